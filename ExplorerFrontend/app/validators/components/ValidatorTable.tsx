@@ -29,6 +29,10 @@ interface Validator {
 interface ValidatorTableProps {
   validators: Validator[];
   loading: boolean;
+  currentPage: number;
+  totalPages: number;
+  totalValidators: number;
+  onPageChange: (page: number) => void;
 }
 
 type SortField = 'index' | 'age' | 'stakedAmount' | 'status';
@@ -41,12 +45,17 @@ const statusOrder: Record<string, number> = {
   slashed: 3,
 };
 
-export default function ValidatorTable({ validators, loading }: ValidatorTableProps) {
+export default function ValidatorTable({
+  validators,
+  loading,
+  currentPage,
+  totalPages,
+  totalValidators,
+  onPageChange,
+}: ValidatorTableProps) {
   const [searchQuery, setSearchQuery] = useState('');
   const [sortField, setSortField] = useState<SortField>('index');
   const [sortDirection, setSortDirection] = useState<SortDirection>('asc');
-  const [currentPage, setCurrentPage] = useState(1);
-  const itemsPerPage = 15;
 
   const handleSort = (field: SortField) => {
     if (sortField === field) {
@@ -102,13 +111,6 @@ export default function ValidatorTable({ validators, loading }: ValidatorTablePr
     return result;
   }, [validators, searchQuery, sortField, sortDirection]);
 
-  const totalPages = Math.ceil(filteredAndSortedValidators.length / itemsPerPage);
-  const startIndex = (currentPage - 1) * itemsPerPage;
-  const currentValidators = filteredAndSortedValidators.slice(
-    startIndex,
-    startIndex + itemsPerPage
-  );
-
   const getStatusBadge = (status: string) => {
     const styles: Record<string, string> = {
       active: 'bg-green-900/30 text-green-400 border-green-800',
@@ -160,16 +162,13 @@ export default function ValidatorTable({ validators, loading }: ValidatorTablePr
         <div className="flex flex-col sm:flex-row gap-2 sm:gap-4">
           <input
             type="text"
-            placeholder="Search validators..."
+            placeholder="Search this page..."
             value={searchQuery}
-            onChange={(e) => {
-              setSearchQuery(e.target.value);
-              setCurrentPage(1);
-            }}
+            onChange={(e) => setSearchQuery(e.target.value)}
             className="flex-1 p-2 text-sm sm:text-base bg-[#1f1f1f] border border-[#3d3d3d] rounded-lg text-gray-300 placeholder-gray-500 focus:outline-none focus:ring-2 focus:ring-[#ffa729] focus:border-transparent"
           />
           <div className="text-xs sm:text-sm text-gray-400 flex items-center">
-            {filteredAndSortedValidators.length} validators
+            {totalValidators.toLocaleString()} validators
           </div>
         </div>
       </div>
@@ -209,7 +208,7 @@ export default function ValidatorTable({ validators, loading }: ValidatorTablePr
             </tr>
           </thead>
           <tbody className="divide-y divide-[#3d3d3d]">
-            {currentValidators.map((validator) => (
+            {filteredAndSortedValidators.map((validator) => (
               <tr
                 key={validator.index}
                 className="hover:bg-[#2d2d2d]/30 cursor-pointer"
@@ -254,7 +253,7 @@ export default function ValidatorTable({ validators, loading }: ValidatorTablePr
       {totalPages > 1 && (
         <div className="p-3 sm:p-4 border-t border-[#3d3d3d] flex flex-wrap justify-center items-center gap-1 sm:gap-2">
           <button
-            onClick={() => setCurrentPage((p) => Math.max(1, p - 1))}
+            onClick={() => onPageChange(Math.max(1, currentPage - 1))}
             disabled={currentPage === 1}
             className="px-2 sm:px-3 py-1 sm:py-1.5 rounded-lg bg-[#1f1f1f] text-gray-300 border border-[#3d3d3d] hover:border-[#ffa729] disabled:opacity-50 disabled:hover:border-[#3d3d3d] text-xs sm:text-sm"
           >
@@ -282,7 +281,7 @@ export default function ValidatorTable({ validators, loading }: ValidatorTablePr
               return (
                 <button
                   key={i}
-                  onClick={() => setCurrentPage(pageNum)}
+                  onClick={() => onPageChange(pageNum)}
                   className={`w-8 h-8 rounded-lg text-sm ${
                     currentPage === pageNum
                       ? 'bg-[#ffa729] text-black'
@@ -296,7 +295,7 @@ export default function ValidatorTable({ validators, loading }: ValidatorTablePr
           </div>
 
           <button
-            onClick={() => setCurrentPage((p) => Math.min(totalPages, p + 1))}
+            onClick={() => onPageChange(Math.min(totalPages, currentPage + 1))}
             disabled={currentPage === totalPages}
             className="px-2 sm:px-3 py-1 sm:py-1.5 rounded-lg bg-[#1f1f1f] text-gray-300 border border-[#3d3d3d] hover:border-[#ffa729] disabled:opacity-50 disabled:hover:border-[#3d3d3d] text-xs sm:text-sm"
           >
@@ -48,6 +48,8 @@ interface HistoryRecord {
   totalStaked: string;
 }
 
+const validatorsPerPage = 50;
+
 // Custom hook for responsive viewport dimensions (SSR-safe)
 function useViewport() {
   const [viewport, setViewport] = useState({
@@ -79,6 +81,8 @@ function useViewport() {
 
 export default function ValidatorsWrapper(): JSX.Element {
   const [validators, setValidators] = useState<Validator[]>([]);
+  const [totalValidators, setTotalValidators] = useState(0);
+  const [page, setPage] = useState(1);
   const [epochInfo, setEpochInfo] = useState<EpochInfo | null>(null);
   const [stats, setStats] = useState<ValidatorStats | null>(null);
   const [history, setHistory] = useState<HistoryRecord[]>([]);
@@ -92,7 +96,7 @@ export default function ValidatorsWrapper(): JSX.Element {
     try {
       // Fetch all data in parallel
       const [validatorsRes, epochRes, statsRes, historyRes] = await Promise.all([
-        axios.get(`${config.handlerUrl}/validators`).catch((err) => { console.error('Failed to fetch validators:', err); return { data: { validators: [] } }; }),
+        axios.get(`${config.handlerUrl}/validators?page=${page}&limit=${validatorsPerPage}`).catch((err) => { console.error('Failed to fetch validators:', err); return { data: { validators: [], totalValidators: 0 } }; }),
         axios.get(`${config.handlerUrl}/epoch`).catch((err) => { console.error('Failed to fetch epoch:', err); return { data: null }; }),
         axios.get(`${config.handlerUrl}/validators/stats`).catch((err) => { console.error('Failed to fetch validator stats:', err); return { data: null }; }),
         axios.get(`${config.handlerUrl}/validators/history?limit=100`).catch((err) => { console.error('Failed to fetch validator history:', err); return { data: { history: [] } }; }),
@@ -105,6 +109,7 @@ export default function ValidatorsWrapper(): JSX.Element {
       }));
 
       setValidators(processedValidators);
+      setTotalValidators(validatorsRes.data.totalValidators || 0);
       setEpochInfo(epochRes.data);
       setStats(statsRes.data);
       setHistory(historyRes.data?.history || []);
@@ -115,7 +120,7 @@ export default function ValidatorsWrapper(): JSX.Element {
     } finally {
       setLoading(false);
     }
-  }, []);
+  }, [page]);
 
   useEffect(() => {
     fetchData();
@@ -222,7 +227,14 @@ export default function ValidatorsWrapper(): JSX.Element {
       {/* Validators Table */}
       <div className="mb-6">
         <h3 className="text-lg font-semibold text-[#ffa729] mb-4">All Validators</h3>
-        <ValidatorTable validators={validators} loading={loading} />
+        <ValidatorTable
+          validators={validators}
+          loading={loading}
+          currentPage={page}
+          totalPages={Math.ceil(totalValidators / validatorsPerPage)}
+          totalValidators={totalValidators}
+          onPageChange={setPage}
+        />
       </div>
     </div>
   );
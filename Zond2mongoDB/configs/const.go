@@ -9,6 +9,14 @@ import (
 
 const QUANTA float64 = 1000000000000000000
 
+// Beacon chain timing
+const (
+	SLOTS_PER_EPOCH  = 128
+	SECONDS_PER_SLOT = 60
+	// MAX_EFFECTIVE_BALANCE is the 40000 QRL stake in gwei; balance above it is swept as a partial withdrawal
+	MAX_EFFECTIVE_BALANCE = 40000000000000
+)
+
 // QRL address constants
 const QRLZeroAddress = "Z0000000000000000000000000000000000000000"
 
@@ -33,6 +41,20 @@ const (
 	EPOCH_INFO_COLLECTION                      = "epoch_info"
 	VALIDATOR_HISTORY_COLLECTION               = "validator_history"
 	PRICE_HISTORY_COLLECTION                   = "priceHistory"
+	VALIDATOR_BALANCES_COLLECTION              = "validator_balances"
+	NETWORK_REWARDS_COLLECTION                 = "network_rewards"
+	SLOTS_COLLECTION                           = "slots"
+	VALIDATOR_ATTESTATIONS_COLLECTION          = "validator_attestations"
+	NETWORK_PARTICIPATION_COLLECTION           = "network_participation"
+	VALIDATOR_EVENTS_COLLECTION                = "validator_events"
+	DEPOSITS_COLLECTION                        = "deposits"
+	FEE_HISTORY_COLLECTION                     = "feeHistory"
+	RECEIPTS_COLLECTION                        = "receipts"
+	NETWORK_STATS_COLLECTION                   = "networkStats"
+	NETWORK_STATS_ADDRESSES_COLLECTION         = "networkStatsAddresses"
+	SUPPLY_COLLECTION                          = "supply"
+	TOTAL_CIRCULATING_SUPPLY_COLLECTION        = "totalCirculatingSupply"
+	VERIFIED_CONTRACTS_COLLECTION              = "verifiedContracts"
 )
 
 // API and configuration constants
@@ -61,6 +83,20 @@ var PendingTransactionsCollections *mongo.Collection = GetCollection(DB, PENDING
 var EpochInfoCollections *mongo.Collection = GetCollection(DB, EPOCH_INFO_COLLECTION)
 var ValidatorHistoryCollections *mongo.Collection = GetCollection(DB, VALIDATOR_HISTORY_COLLECTION)
 var PriceHistoryCollections *mongo.Collection = GetCollection(DB, PRICE_HISTORY_COLLECTION)
+var ValidatorBalancesCollections *mongo.Collection = GetCollection(DB, VALIDATOR_BALANCES_COLLECTION)
+var NetworkRewardsCollections *mongo.Collection = GetCollection(DB, NETWORK_REWARDS_COLLECTION)
+var SlotsCollections *mongo.Collection = GetCollection(DB, SLOTS_COLLECTION)
+var ValidatorAttestationsCollections *mongo.Collection = GetCollection(DB, VALIDATOR_ATTESTATIONS_COLLECTION)
+var NetworkParticipationCollections *mongo.Collection = GetCollection(DB, NETWORK_PARTICIPATION_COLLECTION)
+var ValidatorEventsCollections *mongo.Collection = GetCollection(DB, VALIDATOR_EVENTS_COLLECTION)
+var DepositsCollections *mongo.Collection = GetCollection(DB, DEPOSITS_COLLECTION)
+var FeeHistoryCollections *mongo.Collection = GetCollection(DB, FEE_HISTORY_COLLECTION)
+var ReceiptsCollections *mongo.Collection = GetCollection(DB, RECEIPTS_COLLECTION)
+var NetworkStatsCollections *mongo.Collection = GetCollection(DB, NETWORK_STATS_COLLECTION)
+var NetworkStatsAddressesCollections *mongo.Collection = GetCollection(DB, NETWORK_STATS_ADDRESSES_COLLECTION)
+var SupplyCollections *mongo.Collection = GetCollection(DB, SUPPLY_COLLECTION)
+var TotalCirculatingSupplyCollections *mongo.Collection = GetCollection(DB, TOTAL_CIRCULATING_SUPPLY_COLLECTION)
+var VerifiedContractsCollections *mongo.Collection = GetCollection(DB, VERIFIED_CONTRACTS_COLLECTION)
 
 // Global logger instance - initialized once and used throughout the application
 var Logger *zap.Logger = L.FileLogger(LOG_FILENAME)
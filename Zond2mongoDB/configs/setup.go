@@ -336,19 +336,355 @@ func initializeCollections(db *mongo.Database) {
 		Logger.Info("Price history collection initialized with timestamp index")
 	}
 
-	// Initialize transfer collection with blockTimestamp index for efficient time-range queries (daily volume)
+	// Initialize transfer collection with blockTimestamp index for efficient time-range queries (daily volume),
+	// and the indexes used by the revert reason pass
 	transferCollection := db.Collection("transfer")
-	_, err = transferCollection.Indexes().CreateOne(
+	_, err = transferCollection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "blockTimestamp", Value: -1}}, // Descending for recent-first queries
+				Options: options.Index().SetName("blockTimestamp_desc_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "txHash", Value: 1}},
+				Options: options.Index().SetName("txHash_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "revertPending", Value: 1}},
+				Options: options.Index().SetName("revertPending_idx").SetSparse(true),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for transfer collection", zap.Error(err))
+	} else {
+		Logger.Info("Transfer collection initialized with blockTimestamp, txHash and revertPending indexes")
+	}
+
+	// Verified contract sources, looked up by address
+	_, err = db.Collection("verifiedContracts").Indexes().CreateOne(
 		ctx,
 		mongo.IndexModel{
-			Keys:    bson.D{{Key: "blockTimestamp", Value: -1}}, // Descending for recent-first queries
-			Options: options.Index().SetName("blockTimestamp_desc_idx"),
+			Keys:    bson.D{{Key: "address", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("address_idx"),
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create index for verified contracts collection", zap.Error(err))
+	}
+
+	// Validators are stored one document per validator keyed by index. Drop the
+	// legacy single-document blob so it doesn't get mixed into queries.
+	validatorsCollection := db.Collection("validators")
+	if _, err = validatorsCollection.DeleteOne(ctx, bson.M{"_id": "validators"}); err != nil {
+		Logger.Error("Failed to remove legacy validators document", zap.Error(err))
+	}
+
+	// slotNumber/isLeader were placeholders; proposers are now attributed on blocks
+	_, err = validatorsCollection.UpdateMany(ctx,
+		bson.M{"isLeader": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"isLeader": "", "slotNumber": ""}})
+	if err != nil {
+		Logger.Error("Failed to remove placeholder leader fields from validators", zap.Error(err))
+	}
+
+	_, err = validatorsCollection.Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "publicKeyHex", Value: 1}},
+				Options: options.Index().SetName("publicKeyHex_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("status_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "withdrawalCredentialsHex", Value: 1}},
+				Options: options.Index().SetName("withdrawalCredentialsHex_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "withdrawalAddress", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("withdrawalAddress_idx"),
+			},
+			// Statuses move on when these epochs are reached
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "activationEpochNum", Value: 1}},
+				Options: options.Index().SetName("status_activationEpochNum_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "exitEpochNum", Value: 1}},
+				Options: options.Index().SetName("status_exitEpochNum_idx"),
+			},
 		},
 	)
 	if err != nil {
-		Logger.Error("Failed to create blockTimestamp index for transfer collection", zap.Error(err))
+		Logger.Error("Failed to create indexes for validators collection", zap.Error(err))
 	} else {
-		Logger.Info("Transfer collection initialized with blockTimestamp index")
+		Logger.Info("Validators collection initialized with indexes")
+	}
+
+	// Validator totals per epoch; the API reads the latest one
+	_, err = db.Collection(VALIDATOR_HISTORY_COLLECTION).Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "epoch", Value: 1}},
+				Options: options.Index().SetName("epoch_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "timestamp", Value: -1}},
+				Options: options.Index().SetName("timestamp_desc_idx"),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for validator history collection", zap.Error(err))
+	}
+
+	// Per-validator balance snapshots, one document per validator per epoch
+	_, err = db.Collection("validator_balances").Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "validatorIndex", Value: 1}, {Key: "epoch", Value: -1}},
+				Options: options.Index().SetUnique(true).SetName("validatorIndex_epoch_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "epoch", Value: -1}},
+				Options: options.Index().SetName("epoch_desc_idx"),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for validator balances collection", zap.Error(err))
+	}
+
+	_, err = db.Collection("network_rewards").Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "epoch", Value: -1}},
+			Options: options.Index().SetUnique(true).SetName("epoch_desc_idx"),
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create index for network rewards collection", zap.Error(err))
+	}
+
+	// First snapshots used to store previousEpoch 0, which reads as a snapshot
+	// at genesis; drop it unless there really is one
+	legacy, err := db.Collection("network_rewards").CountDocuments(ctx, bson.M{"previousEpoch": 0})
+	if err == nil && legacy > 0 {
+		genesis, err := db.Collection("network_rewards").CountDocuments(ctx, bson.M{"epoch": 0})
+		if err == nil && genesis == 0 {
+			for _, name := range []string{"network_rewards", "validator_balances"} {
+				if _, err := db.Collection(name).UpdateMany(ctx,
+					bson.M{"previousEpoch": 0},
+					bson.M{"$unset": bson.M{"previousEpoch": ""}}); err != nil {
+					Logger.Error("Failed to clear placeholder previous epochs", zap.String("collection", name), zap.Error(err))
+				}
+			}
+		}
+	}
+
+	// Blocks are attributed to their beacon proposer; index it for validator
+	// pages and for the blocks still waiting for one, which have no proposer
+	// and are scanned in block order
+	_, err = db.Collection("blocks").Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "proposerIndex", Value: 1}, {Key: "slot", Value: -1}},
+				Options: options.Index().SetName("proposerIndex_slot_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "proposerIndex", Value: 1}, {Key: "blockNum", Value: 1}},
+				Options: options.Index().SetName("proposerIndex_blockNum_idx"),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create proposer indexes for blocks collection", zap.Error(err))
+	}
+
+	// Beacon slots, one document per slot keyed by slot number
+	_, err = db.Collection("slots").Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "epoch", Value: 1}},
+				Options: options.Index().SetName("epoch_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("status_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "proposerIndex", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("proposerIndex_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "finalized", Value: 1}, {Key: "_id", Value: 1}},
+				Options: options.Index().SetName("finalized_idx"),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for slots collection", zap.Error(err))
+	}
+
+	// Per-validator attestation results, one document per validator per epoch
+	_, err = db.Collection("validator_attestations").Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "validatorIndex", Value: 1}, {Key: "epoch", Value: -1}},
+				Options: options.Index().SetUnique(true).SetName("validatorIndex_epoch_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "epoch", Value: -1}},
+				Options: options.Index().SetName("epoch_desc_idx"),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for validator attestations collection", zap.Error(err))
+	}
+
+	_, err = db.Collection("network_participation").Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "epoch", Value: -1}},
+			Options: options.Index().SetUnique(true).SetName("epoch_desc_idx"),
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create index for network participation collection", zap.Error(err))
+	}
+
+	// Validator state transitions, one document per validator, type and epoch
+	_, err = db.Collection("validator_events").Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "validatorIndex", Value: 1}, {Key: "type", Value: 1}, {Key: "epoch", Value: 1}},
+				Options: options.Index().SetUnique(true).SetName("validatorIndex_type_epoch_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "validatorIndex", Value: 1}, {Key: "epoch", Value: -1}},
+				Options: options.Index().SetName("validatorIndex_epoch_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "type", Value: 1}, {Key: "epoch", Value: -1}},
+				Options: options.Index().SetName("type_epoch_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "epoch", Value: -1}},
+				Options: options.Index().SetName("epoch_desc_idx"),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for validator events collection", zap.Error(err))
+	}
+
+	// Deposit contract events, keyed by deposit index
+	_, err = db.Collection("deposits").Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "publicKeyHex", Value: 1}},
+				Options: options.Index().SetName("publicKeyHex_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "validatorIndex", Value: 1}},
+				Options: options.Index().SetName("validatorIndex_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "sender", Value: 1}, {Key: "_id", Value: -1}},
+				Options: options.Index().SetName("sender_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "txHash", Value: 1}},
+				Options: options.Index().SetName("txHash_idx"),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for deposits collection", zap.Error(err))
+	}
+
+	// Per-block fee market data; _id is the block number
+	_, err = db.Collection("feeHistory").Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("timestamp_desc_idx"),
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create index for fee history collection", zap.Error(err))
+	}
+
+	// Full transaction receipts; _id is the transaction hash
+	_, err = db.Collection("receipts").Indexes().CreateMany(
+		ctx,
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "blockNumber", Value: 1}},
+				Options: options.Index().SetName("blockNumber_idx"),
+			},
+			{
+				Keys:    bson.D{{Key: "contractAddress", Value: 1}},
+				Options: options.Index().SetName("contractAddress_idx").SetSparse(true),
+			},
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create indexes for receipts collection", zap.Error(err))
+	}
+
+	// Hourly and daily network stats; _id is "<interval>:<bucket start>"
+	_, err = db.Collection("networkStats").Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "interval", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("interval_timestamp_idx"),
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create index for network stats collection", zap.Error(err))
+	}
+
+	// Token transfers are counted per block by the stats aggregator
+	_, err = tokenTransfersCollection.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "blockNumber", Value: 1}},
+			Options: options.Index().SetName("blockNumber_idx"),
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create block index for token transfers collection", zap.Error(err))
+	}
+
+	// Token transfers are paged by chain position per token
+	_, err = tokenTransfersCollection.Indexes().CreateOne(
+		ctx,
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "contractAddress", Value: 1},
+				{Key: "blockNum", Value: -1},
+				{Key: "txIndex", Value: -1},
+				{Key: "logIndex", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("contractAddress_position_desc"),
+		},
+	)
+	if err != nil {
+		Logger.Error("Failed to create position index for token transfers collection", zap.Error(err))
 	}
 
 	// Create and set up the rest of the collections
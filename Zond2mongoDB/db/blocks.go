@@ -374,6 +374,9 @@ func InsertBlockDocument(block models.ZondDatabaseBlock) {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
+	block.BlockNum = utils.HexToInt(block.Result.Number).Int64()
+	block.TimestampNum = utils.HexToInt(block.Result.Timestamp).Int64()
+
 	result, err := configs.BlocksCollections.InsertOne(ctx, block)
 	if err != nil {
 		configs.Logger.Warn("Failed to insert block",
@@ -428,7 +431,9 @@ func InsertManyBlockDocuments(blocks []interface{}) {
 		}
 
 		// Block is unique, add it to our list
-		uniqueBlocks = append(uniqueBlocks, blockInterface)
+		block.BlockNum = utils.HexToInt(blockNumber).Int64()
+		block.TimestampNum = utils.HexToInt(block.Result.Timestamp).Int64()
+		uniqueBlocks = append(uniqueBlocks, block)
 		processedBlockNumbers[blockNumber] = true
 	}
 
@@ -514,6 +519,14 @@ func Rollback(blockNumber string) error {
 		return err
 	}
 
+	// Burnt fees of the removed blocks no longer count towards the total
+	if err := RollbackFeeHistory(utils.HexToInt(blockNumber).Int64()); err != nil {
+		configs.Logger.Error("Failed to roll back fee history",
+			zap.String("block_number", blockNumber),
+			zap.Error(err))
+		return err
+	}
+
 	configs.Logger.Info("Successfully rolled back to block",
 		zap.String("block_number", blockNumber))
 	return nil
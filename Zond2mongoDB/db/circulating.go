@@ -2,86 +2,470 @@ package db
 
 import (
 	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/utils"
 	"context"
+	"fmt"
 	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
-type Address struct {
-	Balance int64              `bson:"balance"`
-	ID      primitive.ObjectID `bson:"_id"`
-}
+// supplyTimeout bounds a supply recomputation, which scans every address and validator
+const supplyTimeout = 5 * time.Minute
+
+const (
+	// recipientRefreshStateID is the sync_state document holding the next block
+	// whose fee and withdrawal recipients get their balances refreshed
+	recipientRefreshStateID = "supply_recipient_refresh"
+	// balanceBackfillStateID is the sync_state document holding the last address
+	// checked by the exact balance backfill
+	balanceBackfillStateID = "supply_balance_backfill"
+	// recipientRefreshBlocks caps the blocks scanned for recipients per run
+	recipientRefreshBlocks = 5000
+	// balanceBackfillBatchSize caps the addresses backfilled per run
+	balanceBackfillBatchSize = 500
+)
 
-func UpdateTotalBalance() {
-	clientOptions := options.Client().ApplyURI("mongodb://localhost:27017")
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel() // Properly cancel the context
+// gweiToWei converts beacon chain gwei amounts to wei
+var gweiToWei = big.NewInt(1000000000)
 
-	client, err := mongo.Connect(ctx, clientOptions)
-	if err != nil {
-		configs.Logger.Error("Failed to connect to MongoDB", zap.Error(err))
-		return
+// addressBalance is the part of an addresses document needed for supply accounting
+type addressBalance struct {
+	ID         string `bson:"id"`
+	BalanceWei string `bson:"balanceWei"`
+	IsContract bool   `bson:"isContract"`
+}
+
+// UpdateTotalBalance recomputes the supply breakdown from the stored address
+// balances, the latest validator balance snapshot and the burnt fee total,
+// and stores it in the supply collection. Amounts are summed as big integers
+// in wei. Fee and withdrawal recipients, whose balances change without a
+// transaction, are refreshed from the node first, and a batch of addresses
+// stored before exact balances were kept is backfilled. Addresses still
+// without an exact balance are counted in MissingBalances. The circulating
+// amount is also written, in QRL, to totalCirculatingSupply for the overview.
+func UpdateTotalBalance() error {
+	if err := refreshRecipientBalances(); err != nil {
+		configs.Logger.Warn("Failed to refresh fee and withdrawal recipient balances", zap.Error(err))
+	}
+	if err := backfillBalanceWei(); err != nil {
+		configs.Logger.Warn("Failed to backfill exact address balances", zap.Error(err))
 	}
-	defer func() {
-		if err := client.Disconnect(ctx); err != nil {
-			configs.Logger.Error("Failed to disconnect from MongoDB", zap.Error(err))
-		}
-	}()
 
-	// Check the connection
-	if err = client.Ping(ctx, nil); err != nil {
-		configs.Logger.Error("Failed to ping MongoDB", zap.Error(err))
-		return
+	ctx, cancel := context.WithTimeout(context.Background(), supplyTimeout)
+	defer cancel()
+
+	// Deposits stay in the deposit contract and are counted as staked instead
+	depositContract, err := rpc.GetDepositContractAddress()
+	if err != nil {
+		configs.Logger.Warn("Deposit contract unknown, its balance is counted as locked", zap.Error(err))
 	}
+	depositContract = strings.ToLower(depositContract)
 
-	destCollection := client.Database("qrldata-z").Collection("totalCirculatingSupply")
+	execution := new(big.Int)
+	locked := new(big.Int)
+	var addressCount, missingBalances int64
 
-	// Get initial total balance
-	total := big.NewInt(0)
-	cursor, err := configs.AddressesCollections.Find(ctx, primitive.D{})
+	cursor, err := configs.AddressesCollections.Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"id": 1, "balanceWei": 1, "isContract": 1}))
 	if err != nil {
 		configs.Logger.Error("Failed to query addresses", zap.Error(err))
-		return
+		return err
 	}
-	defer cursor.Close(ctx)
-
 	for cursor.Next(ctx) {
-		var address Address
+		var address addressBalance
 		if err := cursor.Decode(&address); err != nil {
 			configs.Logger.Error("Failed to decode address", zap.Error(err))
-			continue // Skip this address but continue processing others
+			continue
 		}
+		if address.ID == depositContract {
+			continue
+		}
+		balance, ok := new(big.Int).SetString(address.BalanceWei, 10)
+		if !ok {
+			missingBalances++
+			continue
+		}
+		execution.Add(execution, balance)
+		if address.IsContract {
+			locked.Add(locked, balance)
+		}
+		addressCount++
+	}
+	err = cursor.Err()
+	cursor.Close(ctx)
+	if err != nil {
+		configs.Logger.Error("Cursor iteration error", zap.Error(err))
+		return err
+	}
 
-		balanceBigInt := new(big.Int)
-		balanceBigInt.SetInt64(address.Balance)
-		total.Add(total, balanceBigInt)
+	staked, pending, epoch, err := validatorSupply(ctx)
+	if err != nil {
+		return err
 	}
 
-	if err := cursor.Err(); err != nil {
-		configs.Logger.Error("Cursor iteration error", zap.Error(err))
-		return
+	burnt, err := GetFeeHistoryCheckpoint()
+	if err != nil {
+		return err
+	}
+	burntFees, ok := new(big.Int).SetString(burnt.TotalBurnt, 10)
+	if !ok {
+		burntFees = new(big.Int)
 	}
 
-	// Upsert the total balance
-	filter := primitive.D{{Key: "_id", Value: "totalBalance"}}
-	update := primitive.D{
-		{Key: "$set", Value: primitive.D{
-			{Key: "circulating", Value: total.String()},
-		}},
+	current := new(big.Int).Add(execution, staked)
+	circulating := new(big.Int).Sub(execution, locked)
+	supply := models.SupplyRecord{
+		ID:                "current",
+		TotalIssued:       new(big.Int).Add(current, burntFees).String(),
+		CurrentSupply:     current.String(),
+		Circulating:       circulating.String(),
+		ExecutionBalances: execution.String(),
+		LockedInContracts: locked.String(),
+		Staked:            staked.String(),
+		WithdrawalPending: pending.String(),
+		BurntFees:         burntFees.String(),
+		AddressCount:      addressCount,
+		MissingBalances:   missingBalances,
+		ValidatorEpoch:    epoch,
+		BurntFeesBlock:    burnt.Block,
+		UpdatedAt:         time.Now().Unix(),
 	}
 
-	opts := options.Update().SetUpsert(true)
-	_, err = destCollection.UpdateOne(ctx, filter, update, opts)
+	_, err = configs.SupplyCollections.ReplaceOne(ctx, bson.M{"_id": supply.ID}, supply, options.Replace().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store supply", zap.Error(err))
+		return err
+	}
+
+	// The overview shows whole QRL
+	circulatingQRL := new(big.Int).Quo(circulating, big.NewInt(int64(configs.QUANTA)))
+	_, err = configs.TotalCirculatingSupplyCollections.UpdateOne(ctx,
+		bson.M{"_id": "totalBalance"},
+		bson.M{"$set": bson.M{"circulating": circulatingQRL.String()}},
+		options.Update().SetUpsert(true))
 	if err != nil {
 		configs.Logger.Error("Failed to update total balance", zap.Error(err))
-		return
+		return err
+	}
+
+	if missingBalances > 0 {
+		configs.Logger.Warn("Supply excludes addresses without an exact balance",
+			zap.Int64("addresses", missingBalances))
+	}
+	configs.Logger.Info("Successfully updated supply",
+		zap.String("currentSupply", supply.CurrentSupply),
+		zap.String("circulating", supply.Circulating),
+		zap.String("staked", supply.Staked))
+	return nil
+}
+
+// refreshRecipientBalances refreshes the balances of the fee recipients and
+// withdrawal addresses of the blocks stored since the last run. Their balances
+// change without a transaction, so block processing does not update them. The
+// checkpoint only advances once every recipient of the range was refreshed.
+func refreshRecipientBalances() error {
+	from, err := getSupplyBlockCheckpoint(recipientRefreshStateID)
+	if err != nil {
+		return err
+	}
+	latest := utils.HexToInt(GetLastKnownBlockNumber()).Int64()
+	to := min(latest, from+recipientRefreshBlocks-1)
+	if to < from {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	cursor, err := configs.BlocksCollections.Find(ctx,
+		bson.M{"blockNum": bson.M{"$gte": from, "$lte": to}},
+		options.Find().SetProjection(bson.M{"result.miner": 1, "result.withdrawals.address": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query blocks for recipients", zap.Error(err))
+		return err
+	}
+	var blocks []models.ZondDatabaseBlock
+	if err := cursor.All(ctx, &blocks); err != nil {
+		return err
+	}
+
+	recipients := make(map[string]bool)
+	for _, block := range blocks {
+		if block.Result.Miner != "" {
+			recipients[strings.ToLower(block.Result.Miner)] = true
+		}
+		for _, w := range block.Result.Withdrawals {
+			if w.Address != "" {
+				recipients[strings.ToLower(w.Address)] = true
+			}
+		}
+	}
+
+	failed := 0
+	for address := range recipients {
+		balance, err := fetchBalanceWei(address)
+		if err != nil {
+			configs.Logger.Warn("Failed to refresh recipient balance",
+				zap.String("address", address),
+				zap.Error(err))
+			failed++
+			continue
+		}
+		if _, err := UpsertTransactions(address, weiToQRL(balance), balance.String(), false); err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d recipients of blocks %d-%d not refreshed", failed, len(recipients), from, to)
+	}
+
+	if len(recipients) > 0 {
+		configs.Logger.Info("Refreshed fee and withdrawal recipient balances",
+			zap.Int("addresses", len(recipients)),
+			zap.Int64("fromBlock", from),
+			zap.Int64("toBlock", to))
+	}
+	return storeSupplyCheckpoint(recipientRefreshStateID, bson.M{"block": to + 1})
+}
+
+// backfillBalanceWei fetches the exact balance of a batch of addresses stored
+// with only the float QRL balance. Each run resumes after the last address of
+// the previous one and wraps around at the end, so addresses whose balance
+// could not be fetched are retried on a later pass.
+func backfillBalanceWei() error {
+	after, err := getBalanceBackfillCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"balanceWei": bson.M{"$exists": false}}
+	if after != "" {
+		filter["id"] = bson.M{"$gt": after}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	cursor, err := configs.AddressesCollections.Find(ctx, filter,
+		options.Find().
+			SetProjection(bson.M{"id": 1}).
+			SetSort(bson.D{{Key: "id", Value: 1}}).
+			SetLimit(balanceBackfillBatchSize))
+	if err != nil {
+		configs.Logger.Error("Failed to query addresses without exact balance", zap.Error(err))
+		return err
+	}
+	var addresses []addressBalance
+	if err := cursor.All(ctx, &addresses); err != nil {
+		configs.Logger.Error("Failed to decode addresses without exact balance", zap.Error(err))
+		return err
+	}
+
+	stored := 0
+	for _, address := range addresses {
+		balance, err := fetchBalanceWei(address.ID)
+		if err != nil {
+			configs.Logger.Warn("Failed to get balance for supply",
+				zap.String("address", address.ID),
+				zap.Error(err))
+			continue
+		}
+		updateCtx, updateCancel := context.WithTimeout(context.Background(), dbTimeout)
+		_, err = configs.AddressesCollections.UpdateOne(updateCtx,
+			bson.M{"id": address.ID},
+			bson.M{"$set": bson.M{"balanceWei": balance.String()}})
+		updateCancel()
+		if err != nil {
+			configs.Logger.Warn("Failed to store exact balance",
+				zap.String("address", address.ID),
+				zap.Error(err))
+			continue
+		}
+		stored++
+	}
+
+	next := ""
+	if len(addresses) == balanceBackfillBatchSize {
+		next = addresses[len(addresses)-1].ID
+	}
+	if len(addresses) > 0 {
+		configs.Logger.Info("Backfilled exact address balances",
+			zap.Int("stored", stored),
+			zap.Int("checked", len(addresses)))
+	}
+	return storeSupplyCheckpoint(balanceBackfillStateID, bson.M{"address": next})
+}
+
+// fetchBalanceWei returns the current balance of an address from the node
+func fetchBalanceWei(address string) (*big.Int, error) {
+	response, err := rpc.GetBalance(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(response) <= 2 {
+		return nil, fmt.Errorf("invalid balance response %q", response)
+	}
+	balance, ok := new(big.Int).SetString(response[2:], 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance response %q", response)
+	}
+	return balance, nil
+}
+
+// weiToQRL converts a wei amount to the float QRL balance kept on addresses
+func weiToQRL(wei *big.Int) float64 {
+	qrl, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), new(big.Float).SetFloat64(float64(configs.QUANTA))).Float64()
+	return qrl
+}
+
+// getSupplyBlockCheckpoint returns the block stored in a supply sync_state
+// document, or 0 if there is none
+func getSupplyBlockCheckpoint(id string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var state struct {
+		Block int64 `bson:"block"`
+	}
+	err := configs.GetCollection(configs.DB, SyncStateCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		configs.Logger.Error("Failed to get supply sync state", zap.String("id", id), zap.Error(err))
+		return 0, err
+	}
+	return state.Block, nil
+}
+
+// getBalanceBackfillCheckpoint returns the last address checked by the exact
+// balance backfill, or an empty string to start from the beginning
+func getBalanceBackfillCheckpoint() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var state struct {
+		Address string `bson:"address"`
+	}
+	err := configs.GetCollection(configs.DB, SyncStateCollection).FindOne(ctx, bson.M{"_id": balanceBackfillStateID}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		configs.Logger.Error("Failed to get balance backfill sync state", zap.Error(err))
+		return "", err
+	}
+	return state.Address, nil
+}
+
+// storeSupplyCheckpoint records the progress of a supply sync_state document
+func storeSupplyCheckpoint(id string, progress bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": progress},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store supply sync state", zap.String("id", id), zap.Error(err))
+	}
+	return err
+}
+
+// validatorSupply sums the validator balances of the latest snapshot, in wei.
+// The pending part is what the withdrawal sweep still has to pay out to
+// validators with a withdrawal address: the full balance once withdrawable,
+// otherwise the excess above the maximum effective balance.
+func validatorSupply(ctx context.Context) (staked *big.Int, pending *big.Int, epoch int64, err error) {
+	staked, pending = new(big.Int), new(big.Int)
+
+	var latest models.NetworkRewardsRecord
+	err = configs.NetworkRewardsCollections.FindOne(ctx, bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "epoch", Value: -1}})).Decode(&latest)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return staked, pending, 0, nil
+		}
+		configs.Logger.Error("Failed to get latest balance snapshot", zap.Error(err))
+		return nil, nil, 0, err
+	}
+	if total, ok := new(big.Int).SetString(latest.TotalBalance, 10); ok {
+		staked.Mul(total, gweiToWei)
+	}
+
+	var headEpoch uint64
+	if info, err := GetEpochInfo(); err == nil {
+		headEpoch, _ = strconv.ParseUint(info.HeadEpoch, 10, 64)
+	}
+
+	type withdrawalState struct {
+		withdrawable bool
+		hasAddress   bool
+	}
+	validators := make(map[int64]withdrawalState)
+	cursor, err := configs.ValidatorsCollections.Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"withdrawalAddress": 1, "withdrawableEpoch": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query validators for supply", zap.Error(err))
+		return nil, nil, 0, err
+	}
+	for cursor.Next(ctx) {
+		var v models.ValidatorRecord
+		if err := cursor.Decode(&v); err != nil {
+			continue
+		}
+		withdrawableEpoch, err := strconv.ParseUint(v.WithdrawableEpoch, 10, 64)
+		validators[v.ID] = withdrawalState{
+			withdrawable: err == nil && withdrawableEpoch <= headEpoch,
+			hasAddress:   v.WithdrawalAddress != "",
+		}
+	}
+	cursor.Close(ctx)
+
+	cursor, err = configs.ValidatorBalancesCollections.Find(ctx,
+		bson.M{"epoch": latest.Epoch},
+		options.Find().SetProjection(bson.M{"validatorIndex": 1, "balance": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query validator balances for supply", zap.Error(err))
+		return nil, nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	pendingGwei := new(big.Int)
+	for cursor.Next(ctx) {
+		var snapshot models.ValidatorBalanceSnapshot
+		if err := cursor.Decode(&snapshot); err != nil {
+			continue
+		}
+		balance, err := strconv.ParseInt(snapshot.Balance, 10, 64)
+		if err != nil {
+			continue
+		}
+		// Only execution withdrawal credentials can be swept
+		state := validators[snapshot.ValidatorIndex]
+		if !state.hasAddress {
+			continue
+		}
+		switch {
+		case state.withdrawable:
+			pendingGwei.Add(pendingGwei, big.NewInt(balance))
+		case balance > configs.MAX_EFFECTIVE_BALANCE:
+			pendingGwei.Add(pendingGwei, big.NewInt(balance-configs.MAX_EFFECTIVE_BALANCE))
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		configs.Logger.Error("Cursor iteration error", zap.Error(err))
+		return nil, nil, 0, err
 	}
 
-	configs.Logger.Info("Successfully updated total circulating supply",
-		zap.String("total", total.String()))
+	pending.Mul(pendingGwei, gweiToWei)
+	return staked, pending, latest.Epoch, nil
 }
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// depositSyncStateID is the sync_state document holding the last block scanned for deposits
+const depositSyncStateID = "last_deposit_block"
+
+// GetLastDepositBlock returns the last block scanned for deposit events, or -1 if none
+func GetLastDepositBlock() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var state struct {
+		Block int64 `bson:"block"`
+	}
+	err := configs.GetCollection(configs.DB, SyncStateCollection).FindOne(ctx, bson.M{"_id": depositSyncStateID}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return -1, nil
+		}
+		configs.Logger.Error("Failed to get deposit sync state", zap.Error(err))
+		return -1, err
+	}
+	return state.Block, nil
+}
+
+// StoreLastDepositBlock records the last block scanned for deposit events
+func StoreLastDepositBlock(block int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+		bson.M{"_id": depositSyncStateID},
+		bson.M{"$set": bson.M{"block": block}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store deposit sync state", zap.Error(err))
+	}
+	return err
+}
+
+// UpsertDeposits stores deposit records keyed by deposit index
+func UpsertDeposits(deposits []models.DepositRecord) error {
+	if len(deposits) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	operations := make([]mongo.WriteModel, 0, len(deposits))
+	for _, d := range deposits {
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": d.ID}).
+			SetUpdate(bson.M{"$set": d}).
+			SetUpsert(true))
+	}
+
+	_, err := configs.DepositsCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		configs.Logger.Error("Failed to upsert deposits", zap.Error(err))
+	}
+	return err
+}
+
+// LinkDepositsToValidators sets the validator index on deposits whose public
+// key has since appeared in the validators collection
+func LinkDepositsToValidators() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	pubkeys, err := configs.DepositsCollections.Distinct(ctx, "publicKeyHex",
+		bson.M{"validatorIndex": bson.M{"$exists": false}})
+	if err != nil {
+		configs.Logger.Error("Failed to query unlinked deposits", zap.Error(err))
+		return 0, err
+	}
+	if len(pubkeys) == 0 {
+		return 0, nil
+	}
+
+	cursor, err := configs.GetValidatorCollection().Find(ctx,
+		bson.M{"publicKeyHex": bson.M{"$in": pubkeys}},
+		options.Find().SetProjection(bson.M{"publicKeyHex": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query validators for deposits", zap.Error(err))
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var operations []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var v models.ValidatorRecord
+		if err := cursor.Decode(&v); err != nil {
+			continue
+		}
+		operations = append(operations, mongo.NewUpdateManyModel().
+			SetFilter(bson.M{"publicKeyHex": v.PublicKeyHex, "validatorIndex": bson.M{"$exists": false}}).
+			SetUpdate(bson.M{"$set": bson.M{"validatorIndex": v.ID}}))
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if len(operations) == 0 {
+		return 0, nil
+	}
+
+	_, err = configs.DepositsCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		configs.Logger.Error("Failed to link deposits to validators", zap.Error(err))
+		return 0, err
+	}
+	return len(operations), nil
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/utils"
+	"context"
+	"fmt"
+	"math/big"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// feeHistorySyncStateID is the sync_state document holding the last block with fee history
+const feeHistorySyncStateID = "last_fee_history_block"
+
+// FeeHistoryCheckpoint is the fee history progress stored in sync_state: the
+// last block stored in feeHistory and the base fees burnt up to and including it
+type FeeHistoryCheckpoint struct {
+	Block      int64  `bson:"block"`
+	TotalBurnt string `bson:"totalBurnt"` // Decimal string in wei
+}
+
+// GetFeeHistoryCheckpoint returns the fee history progress. Without a stored
+// checkpoint, or one recorded before burnt totals were tracked, it starts over
+// from block -1 so the network total covers every block.
+func GetFeeHistoryCheckpoint() (FeeHistoryCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	start := FeeHistoryCheckpoint{Block: -1, TotalBurnt: "0"}
+	var state FeeHistoryCheckpoint
+	err := configs.GetCollection(configs.DB, SyncStateCollection).FindOne(ctx, bson.M{"_id": feeHistorySyncStateID}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return start, nil
+		}
+		configs.Logger.Error("Failed to get fee history sync state", zap.Error(err))
+		return start, err
+	}
+	if state.TotalBurnt == "" {
+		return start, nil
+	}
+	return state, nil
+}
+
+// StoreFeeHistoryCheckpoint advances the fee history progress from previous to
+// checkpoint. It fails if the stored progress no longer matches previous, which
+// happens when a rollback moved it back while the batch was being synced.
+func StoreFeeHistoryCheckpoint(previous, checkpoint FeeHistoryCheckpoint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	filter := bson.M{
+		"_id": feeHistorySyncStateID,
+		"$or": []bson.M{
+			{"block": previous.Block, "totalBurnt": previous.TotalBurnt},
+			// Checkpoints recorded before burnt totals were tracked start over
+			{"totalBurnt": bson.M{"$exists": false}},
+		},
+	}
+	_, err := configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+		filter,
+		bson.M{"$set": bson.M{"block": checkpoint.Block, "totalBurnt": checkpoint.TotalBurnt}},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("fee history checkpoint moved from block %d while syncing", previous.Block)
+	}
+	if err != nil {
+		configs.Logger.Error("Failed to store fee history sync state", zap.Error(err))
+	}
+	return err
+}
+
+// RollbackFeeHistory removes the fee history of blocks after the given block
+// and takes their burnt fees out of the running total
+func RollbackFeeHistory(block int64) error {
+	checkpoint, err := GetFeeHistoryCheckpoint()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if checkpoint.Block > block {
+		totalBurnt, ok := new(big.Int).SetString(checkpoint.TotalBurnt, 10)
+		if !ok {
+			return fmt.Errorf("invalid burnt fee total %q in fee history checkpoint", checkpoint.TotalBurnt)
+		}
+
+		cursor, err := configs.FeeHistoryCollections.Find(ctx,
+			bson.M{"_id": bson.M{"$gt": block, "$lte": checkpoint.Block}},
+			options.Find().SetProjection(bson.M{"burntFee": 1}))
+		if err != nil {
+			configs.Logger.Error("Failed to query fee history for rollback", zap.Error(err))
+			return err
+		}
+		var records []models.FeeHistoryRecord
+		if err := cursor.All(ctx, &records); err != nil {
+			return err
+		}
+		for _, r := range records {
+			if burnt, ok := new(big.Int).SetString(r.BurntFee, 10); ok {
+				totalBurnt.Sub(totalBurnt, burnt)
+			}
+		}
+
+		_, err = configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+			bson.M{"_id": feeHistorySyncStateID},
+			bson.M{"$set": bson.M{"block": block, "totalBurnt": totalBurnt.String()}})
+		if err != nil {
+			configs.Logger.Error("Failed to roll back fee history sync state", zap.Error(err))
+			return err
+		}
+	}
+
+	if _, err := configs.FeeHistoryCollections.DeleteMany(ctx, bson.M{"_id": bson.M{"$gt": block}}); err != nil {
+		configs.Logger.Error("Failed to delete rolled back fee history", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UpsertFeeHistory stores fee history records keyed by block number
+func UpsertFeeHistory(records []models.FeeHistoryRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	operations := make([]mongo.WriteModel, 0, len(records))
+	for _, r := range records {
+		operations = append(operations, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": r.ID}).
+			SetReplacement(r).
+			SetUpsert(true))
+	}
+
+	_, err := configs.FeeHistoryCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		configs.Logger.Error("Failed to upsert fee history", zap.Error(err))
+	}
+	return err
+}
+
+// StoredBlockInfo is the part of a stored block needed alongside its fee history
+type StoredBlockInfo struct {
+	Timestamp     int64
+	GasUsed       *big.Int
+	BaseFeePerGas *big.Int
+}
+
+// GetStoredBlockInfo returns the timestamp, gas used and base fee of the given
+// stored blocks, keyed by hex block number
+func GetStoredBlockInfo(blockNumbers []string) (map[string]StoredBlockInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	cursor, err := configs.BlocksCollections.Find(ctx,
+		bson.M{"result.number": bson.M{"$in": blockNumbers}},
+		// Blocks are stored without bson tags, so multi-word fields are lowercased
+		options.Find().SetProjection(bson.M{"result.number": 1, "result.timestamp": 1, "result.gasused": 1, "result.basefeepergas": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query block info", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blocks := make(map[string]StoredBlockInfo, len(blockNumbers))
+	for cursor.Next(ctx) {
+		var block models.ZondDatabaseBlock
+		if err := cursor.Decode(&block); err != nil {
+			continue
+		}
+		blocks[block.Result.Number] = StoredBlockInfo{
+			Timestamp:     utils.HexToInt(block.Result.Timestamp).Int64(),
+			GasUsed:       utils.HexToInt(block.Result.GasUsed),
+			BaseFeePerGas: utils.HexToInt(block.Result.BaseFeePerGas),
+		}
+	}
+	return blocks, cursor.Err()
+}
@@ -0,0 +1,347 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// networkStatsSyncStateID is the sync_state document tracking the stats aggregator
+const networkStatsSyncStateID = "network_stats"
+
+// NetworkStatsCheckpoint is the last block folded into networkStats and its
+// timestamp, needed for the block time of the next block. Pending holds a
+// range whose deltas were computed but may not all be stored yet.
+type NetworkStatsCheckpoint struct {
+	Block     int64              `bson:"block"`
+	Timestamp int64              `bson:"timestamp"`
+	Pending   *NetworkStatsRange `bson:"pending,omitempty"`
+}
+
+// NetworkStatsRange is the bucket deltas of the blocks From to To. Next is the
+// checkpoint to store once the deltas are merged.
+type NetworkStatsRange struct {
+	From    int64                       `bson:"from"`
+	To      int64                       `bson:"to"`
+	Next    NetworkStatsCheckpoint      `bson:"next"`
+	Records []models.NetworkStatsRecord `bson:"records"`
+}
+
+// ID identifies the range in the appliedRanges of the buckets it was merged into
+func (r NetworkStatsRange) ID() string {
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// AddressActivity is a networkStatsAddresses document: the latest bucket an
+// address was active in per interval and the hourly bucket it was first seen in
+type AddressActivity struct {
+	Address    string           `bson:"_id"`
+	LastActive map[string]int64 `bson:"lastActive"`
+	FirstSeen  int64            `bson:"firstSeen"`
+}
+
+// GetNetworkStatsCheckpoint returns the aggregator progress, or block -1 if none
+func GetNetworkStatsCheckpoint() (NetworkStatsCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	checkpoint := NetworkStatsCheckpoint{Block: -1}
+	err := configs.GetCollection(configs.DB, SyncStateCollection).FindOne(ctx, bson.M{"_id": networkStatsSyncStateID}).Decode(&checkpoint)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return NetworkStatsCheckpoint{Block: -1}, nil
+		}
+		configs.Logger.Error("Failed to get network stats sync state", zap.Error(err))
+		return checkpoint, err
+	}
+	return checkpoint, nil
+}
+
+// StoreNetworkStatsPending records the deltas of a range before any of them
+// are merged, so an interrupted merge is finished with the same deltas
+func StoreNetworkStatsPending(pending NetworkStatsRange) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+		bson.M{"_id": networkStatsSyncStateID},
+		bson.M{"$set": bson.M{"pending": pending}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store pending network stats", zap.Error(err))
+	}
+	return err
+}
+
+// StoreNetworkStatsCheckpoint records the aggregator progress and clears the
+// pending range in the same write
+func StoreNetworkStatsCheckpoint(checkpoint NetworkStatsCheckpoint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+		bson.M{"_id": networkStatsSyncStateID},
+		bson.M{
+			"$set":   bson.M{"block": checkpoint.Block, "timestamp": checkpoint.Timestamp},
+			"$unset": bson.M{"pending": ""},
+		},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store network stats sync state", zap.Error(err))
+	}
+	return err
+}
+
+// GetStoredBlocks returns the stored blocks with the given hex numbers, keyed by number
+func GetStoredBlocks(blockNumbers []string) (map[string]models.ZondDatabaseBlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := configs.BlocksCollections.Find(ctx,
+		bson.M{"result.number": bson.M{"$in": blockNumbers}},
+		// Blocks are stored without bson tags, so multi-word fields are lowercased
+		options.Find().SetProjection(bson.M{
+			"result.number":            1,
+			"result.timestamp":         1,
+			"result.gasused":           1,
+			"result.basefeepergas":     1,
+			"result.transactions.hash": 1,
+			"result.transactions.from": 1,
+			"result.transactions.to":   1,
+		}))
+	if err != nil {
+		configs.Logger.Error("Failed to query blocks for network stats", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blocks := make(map[string]models.ZondDatabaseBlock, len(blockNumbers))
+	for cursor.Next(ctx) {
+		var block models.ZondDatabaseBlock
+		if err := cursor.Decode(&block); err != nil {
+			continue
+		}
+		blocks[block.Result.Number] = block
+	}
+	return blocks, cursor.Err()
+}
+
+// GetReceiptFeesByBlock sums gasUsed * effectiveGasPrice of the stored receipts
+// of the given blocks, returning the total fee and receipt count per block
+func GetReceiptFeesByBlock(blockNumbers []string) (map[string]*big.Int, map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := configs.ReceiptsCollections.Find(ctx,
+		bson.M{"blockNumber": bson.M{"$in": blockNumbers}},
+		options.Find().SetProjection(bson.M{"blockNumber": 1, "gasUsed": 1, "effectiveGasPrice": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query receipts for network stats", zap.Error(err))
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	fees := make(map[string]*big.Int)
+	counts := make(map[string]int64)
+	for cursor.Next(ctx) {
+		var receipt models.ReceiptRecord
+		if err := cursor.Decode(&receipt); err != nil {
+			continue
+		}
+		fee := new(big.Int).Mul(utils.HexToInt(receipt.GasUsed), utils.HexToInt(receipt.EffectiveGasPrice))
+		if fees[receipt.BlockNumber] == nil {
+			fees[receipt.BlockNumber] = new(big.Int)
+		}
+		fees[receipt.BlockNumber].Add(fees[receipt.BlockNumber], fee)
+		counts[receipt.BlockNumber]++
+	}
+	return fees, counts, cursor.Err()
+}
+
+// CountTokenTransfersByBlock counts the stored token transfers of the given blocks
+func CountTokenTransfersByBlock(blockNumbers []string) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"blockNumber": bson.M{"$in": blockNumbers}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$blockNumber", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := configs.GetTokenTransfersCollection().Aggregate(ctx, pipeline)
+	if err != nil {
+		configs.Logger.Error("Failed to count token transfers for network stats", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	counts := make(map[string]int64)
+	for cursor.Next(ctx) {
+		var row struct {
+			BlockNumber string `bson:"_id"`
+			Count       int64  `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			continue
+		}
+		counts[row.BlockNumber] = row.Count
+	}
+	return counts, cursor.Err()
+}
+
+// GetAddressActivity returns the stored activity of the given addresses, keyed
+// by address. Addresses never seen are absent.
+func GetAddressActivity(addresses []string) (map[string]AddressActivity, error) {
+	activity := make(map[string]AddressActivity, len(addresses))
+	if len(addresses) == 0 {
+		return activity, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := configs.NetworkStatsAddressesCollections.Find(ctx, bson.M{"_id": bson.M{"$in": addresses}})
+	if err != nil {
+		configs.Logger.Error("Failed to query address activity", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var a AddressActivity
+		if err := cursor.Decode(&a); err == nil {
+			activity[a.Address] = a
+		}
+	}
+	return activity, cursor.Err()
+}
+
+// MarkActiveAddresses records the latest bucket each address was active in per
+// interval. $max and $setOnInsert make repeating it harmless.
+func MarkActiveAddresses(activity []AddressActivity) error {
+	if len(activity) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	operations := make([]mongo.WriteModel, 0, len(activity))
+	for _, a := range activity {
+		lastActive := bson.M{}
+		for interval, bucket := range a.LastActive {
+			lastActive["lastActive."+interval] = bucket
+		}
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": a.Address}).
+			SetUpdate(bson.M{
+				"$max":         lastActive,
+				"$setOnInsert": bson.M{"firstSeen": a.FirstSeen},
+			}).
+			SetUpsert(true))
+	}
+
+	if _, err := configs.NetworkStatsAddressesCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false)); err != nil {
+		configs.Logger.Error("Failed to mark active addresses", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// MergeNetworkStats adds the deltas of a range to the stored buckets. Each
+// bucket lists the ranges merged into it and the update only matches buckets
+// without rangeID, so a range is added at most once; a repeated upsert fails
+// with a duplicate key and is skipped. Fee totals are decimal strings and are
+// added as decimals in the update pipeline.
+func MergeNetworkStats(rangeID string, deltas []models.NetworkStatsRecord) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	add := func(field string, delta int64) bson.M {
+		return bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$" + field, 0}}, delta}}
+	}
+	addDecimal := func(field string, delta string) (bson.M, error) {
+		value, err := primitive.ParseDecimal128(delta)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s delta %q: %v", field, delta, err)
+		}
+		return bson.M{"$toString": bson.M{"$add": bson.A{
+			bson.M{"$toDecimal": bson.M{"$ifNull": bson.A{"$" + field, "0"}}},
+			value,
+		}}}, nil
+	}
+
+	now := time.Now().Unix()
+	operations := make([]mongo.WriteModel, 0, len(deltas))
+	for _, d := range deltas {
+		totalFees, err := addDecimal("totalFees", d.TotalFees)
+		if err != nil {
+			return err
+		}
+		burntFees, err := addDecimal("burntFees", d.BurntFees)
+		if err != nil {
+			return err
+		}
+
+		update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"interval":            d.Interval,
+			"timestamp":           d.Timestamp,
+			"blockCount":          add("blockCount", d.BlockCount),
+			"blockTimeSum":        add("blockTimeSum", d.BlockTimeSum),
+			"txCount":             add("txCount", d.TxCount),
+			"activeAddresses":     add("activeAddresses", d.ActiveAddresses),
+			"newAddresses":        add("newAddresses", d.NewAddresses),
+			"gasUsed":             add("gasUsed", d.GasUsed),
+			"feeTxCount":          add("feeTxCount", d.FeeTxCount),
+			"contractDeployments": add("contractDeployments", d.ContractDeployments),
+			"tokenTransfers":      add("tokenTransfers", d.TokenTransfers),
+			"totalFees":           totalFees,
+			"burntFees":           burntFees,
+			"appliedRanges": bson.M{"$concatArrays": bson.A{
+				bson.M{"$ifNull": bson.A{"$appliedRanges", bson.A{}}},
+				bson.A{rangeID},
+			}},
+			"updatedAt": now,
+		}}}}
+
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": d.ID, "appliedRanges": bson.M{"$ne": rangeID}}).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+
+	_, err := configs.NetworkStatsCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	if err != nil && !onlyDuplicateKeys(err) {
+		configs.Logger.Error("Failed to store network stats", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// onlyDuplicateKeys reports whether every error of a bulk write is a duplicate key
+func onlyDuplicateKeys(err error) bool {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
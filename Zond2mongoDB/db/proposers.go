@@ -0,0 +1,186 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// proposerSyncStateID is the sync_state document holding the next block to attribute
+const proposerSyncStateID = "proposer_attribution"
+
+// GetProposerCheckpoint returns the lowest block number not yet scanned for
+// its proposer, or 0 if attribution has not started
+func GetProposerCheckpoint() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var state struct {
+		Block int64 `bson:"block"`
+	}
+	err := configs.GetCollection(configs.DB, SyncStateCollection).FindOne(ctx, bson.M{"_id": proposerSyncStateID}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, nil
+		}
+		configs.Logger.Error("Failed to get proposer sync state", zap.Error(err))
+		return 0, err
+	}
+	return state.Block, nil
+}
+
+// StoreProposerCheckpoint records the lowest block number not yet scanned for its proposer
+func StoreProposerCheckpoint(block int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+		bson.M{"_id": proposerSyncStateID},
+		bson.M{"$set": bson.M{"block": block}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store proposer sync state", zap.Error(err))
+	}
+	return err
+}
+
+// ResetEmptySlotProposers clears the null proposers stored for empty slots
+// before emptiness had to be final, so those blocks are checked again
+func ResetEmptySlotProposers() error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	result, err := configs.BlocksCollections.UpdateMany(ctx,
+		bson.M{
+			"proposerIndex": bson.M{"$type": "null"},
+			"slot":          bson.M{"$exists": true},
+			"orphaned":      bson.M{"$exists": false},
+		},
+		bson.M{"$unset": bson.M{"proposerIndex": "", "slot": ""}})
+	if err != nil {
+		configs.Logger.Error("Failed to reset empty slot proposers", zap.Error(err))
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		configs.Logger.Info("Reset empty slot proposers for re-checking", zap.Int64("blocks", result.ModifiedCount))
+	}
+	return nil
+}
+
+// GetBlocksWithoutProposer returns the oldest blocks numbered from from up to
+// but not including before that have not been attributed to a beacon
+// proposer yet
+func GetBlocksWithoutProposer(from, before, limit int64) ([]models.ZondDatabaseBlock, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetProjection(bson.M{"result.hash": 1, "result.number": 1, "result.timestamp": 1, "blockNum": 1, "proposerAttempts": 1}).
+		SetSort(bson.D{{Key: "blockNum", Value: 1}}).
+		SetLimit(limit)
+
+	filter := bson.M{
+		"blockNum":      bson.M{"$gte": from, "$lt": before},
+		"proposerIndex": bson.M{"$exists": false},
+	}
+	cursor, err := configs.BlocksCollections.Find(ctx, filter, findOptions)
+	if err != nil {
+		configs.Logger.Error("Failed to query blocks without proposer", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var blocks []models.ZondDatabaseBlock
+	if err := cursor.All(ctx, &blocks); err != nil {
+		configs.Logger.Error("Failed to decode blocks without proposer", zap.Error(err))
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// CountProposerAttempt records that a block could not be attributed yet
+func CountProposerAttempt(blockHash string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.BlocksCollections.UpdateOne(ctx,
+		bson.M{"result.hash": blockHash},
+		bson.M{"$inc": bson.M{"proposerAttempts": 1}})
+	if err != nil {
+		configs.Logger.Error("Failed to count proposer attempt",
+			zap.String("hash", blockHash),
+			zap.Error(err))
+	}
+	return err
+}
+
+// MarkBlockOrphaned records a null proposer on a block whose slot was taken
+// by a beacon block carrying another execution payload
+func MarkBlockOrphaned(blockHash string, slot int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.BlocksCollections.UpdateOne(ctx,
+		bson.M{"result.hash": blockHash},
+		bson.M{"$set": bson.M{"slot": slot, "proposerIndex": nil, "orphaned": true}})
+	if err != nil {
+		configs.Logger.Error("Failed to mark block orphaned",
+			zap.String("hash", blockHash),
+			zap.Error(err))
+	}
+	return err
+}
+
+// MarkBlocksWithoutProposer records a null proposer on blocks that no beacon
+// block can carry, such as blocks from before the beacon genesis, so they are
+// not retried
+func MarkBlocksWithoutProposer(blockHashes []string) error {
+	if len(blockHashes) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.BlocksCollections.UpdateMany(ctx,
+		bson.M{"result.hash": bson.M{"$in": blockHashes}},
+		bson.M{"$set": bson.M{"proposerIndex": nil}})
+	if err != nil {
+		configs.Logger.Error("Failed to mark blocks without proposer", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateBlockProposer stores the beacon slot, proposer and graffiti on a block.
+// A nil info records the slot with a null proposer so the block is not
+// retried; callers only do so once the slot is final.
+func UpdateBlockProposer(blockHash string, slot int64, info *models.BeaconBlockInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	fields := bson.M{"slot": slot, "proposerIndex": nil}
+	if info != nil {
+		fields = bson.M{
+			"slot":            info.Slot,
+			"proposerIndex":   info.ProposerIndex,
+			"graffiti":        info.Graffiti,
+			"beaconBlockRoot": info.BlockRoot,
+		}
+	}
+
+	_, err := configs.BlocksCollections.UpdateOne(ctx, bson.M{"result.hash": blockHash}, bson.M{"$set": fields})
+	if err != nil {
+		configs.Logger.Error("Failed to update block proposer",
+			zap.String("hash", blockHash),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
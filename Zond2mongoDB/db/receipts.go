@@ -0,0 +1,52 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// StoreReceipt upserts the full receipt of a mined transaction, so a block
+// processed again after a reorg replaces the receipt instead of duplicating it
+func StoreReceipt(receipt *models.TransactionReceipt, blockTimestamp string) error {
+	if receipt == nil || receipt.Result.TransactionHash == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	record := models.NewReceiptRecord(receipt, blockTimestamp)
+	_, err := configs.ReceiptsCollections.ReplaceOne(ctx,
+		bson.M{"_id": record.TxHash},
+		record,
+		options.Replace().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store transaction receipt",
+			zap.String("txHash", record.TxHash),
+			zap.Error(err))
+	}
+	return err
+}
+
+// receiptStoreAttempts is the number of times a receipt write is tried before giving up
+const receiptStoreAttempts = 3
+
+// storeReceiptWithRetry stores a receipt, retrying failed writes with a short backoff
+func storeReceiptWithRetry(receipt *models.TransactionReceipt, blockTimestamp string) error {
+	var err error
+	for attempt := 1; attempt <= receiptStoreAttempts; attempt++ {
+		if err = StoreReceipt(receipt, blockTimestamp); err == nil {
+			return nil
+		}
+		if attempt < receiptStoreAttempts {
+			time.Sleep(time.Duration(attempt*100) * time.Millisecond)
+		}
+	}
+	return err
+}
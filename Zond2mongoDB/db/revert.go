@@ -0,0 +1,135 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// RevertReasonMaxAttempts is the number of revert reason passes a failed
+// transaction gets before it is left without a reason
+const RevertReasonMaxAttempts = 5
+
+// PendingRevert is a failed transaction whose revert reason is still to be determined
+type PendingRevert struct {
+	TxHash      string `bson:"txHash"`
+	BlockNumber string `bson:"blockNumber"`
+	Attempts    int    `bson:"revertAttempts"`
+}
+
+// GetPendingRevertReasons returns failed transactions awaiting a revert reason
+func GetPendingRevertReasons(limit int64) ([]PendingRevert, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	cursor, err := configs.TransferCollections.Find(ctx,
+		bson.M{"revertPending": true},
+		options.Find().
+			SetProjection(bson.M{"txHash": 1, "blockNumber": 1, "revertAttempts": 1}).
+			SetLimit(limit))
+	if err != nil {
+		configs.Logger.Error("Failed to query pending revert reasons", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var pending []PendingRevert
+	if err := cursor.All(ctx, &pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// GetBlockTransaction returns a transaction as stored in its block, or nil if
+// the block or transaction is not stored
+func GetBlockTransaction(blockNumber, txHash string) (*models.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var block models.ZondDatabaseBlock
+	err := configs.BlocksCollections.FindOne(ctx,
+		bson.M{"result.number": blockNumber, "result.transactions.hash": txHash},
+		options.FindOne().SetProjection(bson.M{"result.transactions.$": 1})).Decode(&block)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		configs.Logger.Error("Failed to get block transaction",
+			zap.String("txHash", txHash),
+			zap.Error(err))
+		return nil, err
+	}
+	if len(block.Result.Transactions) == 0 {
+		return nil, nil
+	}
+	return &block.Result.Transactions[0], nil
+}
+
+// GetVerifiedContractABI returns the verified ABI of a contract, or an empty
+// string if its source has not been verified
+func GetVerifiedContractABI(address string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var contract struct {
+		ABI string `bson:"abi"`
+	}
+	err := configs.VerifiedContractsCollections.FindOne(ctx,
+		bson.M{"address": strings.ToLower(address)},
+		options.FindOne().SetProjection(bson.M{"abi": 1})).Decode(&contract)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", nil
+		}
+		configs.Logger.Error("Failed to get verified contract ABI",
+			zap.String("address", address),
+			zap.Error(err))
+		return "", err
+	}
+	return contract.ABI, nil
+}
+
+// StoreRevertReason records the revert reason of a failed transaction
+func StoreRevertReason(txHash string, reason *models.RevertReason) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.TransferCollections.UpdateMany(ctx,
+		bson.M{"txHash": txHash},
+		bson.M{
+			"$set":   bson.M{"revertReason": reason},
+			"$unset": bson.M{"revertPending": "", "revertAttempts": ""},
+		})
+	if err != nil {
+		configs.Logger.Error("Failed to store revert reason",
+			zap.String("txHash", txHash),
+			zap.Error(err))
+	}
+	return err
+}
+
+// CountRevertAttempt records a failed revert reason pass. After
+// RevertReasonMaxAttempts passes the transaction is no longer retried.
+func CountRevertAttempt(pending PendingRevert) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	update := bson.M{"$inc": bson.M{"revertAttempts": 1}}
+	if pending.Attempts+1 >= RevertReasonMaxAttempts {
+		update = bson.M{"$unset": bson.M{"revertPending": ""}, "$set": bson.M{"revertAttempts": pending.Attempts + 1}}
+	}
+
+	_, err := configs.TransferCollections.UpdateMany(ctx, bson.M{"txHash": pending.TxHash}, update)
+	if err != nil {
+		configs.Logger.Error("Failed to count revert reason attempt",
+			zap.String("txHash", pending.TxHash),
+			zap.Error(err))
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// slotSyncStateID is the sync_state document holding the last processed slot
+const slotSyncStateID = "last_synced_slot"
+
+// GetLastSyncedSlot returns the last slot stored in the slots collection, or -1 if none
+func GetLastSyncedSlot() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	var state struct {
+		Slot int64 `bson:"slot"`
+	}
+	err := configs.GetCollection(configs.DB, SyncStateCollection).FindOne(ctx, bson.M{"_id": slotSyncStateID}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return -1, nil
+		}
+		configs.Logger.Error("Failed to get slot sync state", zap.Error(err))
+		return -1, err
+	}
+	return state.Slot, nil
+}
+
+// StoreLastSyncedSlot records the last slot stored in the slots collection
+func StoreLastSyncedSlot(slot int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err := configs.GetCollection(configs.DB, SyncStateCollection).UpdateOne(ctx,
+		bson.M{"_id": slotSyncStateID},
+		bson.M{"$set": bson.M{"slot": slot}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store slot sync state", zap.Error(err))
+	}
+	return err
+}
+
+// UpsertSlots stores slot records keyed by slot number
+func UpsertSlots(slots []models.SlotRecord) error {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	operations := make([]mongo.WriteModel, 0, len(slots))
+	for _, s := range slots {
+		operations = append(operations, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": s.ID}).
+			SetReplacement(s).
+			SetUpsert(true))
+	}
+
+	_, err := configs.SlotsCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		configs.Logger.Error("Failed to upsert slots", zap.Error(err))
+	}
+	return err
+}
+
+// GetUnfinalizedSlots returns stored slots at or below upTo that were recorded before finality
+func GetUnfinalizedSlots(upTo int64, limit int64) ([]models.SlotRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := configs.SlotsCollections.Find(ctx,
+		bson.M{"finalized": false, "_id": bson.M{"$lte": upTo}}, findOptions)
+	if err != nil {
+		configs.Logger.Error("Failed to query unfinalized slots", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var slots []models.SlotRecord
+	if err := cursor.All(ctx, &slots); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
@@ -0,0 +1,599 @@
+package db
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// sortKeyBatchSize is the number of documents updated per backfill batch
+const sortKeyBatchSize = 1000
+
+// fetchReceiptLogs loads the logs of a transaction from the node, for token
+// transfers whose receipt was never stored
+var fetchReceiptLogs = func(txHash string) ([]models.Log, error) {
+	receipt, err := rpc.GetTransactionReceipt(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil || receipt.Result.TransactionHash == "" {
+		return nil, fmt.Errorf("no receipt for %s", txHash)
+	}
+	return receipt.Result.Logs, nil
+}
+
+// BackfillSortKeys adds the integer chain position (blockNum, txIndex, logIndex)
+// that the API pages by to blocks, address transactions and token transfers
+// stored before those fields existed, and the integer timestamp to blocks.
+// Transaction indexes come from the transactions of the stored block and log
+// indexes from the stored receipt, or from the node when it is missing.
+// Internal transactions get the block number and exact value of their
+// transaction. Documents
+// whose position cannot be worked out yet are left without the fields, and
+// complete is false until a pass has filled in every one.
+func BackfillSortKeys() (complete bool, err error) {
+	complete = true
+	for _, backfill := range []func() (bool, error){
+		backfillBlockNumbers,
+		backfillTransactionPositions,
+		backfillTokenTransferPositions,
+		backfillInternalTransactionBlocks,
+		backfillInternalTransactionValues,
+	} {
+		done, err := backfill()
+		if err != nil {
+			return false, err
+		}
+		complete = complete && done
+	}
+	return complete, nil
+}
+
+func backfillBlockNumbers() (bool, error) {
+	type blockNumber struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Result struct {
+			Number    string `bson:"number"`
+			Timestamp string `bson:"timestamp"`
+		} `bson:"result"`
+	}
+
+	return backfillBatches(configs.BlocksCollections, "blocks", "timestampNum",
+		bson.M{"result.number": 1, "result.timestamp": 1},
+		func(ctx context.Context, cursor *mongo.Cursor) ([]mongo.WriteModel, []primitive.ObjectID, error) {
+			var blocks []blockNumber
+			if err := cursor.All(ctx, &blocks); err != nil {
+				return nil, nil, err
+			}
+			ids := make([]primitive.ObjectID, 0, len(blocks))
+			updates := make([]mongo.WriteModel, 0, len(blocks))
+			for _, block := range blocks {
+				ids = append(ids, block.ID)
+				updates = append(updates, mongo.NewUpdateOneModel().
+					SetFilter(bson.M{"_id": block.ID}).
+					SetUpdate(bson.M{"$set": bson.M{
+						"blockNum":     utils.HexToInt(block.Result.Number).Int64(),
+						"timestampNum": utils.HexToInt(block.Result.Timestamp).Int64(),
+					}}))
+			}
+			return updates, ids, nil
+		})
+}
+
+// transactionPosition is a stored address transaction or token transfer
+// without its chain position
+type transactionPosition struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	TxHash       string             `bson:"txHash"`
+	BlockNumber  string             `bson:"blockNumber"`
+	TransferType string             `bson:"transferType"`
+}
+
+func backfillTransactionPositions() (bool, error) {
+	return backfillBatches(configs.TransactionByAddressCollections, "transactionByAddress", "blockNum",
+		bson.M{"txHash": 1, "blockNumber": 1},
+		func(ctx context.Context, cursor *mongo.Cursor) ([]mongo.WriteModel, []primitive.ObjectID, error) {
+			var txs []transactionPosition
+			if err := cursor.All(ctx, &txs); err != nil {
+				return nil, nil, err
+			}
+			txIndexes, err := blockTransactionIndexes(ctx, txs)
+			if err != nil {
+				return nil, nil, err
+			}
+			return positionUpdates(transactionPositions(txs, txIndexes)), documentIDs(txs), nil
+		})
+}
+
+func backfillTokenTransferPositions() (bool, error) {
+	collection := configs.GetTokenTransfersCollection()
+	return backfillBatches(collection, "tokenTransfers", "blockNum",
+		bson.M{"txHash": 1, "blockNumber": 1, "transferType": 1},
+		func(ctx context.Context, cursor *mongo.Cursor) ([]mongo.WriteModel, []primitive.ObjectID, error) {
+			var transfers []transactionPosition
+			if err := cursor.All(ctx, &transfers); err != nil {
+				return nil, nil, err
+			}
+			txIndexes, err := blockTransactionIndexes(ctx, transfers)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			hashes := make([]string, 0, len(transfers))
+			for _, transfer := range transfers {
+				hashes = append(hashes, transfer.TxHash)
+			}
+			receipts, err := receiptsByHash(ctx, hashes)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			// Log indexes follow the order of all the transfers of a transaction,
+			// including those backfilled by earlier batches
+			txTransfers, err := transfersByTx(ctx, collection, hashes)
+			if err != nil {
+				return nil, nil, err
+			}
+			logs := transactionLogs(txTransfers, receipts, fetchReceiptLogs)
+
+			positions := tokenTransferPositions(transfers, txIndexes, logs, txTransfers)
+			return positionUpdates(positions), documentIDs(transfers), nil
+		})
+}
+
+func backfillInternalTransactionBlocks() (bool, error) {
+	type internalTransaction struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Hash string             `bson:"hash"`
+	}
+
+	return backfillBatches(configs.InternalTransactionByAddressCollections, "internalTransactionByAddress", "blockNum",
+		bson.M{"hash": 1},
+		func(ctx context.Context, cursor *mongo.Cursor) ([]mongo.WriteModel, []primitive.ObjectID, error) {
+			var txs []internalTransaction
+			if err := cursor.All(ctx, &txs); err != nil {
+				return nil, nil, err
+			}
+			ids := make([]primitive.ObjectID, 0, len(txs))
+			hashes := make([]string, 0, len(txs))
+			for _, tx := range txs {
+				ids = append(ids, tx.ID)
+				hashes = append(hashes, tx.Hash)
+			}
+			blocks, err := blockNumbersByHash(ctx, hashes)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			// Internal transactions of transactions that are not stored yet are
+			// left for a later pass
+			updates := make([]mongo.WriteModel, 0, len(txs))
+			for _, tx := range txs {
+				blockNumber, ok := blocks[tx.Hash]
+				if !ok {
+					continue
+				}
+				updates = append(updates, mongo.NewUpdateOneModel().
+					SetFilter(bson.M{"_id": tx.ID}).
+					SetUpdate(bson.M{"$set": bson.M{
+						"blockNum": utils.HexToInt(blockNumber).Int64(),
+					}}))
+			}
+			return updates, ids, nil
+		})
+}
+
+// backfillInternalTransactionValues adds the exact value in wei to internal
+// transactions stored with only the QRL amount. The traced call is the
+// transaction itself, so its value is the value of the block transaction.
+func backfillInternalTransactionValues() (bool, error) {
+	type internalTransaction struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Hash string             `bson:"hash"`
+	}
+
+	return backfillBatches(configs.InternalTransactionByAddressCollections, "internalTransactionByAddress", "valueWei",
+		bson.M{"hash": 1},
+		func(ctx context.Context, cursor *mongo.Cursor) ([]mongo.WriteModel, []primitive.ObjectID, error) {
+			var txs []internalTransaction
+			if err := cursor.All(ctx, &txs); err != nil {
+				return nil, nil, err
+			}
+			ids := make([]primitive.ObjectID, 0, len(txs))
+			hashes := make([]string, 0, len(txs))
+			for _, tx := range txs {
+				ids = append(ids, tx.ID)
+				hashes = append(hashes, tx.Hash)
+			}
+			blocks, err := blockNumbersByHash(ctx, hashes)
+			if err != nil {
+				return nil, nil, err
+			}
+			numbers := make([]string, 0, len(blocks))
+			for _, number := range blocks {
+				numbers = append(numbers, number)
+			}
+			blockTxs, err := storedBlockTransactions(ctx, numbers, "value")
+			if err != nil {
+				return nil, nil, err
+			}
+
+			updates := make([]mongo.WriteModel, 0, len(txs))
+			for _, tx := range txs {
+				blockTx, ok := blockTxs[tx.Hash]
+				if !ok || blockTx.Value == "" {
+					continue
+				}
+				updates = append(updates, mongo.NewUpdateOneModel().
+					SetFilter(bson.M{"_id": tx.ID}).
+					SetUpdate(bson.M{"$set": bson.M{
+						"valueWei": utils.HexToInt(blockTx.Value).String(),
+					}}))
+			}
+			return updates, ids, nil
+		})
+}
+
+// backfillBatches loads the documents without the missing field in _id order,
+// a batch at a time, and writes the updates built for them. Documents that
+// build returns no update for are skipped over and left for a later pass, in
+// which case complete is false.
+func backfillBatches(collection *mongo.Collection, name, missing string, projection bson.M,
+	build func(ctx context.Context, cursor *mongo.Cursor) ([]mongo.WriteModel, []primitive.ObjectID, error)) (complete bool, err error) {
+	total, skipped := 0, 0
+	var after *primitive.ObjectID
+	for {
+		filter := bson.M{missing: bson.M{"$exists": false}}
+		if after != nil {
+			filter["_id"] = bson.M{"$gt": *after}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		cursor, err := collection.Find(ctx, filter,
+			options.Find().
+				SetProjection(projection).
+				SetSort(bson.D{{Key: "_id", Value: 1}}).
+				SetLimit(sortKeyBatchSize))
+		if err != nil {
+			cancel()
+			configs.Logger.Error("Failed to query documents without sort keys",
+				zap.String("collection", name),
+				zap.Error(err))
+			return false, err
+		}
+
+		updates, ids, err := build(ctx, cursor)
+		if err == nil && len(updates) > 0 {
+			_, err = collection.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+		}
+		cancel()
+		if err != nil {
+			configs.Logger.Error("Failed to backfill sort keys",
+				zap.String("collection", name),
+				zap.Error(err))
+			return false, err
+		}
+
+		total += len(updates)
+		skipped += len(ids) - len(updates)
+		if len(ids) < sortKeyBatchSize {
+			break
+		}
+		after = &ids[len(ids)-1]
+	}
+
+	if total > 0 {
+		configs.Logger.Info("Backfilled sort keys",
+			zap.String("collection", name),
+			zap.Int("count", total))
+	}
+	if skipped > 0 {
+		configs.Logger.Warn("Sort keys not known yet, left for a later pass",
+			zap.String("collection", name),
+			zap.Int("count", skipped))
+	}
+	return skipped == 0, nil
+}
+
+// documentIDs returns the ids of the documents of a batch
+func documentIDs(docs []transactionPosition) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+// positionUpdates turns the positions worked out for documents into updates
+func positionUpdates(positions map[primitive.ObjectID]bson.M) []mongo.WriteModel {
+	updates := make([]mongo.WriteModel, 0, len(positions))
+	for id, position := range positions {
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": position}))
+	}
+	return updates
+}
+
+// transactionPositions returns the blockNum and txIndex of the transactions
+// whose index is known from their block, keyed by document id
+func transactionPositions(txs []transactionPosition, txIndexes map[string]int64) map[primitive.ObjectID]bson.M {
+	positions := make(map[primitive.ObjectID]bson.M, len(txs))
+	for _, tx := range txs {
+		txIndex, ok := txIndexes[tx.TxHash]
+		if !ok {
+			continue
+		}
+		positions[tx.ID] = bson.M{
+			"blockNum": utils.HexToInt(tx.BlockNumber).Int64(),
+			"txIndex":  txIndex,
+		}
+	}
+	return positions
+}
+
+// tokenTransferPositions returns the blockNum, txIndex and logIndex of the
+// token transfers whose transaction index and log index are both known, keyed
+// by document id. txTransfers holds all the transfers of each transaction in
+// storage order, as log indexes are assigned across them.
+func tokenTransferPositions(transfers []transactionPosition, txIndexes map[string]int64,
+	logs map[string][]models.Log, txTransfers map[string][]transferParties) map[primitive.ObjectID]bson.M {
+	logIndexes := make(map[primitive.ObjectID]int64, len(transfers))
+	for hash, parties := range txTransfers {
+		// Without logs only the direct transfers of a transaction get an index
+		for id, logIndex := range transferLogIndexes(logs[hash], parties) {
+			logIndexes[id] = logIndex
+		}
+	}
+
+	positions := make(map[primitive.ObjectID]bson.M, len(transfers))
+	for _, transfer := range transfers {
+		txIndex, ok := txIndexes[transfer.TxHash]
+		if !ok {
+			continue
+		}
+		logIndex, ok := logIndexes[transfer.ID]
+		if !ok {
+			continue
+		}
+		positions[transfer.ID] = bson.M{
+			"blockNum": utils.HexToInt(transfer.BlockNumber).Int64(),
+			"txIndex":  txIndex,
+			"logIndex": logIndex,
+		}
+	}
+	return positions
+}
+
+// blockTransactionIndexes loads the transaction indexes of the given
+// transactions from the transactions of their stored blocks
+func blockTransactionIndexes(ctx context.Context, txs []transactionPosition) (map[string]int64, error) {
+	numbers := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		numbers = append(numbers, tx.BlockNumber)
+	}
+	blockTxs, err := storedBlockTransactions(ctx, numbers, "transactionindex")
+	if err != nil {
+		return nil, err
+	}
+
+	txIndexes := make(map[string]int64, len(txs))
+	for hash, tx := range blockTxs {
+		if tx.TransactionIndex != "" {
+			txIndexes[hash] = utils.HexToInt(tx.TransactionIndex).Int64()
+		}
+	}
+	return txIndexes, nil
+}
+
+// storedBlockTransactions loads the transactions of the stored blocks with the
+// given hex numbers, keyed by hash, with only their hash and the given fields
+func storedBlockTransactions(ctx context.Context, blockNumbers []string, fields ...string) (map[string]models.Transaction, error) {
+	numbers := make([]string, 0, len(blockNumbers))
+	seen := make(map[string]bool, len(blockNumbers))
+	for _, number := range blockNumbers {
+		if !seen[number] {
+			seen[number] = true
+			numbers = append(numbers, number)
+		}
+	}
+
+	projection := bson.M{"result.transactions.hash": 1}
+	for _, field := range fields {
+		projection["result.transactions."+field] = 1
+	}
+	cursor, err := configs.BlocksCollections.Find(ctx,
+		bson.M{"result.number": bson.M{"$in": numbers}},
+		options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	txs := make(map[string]models.Transaction)
+	for cursor.Next(ctx) {
+		var block models.ZondDatabaseBlock
+		if err := cursor.Decode(&block); err != nil {
+			continue
+		}
+		for _, tx := range block.Result.Transactions {
+			if tx.Hash != "" {
+				txs[tx.Hash] = tx
+			}
+		}
+	}
+	return txs, cursor.Err()
+}
+
+// receiptsByHash loads the stored receipts of the given transactions
+func receiptsByHash(ctx context.Context, hashes []string) (map[string]models.ReceiptRecord, error) {
+	receipts := make(map[string]models.ReceiptRecord, len(hashes))
+	cursor, err := configs.ReceiptsCollections.Find(ctx,
+		bson.M{"_id": bson.M{"$in": hashes}},
+		options.Find().SetProjection(bson.M{"transactionIndex": 1, "logs": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var receipt models.ReceiptRecord
+		if err := cursor.Decode(&receipt); err == nil {
+			receipts[receipt.TxHash] = receipt
+		}
+	}
+	return receipts, cursor.Err()
+}
+
+// transactionLogs returns the logs of the transactions with event transfers,
+// from their stored receipt or else from fetch. Transactions whose logs could
+// not be loaded are left out.
+func transactionLogs(txTransfers map[string][]transferParties, receipts map[string]models.ReceiptRecord,
+	fetch func(txHash string) ([]models.Log, error)) map[string][]models.Log {
+	logs := make(map[string][]models.Log, len(txTransfers))
+	for hash, parties := range txTransfers {
+		if receipt, ok := receipts[hash]; ok {
+			logs[hash] = receipt.Logs
+			continue
+		}
+		events := false
+		for _, transfer := range parties {
+			events = events || transfer.TransferType != "direct"
+		}
+		if !events {
+			continue
+		}
+		fetched, err := fetch(hash)
+		if err != nil {
+			configs.Logger.Warn("Failed to load logs of token transfers without a receipt",
+				zap.String("txHash", hash),
+				zap.Error(err))
+			continue
+		}
+		logs[hash] = fetched
+	}
+	return logs
+}
+
+// blockNumbersByHash loads the hex block numbers of the given transactions
+// from the transfer collection
+func blockNumbersByHash(ctx context.Context, hashes []string) (map[string]string, error) {
+	cursor, err := configs.TransferCollections.Find(ctx,
+		bson.M{"txHash": bson.M{"$in": hashes}},
+		options.Find().SetProjection(bson.M{"txHash": 1, "blockNumber": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blocks := make(map[string]string, len(hashes))
+	for cursor.Next(ctx) {
+		var tx struct {
+			TxHash      string `bson:"txHash"`
+			BlockNumber string `bson:"blockNumber"`
+		}
+		if err := cursor.Decode(&tx); err == nil && tx.BlockNumber != "" {
+			blocks[tx.TxHash] = tx.BlockNumber
+		}
+	}
+	return blocks, cursor.Err()
+}
+
+// transferParties is a stored token transfer as matched against receipt logs
+type transferParties struct {
+	ID              primitive.ObjectID `bson:"_id"`
+	ContractAddress string             `bson:"contractAddress"`
+	From            string             `bson:"from"`
+	To              string             `bson:"to"`
+	TxHash          string             `bson:"txHash"`
+	TransferType    string             `bson:"transferType"`
+}
+
+// transfersByTx loads the token transfers of the given transactions in the
+// order they were stored, grouped by transaction hash
+func transfersByTx(ctx context.Context, collection *mongo.Collection, hashes []string) (map[string][]transferParties, error) {
+	cursor, err := collection.Find(ctx,
+		bson.M{"txHash": bson.M{"$in": hashes}},
+		options.Find().
+			SetProjection(bson.M{"contractAddress": 1, "from": 1, "to": 1, "txHash": 1, "transferType": 1}).
+			SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	transfers := make(map[string][]transferParties)
+	for cursor.Next(ctx) {
+		var transfer transferParties
+		if err := cursor.Decode(&transfer); err == nil {
+			transfers[transfer.TxHash] = append(transfers[transfer.TxHash], transfer)
+		}
+	}
+	return transfers, cursor.Err()
+}
+
+// transferLogIndexes assigns each transfer of a transaction the log index of
+// the Transfer event it was read from. Direct transfers get -1. Event
+// transfers take the first unassigned Transfer log of their contract with the
+// same sender and recipient, or else the first unassigned one in log order.
+// Event transfers left without a log are not assigned an index.
+func transferLogIndexes(logs []models.Log, transfers []transferParties) map[primitive.ObjectID]int64 {
+	used := make([]bool, len(logs))
+	logIndexes := make(map[primitive.ObjectID]int64, len(transfers))
+	for _, transfer := range transfers {
+		if transfer.TransferType == "direct" {
+			logIndexes[transfer.ID] = -1
+			continue
+		}
+
+		contract := plainAddress(transfer.ContractAddress)
+		match, fallback := -1, -1
+		for i, log := range logs {
+			if used[i] || len(log.Topics) == 0 || log.Topics[0] != rpc.TransferEventSignature || plainAddress(log.Address) != contract {
+				continue
+			}
+			if fallback < 0 {
+				fallback = i
+			}
+			if len(log.Topics) == 3 &&
+				plainAddress(log.Topics[1]) == plainAddress(transfer.From) &&
+				plainAddress(log.Topics[2]) == plainAddress(transfer.To) {
+				match = i
+				break
+			}
+		}
+		if match < 0 {
+			match = fallback
+		}
+		if match < 0 {
+			continue
+		}
+		used[match] = true
+		logIndexes[transfer.ID] = utils.HexToInt(logs[match].LogIndex).Int64()
+	}
+	return logIndexes
+}
+
+// plainAddress reduces an address or an address topic to its last 40 hex
+// digits in lowercase, left-padded, so differently prefixed and trimmed
+// spellings compare equal
+func plainAddress(address string) string {
+	address = strings.ToLower(address)
+	address = strings.TrimPrefix(address, "z")
+	address = strings.TrimPrefix(address, "0x")
+	if len(address) > 40 {
+		return address[len(address)-40:]
+	}
+	return strings.Repeat("0", 40-len(address)) + address
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// transferLog builds a Transfer event log of a contract between two addresses
+func transferLog(logIndex, contract, from, to string) models.Log {
+	topic := func(address string) string {
+		return "0x" + strings.Repeat("0", 24) + address
+	}
+	return models.Log{
+		Address:  "Z" + contract,
+		Topics:   []string{rpc.TransferEventSignature, topic(from), topic(to)},
+		LogIndex: logIndex,
+	}
+}
+
+func TestTransferLogIndexes(t *testing.T) {
+	token := strings.Repeat("a", 40)
+	other := strings.Repeat("b", 40)
+	alice := strings.Repeat("1", 40)
+	bob := strings.Repeat("2", 40)
+	carol := "00" + strings.Repeat("3", 38)
+
+	logs := []models.Log{
+		transferLog("0x3", other, alice, bob),
+		transferLog("0x4", token, alice, bob),
+		{Address: "Z" + token, Topics: []string{"0x8c5be1e5"}, LogIndex: "0x5"},
+		transferLog("0x6", token, bob, carol),
+		transferLog("0x7", token, alice, bob),
+	}
+
+	transfer := func(contract, from, to, transferType string) transferParties {
+		return transferParties{
+			ID:              primitive.NewObjectID(),
+			ContractAddress: "Z" + contract,
+			From:            "Z" + from,
+			To:              "Z" + to,
+			TransferType:    transferType,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		transfers []transferParties
+		want      []int64
+	}{
+		{
+			name:      "one transfer per log",
+			transfers: []transferParties{transfer(token, alice, bob, "event"), transfer(token, bob, carol, "event"), transfer(token, alice, bob, "event")},
+			want:      []int64{4, 6, 7},
+		},
+		{
+			name:      "matched by parties out of log order",
+			transfers: []transferParties{transfer(token, bob, carol, "event"), transfer(token, alice, bob, "event")},
+			want:      []int64{6, 4},
+		},
+		{
+			name:      "trimmed addresses",
+			transfers: []transferParties{transfer(token, bob, strings.TrimLeft(carol, "0"), "event")},
+			want:      []int64{6},
+		},
+		{
+			name:      "unmatched parties take the next log",
+			transfers: []transferParties{transfer(token, carol, alice, "event"), transfer(token, alice, bob, "event")},
+			want:      []int64{4, 7},
+		},
+		{
+			name:      "other contract",
+			transfers: []transferParties{transfer(other, alice, bob, "event")},
+			want:      []int64{3},
+		},
+		{
+			name:      "direct transfer",
+			transfers: []transferParties{transfer(token, alice, bob, "direct"), transfer(token, alice, bob, "event")},
+			want:      []int64{-1, 4},
+		},
+		{
+			name:      "more transfers than logs",
+			transfers: []transferParties{transfer(other, alice, bob, "event"), transfer(other, alice, bob, "event")},
+			want:      []int64{3, unassigned},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := transferLogIndexes(logs, tt.transfers)
+			for i, transfer := range tt.transfers {
+				logIndex, ok := got[transfer.ID]
+				if !ok {
+					logIndex = unassigned
+				}
+				if logIndex != tt.want[i] {
+					t.Errorf("transfer %d: got log index %d, wanted %d", i, logIndex, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// unassigned stands for a transfer that was not given a log index
+const unassigned = int64(-2)
+
+func TestTokenTransferPositions(t *testing.T) {
+	token := strings.Repeat("a", 40)
+	alice := strings.Repeat("1", 40)
+	bob := strings.Repeat("2", 40)
+
+	stored := "0x" + strings.Repeat("1", 64)
+	receiptless := "0x" + strings.Repeat("2", 64)
+	unreachable := "0x" + strings.Repeat("3", 64)
+	unindexed := "0x" + strings.Repeat("4", 64)
+	direct := "0x" + strings.Repeat("5", 64)
+
+	position := func(hash, transferType string) (transactionPosition, transferParties) {
+		id := primitive.NewObjectID()
+		return transactionPosition{ID: id, TxHash: hash, BlockNumber: "0x10", TransferType: transferType},
+			transferParties{ID: id, ContractAddress: "Z" + token, From: "Z" + alice, To: "Z" + bob, TxHash: hash, TransferType: transferType}
+	}
+
+	var transfers []transactionPosition
+	txTransfers := make(map[string][]transferParties)
+	for _, tx := range []struct{ hash, transferType string }{
+		{stored, "event"}, {receiptless, "event"}, {unreachable, "event"}, {unindexed, "event"}, {direct, "direct"},
+	} {
+		transfer, parties := position(tx.hash, tx.transferType)
+		transfers = append(transfers, transfer)
+		txTransfers[tx.hash] = append(txTransfers[tx.hash], parties)
+	}
+
+	receipts := map[string]models.ReceiptRecord{
+		stored: {TxHash: stored, Logs: []models.Log{transferLog("0x2", token, alice, bob)}},
+	}
+	fetched := make(map[string]bool)
+	fetch := func(txHash string) ([]models.Log, error) {
+		fetched[txHash] = true
+		if txHash == receiptless || txHash == unindexed {
+			return []models.Log{transferLog("0x9", token, alice, bob)}, nil
+		}
+		return nil, errors.New("node unavailable")
+	}
+	txIndexes := map[string]int64{stored: 0, receiptless: 3, unreachable: 4, direct: 5}
+
+	logs := transactionLogs(txTransfers, receipts, fetch)
+	if fetched[stored] || fetched[direct] {
+		t.Errorf("fetched logs that were not needed: %v", fetched)
+	}
+	got := tokenTransferPositions(transfers, txIndexes, logs, txTransfers)
+
+	want := map[string]bson.M{
+		stored:      {"blockNum": int64(16), "txIndex": int64(0), "logIndex": int64(2)},
+		receiptless: {"blockNum": int64(16), "txIndex": int64(3), "logIndex": int64(9)},
+		direct:      {"blockNum": int64(16), "txIndex": int64(5), "logIndex": int64(-1)},
+	}
+	for _, transfer := range transfers {
+		position, ok := got[transfer.ID]
+		expected, known := want[transfer.TxHash]
+		if ok != known || (known && !reflect.DeepEqual(position, expected)) {
+			t.Errorf("transfer of %s: got %v (%v), wanted %v (%v)", transfer.TxHash[:4], position, ok, expected, known)
+		}
+	}
+}
+
+func TestTransactionPositions(t *testing.T) {
+	indexed := primitive.NewObjectID()
+	missing := primitive.NewObjectID()
+	txs := []transactionPosition{
+		{ID: indexed, TxHash: "0xaa", BlockNumber: "0x20"},
+		{ID: missing, TxHash: "0xbb", BlockNumber: "0x21"},
+	}
+
+	got := transactionPositions(txs, map[string]int64{"0xaa": 7})
+	if len(got) != 1 || !reflect.DeepEqual(got[indexed], bson.M{"blockNum": int64(32), "txIndex": int64(7)}) {
+		t.Errorf("got %v, wanted only the transaction found in its block", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"Zond2mongoDB/configs"
 	"Zond2mongoDB/models"
 	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/utils"
 	"context"
 	"strings"
 
@@ -46,6 +47,7 @@ func StoreTokenTransfer(transfer models.TokenTransfer) error {
 	transfer.From = strings.ToLower(transfer.From)
 	transfer.To = strings.ToLower(transfer.To)
 	transfer.ContractAddress = strings.ToLower(transfer.ContractAddress)
+	transfer.BlockNum = utils.HexToInt(transfer.BlockNumber).Int64()
 
 	_, err := collection.InsertOne(ctx, transfer)
 	if err != nil {
@@ -254,6 +256,8 @@ func ProcessBlockTokenTransfers(blockNumber string, blockTimestamp string) error
 			TokenDecimals:   contract.Decimals,
 			TokenName:       contract.Name,
 			TransferType:    "event",
+			TxIndex:         utils.HexToInt(log.TransactionIndex).Int64(),
+			LogIndex:        utils.HexToInt(log.LogIndex).Int64(),
 		}
 
 		// Store the transfer
@@ -4,6 +4,7 @@ import (
 	"Zond2mongoDB/configs"
 	"Zond2mongoDB/models"
 	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/utils"
 	"context"
 	"fmt"
 	"math/big"
@@ -21,7 +22,7 @@ func ProcessTransactions(blockData interface{}) {
 	for _, tx := range blockData.(models.ZondDatabaseBlock).Result.Transactions {
 		to, contractAddress, statusTx, isContract := processContracts(&tx)
 
-		processTransactionData(&tx, blockData.(models.ZondDatabaseBlock).Result.Timestamp, to, contractAddress, statusTx, isContract, blockData.(models.ZondDatabaseBlock).Result.Size)
+		processTransactionData(&tx, blockData.(models.ZondDatabaseBlock).Result.Timestamp, blockData.(models.ZondDatabaseBlock).Result.BaseFeePerGas, to, contractAddress, statusTx, isContract, blockData.(models.ZondDatabaseBlock).Result.Size)
 
 		// Store contract addresses for later token processing
 		// Only queue if this is actually a contract (new creation or interaction with existing contract)
@@ -238,6 +239,8 @@ func processTokenContract(targetAddress string, txHash string, blockNumber strin
 			TokenDecimals:   contract.Decimals,
 			TokenName:       contract.Name,
 			TransferType:    "direct",
+			TxIndex:         utils.HexToInt(txDetails.TransactionIndex).Int64(),
+			LogIndex:        -1,
 		}
 		if err := StoreTokenTransfer(transfer); err != nil {
 			configs.Logger.Error("Failed to store token transfer",
@@ -290,6 +293,8 @@ func processTokenContract(targetAddress string, txHash string, blockNumber strin
 			TokenDecimals:   contract.Decimals,
 			TokenName:       contract.Name,
 			TransferType:    "event",
+			TxIndex:         utils.HexToInt(receipt.Result.TransactionIndex).Int64(),
+			LogIndex:        utils.HexToInt(transferEvent.LogIndex).Int64(),
 		}
 		if err := StoreTokenTransfer(transfer); err != nil {
 			configs.Logger.Error("Failed to store token transfer",
@@ -313,11 +318,10 @@ func processTokenContract(targetAddress string, txHash string, blockNumber strin
 	}
 }
 
-func processTransactionData(tx *models.Transaction, blockTimestamp string, to string, contractAddress string, statusTx string, isContract bool, size string) {
+func processTransactionData(tx *models.Transaction, blockTimestamp string, baseFeePerGas string, to string, contractAddress string, statusTx string, isContract bool, size string) {
 	from := tx.From
 	txHash := tx.Hash
 	blockNumber := tx.BlockNumber
-	gasPrice := tx.GasPrice
 	pk := tx.PublicKey
 	signature := tx.Signature
 	data := tx.Data
@@ -355,65 +359,77 @@ func processTransactionData(tx *models.Transaction, blockTimestamp string, to st
 			resultBigFloat := new(big.Float).Quo(bigIntAsFloat, divisor)
 			resultFloat64, _ := resultBigFloat.Float64()
 
-			UpsertTransactions(address, resultFloat64, isContract)
+			UpsertTransactions(address, resultFloat64, getBalanceResult.String(), isContract)
 		}
 	}
 
-	transactionType, callType, fromInternal, toInternal, inputInternal, outputInternal, InternalTracerAddress, valueInternal, gasInternal, gasUsedInternal, addressFunctionIdentifier, amountFunctionIdentifier := rpc.CallDebugTraceTransaction(tx.Hash)
+	transactionType, callType, fromInternal, toInternal, inputInternal, outputInternal, InternalTracerAddress, valueInternal, gasInternal, gasUsedInternal, addressFunctionIdentifier, amountFunctionIdentifier, valueWeiInternal, errorInternal := rpc.CallDebugTraceTransaction(tx.Hash)
 	if transactionType == "CALL" || InternalTracerAddress != nil {
-		InternalTransactionByAddressCollection(transactionType, callType, txHash, fromInternal, toInternal, fmt.Sprintf("0x%x", inputInternal), fmt.Sprintf("0x%x", outputInternal), InternalTracerAddress, float64(valueInternal), fmt.Sprintf("0x%x", gasInternal), fmt.Sprintf("0x%x", gasUsedInternal), addressFunctionIdentifier, fmt.Sprintf("0x%x", amountFunctionIdentifier), blockTimestamp)
+		InternalTransactionByAddressCollection(transactionType, callType, txHash, fromInternal, toInternal, fmt.Sprintf("0x%x", inputInternal), fmt.Sprintf("0x%x", outputInternal), InternalTracerAddress, float64(valueInternal), fmt.Sprintf("0x%x", gasInternal), fmt.Sprintf("0x%x", gasUsedInternal), addressFunctionIdentifier, fmt.Sprintf("0x%x", amountFunctionIdentifier), blockTimestamp, blockNumber, valueWeiInternal, errorInternal)
 	}
 
-	// Calculate fees using hex strings
-	gasPriceBig := new(big.Int)
-	gasPriceBig.SetString(gasPrice[2:], 16)
-
-	gasUsedBig := new(big.Int)
-	// If gasUsedInternal is 0, try to use gasUsed from the transaction receipt
-	if gasUsedInternal == 0 {
-		// Get transaction receipt to obtain actual gas used
-		receipt, err := rpc.GetTransactionReceipt(txHash)
-		if err == nil && receipt != nil && receipt.Result.GasUsed != "" && len(receipt.Result.GasUsed) > 2 {
-			gasUsedBig.SetString(receipt.Result.GasUsed[2:], 16)
-			configs.Logger.Debug("Using gasUsed from receipt",
+	// Fees come from the receipt: gasUsed and effectiveGasPrice are only final once mined
+	var txFees models.TxFees
+	revertPending := false
+	receipt, err := rpc.GetTransactionReceipt(txHash)
+	if err != nil || receipt == nil || len(receipt.Result.GasUsed) <= 2 {
+		configs.Logger.Warn("Receipt unavailable, transaction fees not recorded",
+			zap.String("txHash", txHash),
+			zap.Error(err))
+	} else {
+		if err := storeReceiptWithRetry(receipt, blockTimestamp); err != nil {
+			configs.Logger.Error("Failed to store receipt after retries",
 				zap.String("txHash", txHash),
-				zap.String("gasUsed", receipt.Result.GasUsed))
-		} else {
-			// If receipt isn't available, use gas limit as a fallback
-			// This is not accurate but better than 0
-			if tx.Gas != "" && len(tx.Gas) > 2 {
-				gasUsedBig.SetString(tx.Gas[2:], 16)
-				configs.Logger.Debug("Using gas limit as fallback",
-					zap.String("txHash", txHash),
-					zap.String("gas", tx.Gas))
-			} else {
-				gasUsedBig.SetString(fmt.Sprintf("%x", gasUsedInternal), 16)
-			}
+				zap.Int("attempts", receiptStoreAttempts),
+				zap.Error(err))
 		}
-	} else {
-		gasUsedBig.SetString(fmt.Sprintf("%x", gasUsedInternal), 16)
-	}
 
-	feesBig := new(big.Int).Mul(gasPriceBig, gasUsedBig)
+		effectiveGasPrice := receipt.Result.EffectiveGasPrice
+		if len(effectiveGasPrice) <= 2 {
+			// Nodes report the effective price as gasPrice for mined transactions
+			effectiveGasPrice = tx.GasPrice
+		}
+		txFees = calculateTxFees(utils.HexToInt(receipt.Result.GasUsed), utils.HexToInt(effectiveGasPrice), utils.HexToInt(baseFeePerGas))
 
+		// The revert reason needs a trace or a replay, so it is left to the
+		// revert reason pass instead of slowing down block processing
+		revertPending = receipt.Result.Status == "0x0"
+	}
+
+	totalFee, _ := new(big.Int).SetString(txFees.TotalFee, 10)
+	if totalFee == nil {
+		totalFee = big.NewInt(0)
+	}
 	divisor = new(big.Float).SetFloat64(float64(configs.QUANTA))
-	feesFloat := new(big.Float).SetInt(feesBig)
-	feesResult := new(big.Float).Quo(feesFloat, divisor)
+	feesResult := new(big.Float).Quo(new(big.Float).SetInt(totalFee), divisor)
 	fees, _ := feesResult.Float64()
 
-	// Ensure fees are never zero for successful transactions
-	if fees == 0 && statusTx == "0x1" {
-		configs.Logger.Warn("Calculated fees is zero for a successful transaction, using minimal fee",
-			zap.String("txHash", txHash))
-		// Set a minimal fee value rather than zero
-		fees = 0.000001
-	}
+	TransactionByAddressCollection(blockTimestamp, txType, from, to, txHash, valueFloat64, fees, blockNumber, tx.TransactionIndex)
+	TransferCollection(blockNumber, blockTimestamp, from, to, txHash, pk, signature, nonce, valueFloat64, data, contractAddress, statusTx, size, fees, txFees, revertPending)
+}
 
-	TransactionByAddressCollection(blockTimestamp, txType, from, to, txHash, valueFloat64, fees, blockNumber)
-	TransferCollection(blockNumber, blockTimestamp, from, to, txHash, pk, signature, nonce, valueFloat64, data, contractAddress, statusTx, size, fees)
+// calculateTxFees splits a transaction fee into the part burnt by the base fee
+// and the priority tip paid to the proposer
+func calculateTxFees(gasUsed, effectiveGasPrice, baseFeePerGas *big.Int) models.TxFees {
+	total := new(big.Int).Mul(gasUsed, effectiveGasPrice)
+	burnt := new(big.Int).Mul(gasUsed, baseFeePerGas)
+	if burnt.Cmp(total) > 0 {
+		// Cannot happen for a valid block; never report a negative tip
+		burnt.Set(total)
+	}
+	tip := new(big.Int).Sub(total, burnt)
+
+	return models.TxFees{
+		GasUsed:           gasUsed.String(),
+		EffectiveGasPrice: effectiveGasPrice.String(),
+		BaseFeePerGas:     baseFeePerGas.String(),
+		BurntFee:          burnt.String(),
+		PriorityFee:       tip.String(),
+		TotalFee:          total.String(),
+	}
 }
 
-func TransferCollection(blockNumber string, blockTimestamp string, from string, to string, hash string, pk string, signature string, nonce string, value float64, data string, contractAddress string, status string, size string, paidFees float64) (*mongo.InsertOneResult, error) {
+func TransferCollection(blockNumber string, blockTimestamp string, from string, to string, hash string, pk string, signature string, nonce string, value float64, data string, contractAddress string, status string, size string, paidFees float64, fees models.TxFees, revertPending bool) (*mongo.InsertOneResult, error) {
 	// Normalize addresses to lowercase for consistent storage
 	from = strings.ToLower(from)
 	to = strings.ToLower(to)
@@ -434,6 +450,19 @@ func TransferCollection(blockNumber string, blockTimestamp string, from string,
 		{Key: "size", Value: size},
 		{Key: "paidFees", Value: paidFees},
 	}
+	if fees.TotalFee != "" {
+		baseDoc = append(baseDoc,
+			bson.E{Key: "gasUsed", Value: fees.GasUsed},
+			bson.E{Key: "effectiveGasPrice", Value: fees.EffectiveGasPrice},
+			bson.E{Key: "baseFeePerGas", Value: fees.BaseFeePerGas},
+			bson.E{Key: "burntFee", Value: fees.BurntFee},
+			bson.E{Key: "priorityFee", Value: fees.PriorityFee},
+			bson.E{Key: "totalFee", Value: fees.TotalFee},
+		)
+	}
+	if revertPending {
+		baseDoc = append(baseDoc, bson.E{Key: "revertPending", Value: true})
+	}
 
 	if contractAddress == "" {
 		doc = append(baseDoc, bson.E{Key: "to", Value: to})
@@ -455,7 +484,11 @@ func TransferCollection(blockNumber string, blockTimestamp string, from string,
 	return result, err
 }
 
-func InternalTransactionByAddressCollection(transactionType string, callType string, hash string, from string, to string, input string, output string, traceAddress []int, value float64, gas string, gasUsed string, addressFunctionIdentifier string, amountFunctionIdentifier string, blockTimestamp string) (*mongo.InsertOneResult, error) {
+// InternalTransactionByAddressCollection stores the trace of a contract call.
+// The integer block number lets the API filter internal transactions by block;
+// valueWei keeps the exact value next to the QRL amount and error the error
+// of the call, empty when it succeeded.
+func InternalTransactionByAddressCollection(transactionType string, callType string, hash string, from string, to string, input string, output string, traceAddress []int, value float64, gas string, gasUsed string, addressFunctionIdentifier string, amountFunctionIdentifier string, blockTimestamp string, blockNumber string, valueWei string, callError string) (*mongo.InsertOneResult, error) {
 	// Normalize addresses to lowercase for consistent storage
 	from = strings.ToLower(from)
 	to = strings.ToLower(to)
@@ -476,6 +509,9 @@ func InternalTransactionByAddressCollection(transactionType string, callType str
 		{Key: "addressFunctionIdentifier", Value: addressFunctionIdentifier},
 		{Key: "amountFunctionIdentifier", Value: amountFunctionIdentifier},
 		{Key: "blockTimestamp", Value: blockTimestamp},
+		{Key: "blockNum", Value: utils.HexToInt(blockNumber).Int64()},
+		{Key: "valueWei", Value: valueWei},
+		{Key: "error", Value: callError},
 	}
 
 	result, err := configs.InternalTransactionByAddressCollections.InsertOne(context.TODO(), doc)
@@ -487,7 +523,9 @@ func InternalTransactionByAddressCollection(transactionType string, callType str
 	return result, nil
 }
 
-func TransactionByAddressCollection(timeStamp string, txType string, from string, to string, hash string, amount float64, paidFees float64, blockNumber string) (*mongo.InsertOneResult, error) {
+// TransactionByAddressCollection stores a transaction for address lookups. The
+// integer block number and transaction index order transactions for paging.
+func TransactionByAddressCollection(timeStamp string, txType string, from string, to string, hash string, amount float64, paidFees float64, blockNumber string, txIndex string) (*mongo.InsertOneResult, error) {
 	// Normalize addresses to lowercase for consistent storage
 	from = strings.ToLower(from)
 	to = strings.ToLower(to)
@@ -501,6 +539,8 @@ func TransactionByAddressCollection(timeStamp string, txType string, from string
 		{Key: "amount", Value: amount},
 		{Key: "paidFees", Value: paidFees},
 		{Key: "blockNumber", Value: blockNumber},
+		{Key: "blockNum", Value: utils.HexToInt(blockNumber).Int64()},
+		{Key: "txIndex", Value: utils.HexToInt(txIndex).Int64()},
 	}
 
 	result, err := configs.TransactionByAddressCollections.InsertOne(context.TODO(), doc)
@@ -511,7 +551,9 @@ func TransactionByAddressCollection(timeStamp string, txType string, from string
 	return result, err
 }
 
-func UpsertTransactions(address string, value float64, isContract bool) (*mongo.UpdateResult, error) {
+// UpsertTransactions stores an address balance both as QRL for display and as
+// an exact decimal wei string for supply accounting
+func UpsertTransactions(address string, value float64, balanceWei string, isContract bool) (*mongo.UpdateResult, error) {
 	// Normalize address to lowercase to ensure consistent storage
 	// This matches the backend API's normalization in ReturnSingleAddress
 	address = strings.ToLower(address)
@@ -523,6 +565,7 @@ func UpsertTransactions(address string, value float64, isContract bool) (*mongo.
 			{Key: "$set", Value: bson.D{
 				{Key: "id", Value: address},
 				{Key: "balance", Value: value},
+				{Key: "balanceWei", Value: balanceWei},
 				{Key: "isContract", Value: true}, // Always set to true if we know it's a contract
 			}},
 		}
@@ -547,6 +590,7 @@ func UpsertTransactions(address string, value float64, isContract bool) (*mongo.
 			{Key: "$set", Value: bson.D{
 				{Key: "id", Value: address},
 				{Key: "balance", Value: value},
+				{Key: "balanceWei", Value: balanceWei},
 				// Don't update isContract field since we want to keep it as true
 			}},
 		}
@@ -563,6 +607,7 @@ func UpsertTransactions(address string, value float64, isContract bool) (*mongo.
 		{Key: "$set", Value: bson.D{
 			{Key: "id", Value: address},
 			{Key: "balance", Value: value},
+			{Key: "balanceWei", Value: balanceWei},
 			{Key: "isContract", Value: isContract},
 		}},
 	}
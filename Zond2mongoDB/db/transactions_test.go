@@ -0,0 +1,30 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCalculateTxFees(t *testing.T) {
+	tests := []struct {
+		name                    string
+		gasUsed, price, baseFee int64
+		burnt, priority, total  string
+	}{
+		{name: "tip over base fee", gasUsed: 21000, price: 12, baseFee: 10, burnt: "210000", priority: "42000", total: "252000"},
+		{name: "no tip", gasUsed: 21000, price: 10, baseFee: 10, burnt: "210000", priority: "0", total: "210000"},
+		{name: "before base fee", gasUsed: 21000, price: 5, baseFee: 0, burnt: "0", priority: "105000", total: "105000"},
+		{name: "no gas used", gasUsed: 0, price: 12, baseFee: 10, burnt: "0", priority: "0", total: "0"},
+		{name: "price below base fee", gasUsed: 21000, price: 8, baseFee: 10, burnt: "168000", priority: "0", total: "168000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees := calculateTxFees(big.NewInt(tt.gasUsed), big.NewInt(tt.price), big.NewInt(tt.baseFee))
+			if fees.BurntFee != tt.burnt || fees.PriorityFee != tt.priority || fees.TotalFee != tt.total {
+				t.Errorf("got burnt %s, priority %s, total %s, wanted %s, %s, %s",
+					fees.BurntFee, fees.PriorityFee, fees.TotalFee, tt.burnt, tt.priority, tt.total)
+			}
+		})
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"Zond2mongoDB/models"
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
@@ -25,88 +27,41 @@ func UpdateValidators(blockNumber string, previousHash string) {
 	}
 }
 
-func InsertValidators(beaconResponse models.BeaconValidatorResponse, currentEpoch string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func GetValidators() ([]models.ValidatorRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Convert beacon response to storage format
-	storage := models.ValidatorStorage{
-		ID:         "validators", // Single document ID for easy updates
-		Epoch:      currentEpoch,
-		UpdatedAt:  fmt.Sprintf("%d", time.Now().Unix()),
-		Validators: make([]models.ValidatorRecord, 0, len(beaconResponse.ValidatorList)),
-	}
-
-	// Convert each validator
-	for _, v := range beaconResponse.ValidatorList {
-		record := models.ValidatorRecord{
-			Index:                      v.Index,
-			PublicKeyHex:               models.Base64ToHex(v.Validator.PublicKey),
-			WithdrawalCredentialsHex:   models.Base64ToHex(v.Validator.WithdrawalCredentials),
-			EffectiveBalance:           v.Validator.EffectiveBalance,
-			Slashed:                    v.Validator.Slashed,
-			ActivationEligibilityEpoch: v.Validator.ActivationEligibilityEpoch,
-			ActivationEpoch:            v.Validator.ActivationEpoch,
-			ExitEpoch:                  v.Validator.ExitEpoch,
-			WithdrawableEpoch:          v.Validator.WithdrawableEpoch,
-			SlotNumber:                 v.Index, // Using index as slot number
-			IsLeader:                   true,    // Set based on your leader selection logic
-		}
-		storage.Validators = append(storage.Validators, record)
-	}
-
-	// Upsert the document
-	opts := options.Update().SetUpsert(true)
-	filter := bson.M{"_id": "validators"}
-	update := bson.M{"$set": storage}
-
-	_, err := configs.ValidatorsCollections.UpdateOne(ctx, filter, update, opts)
+	cursor, err := configs.ValidatorsCollections.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
 	if err != nil {
-		configs.Logger.Error("Failed to update validator document", zap.Error(err))
-		return err
+		configs.Logger.Error("Failed to query validators", zap.Error(err))
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	configs.Logger.Info("Successfully updated validators",
-		zap.Int("count", len(storage.Validators)),
-		zap.String("epoch", currentEpoch))
-	return nil
-}
-
-func GetValidators() (*models.ValidatorStorage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	var storage models.ValidatorStorage
-	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{"_id": "validators"}).Decode(&storage)
-	if err != nil {
-		configs.Logger.Error("Failed to get validator document", zap.Error(err))
+	var validators []models.ValidatorRecord
+	if err := cursor.All(ctx, &validators); err != nil {
+		configs.Logger.Error("Failed to decode validators", zap.Error(err))
 		return nil, err
 	}
 
-	return &storage, nil
+	return validators, nil
 }
 
 func GetValidatorByPublicKey(publicKeyHex string) (*models.ValidatorRecord, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var storage models.ValidatorStorage
-	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{
-		"validators.publicKeyHex": publicKeyHex,
-	}).Decode(&storage)
-
+	var validator models.ValidatorRecord
+	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{"publicKeyHex": publicKeyHex}).Decode(&validator)
 	if err != nil {
-		return nil, err
-	}
-
-	// Find the matching validator
-	for _, v := range storage.Validators {
-		if v.PublicKeyHex == publicKeyHex {
-			return &v, nil
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("validator not found")
 		}
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("validator not found")
+	return &validator, nil
 }
 
 func GetBlockNumberFromHash(hash string) string {
@@ -217,21 +172,19 @@ func GetValidatorByIndex(index string) (*models.ValidatorRecord, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var storage models.ValidatorStorage
-	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{
-		"validators.index": index,
-	}).Decode(&storage)
-
+	id, err := strconv.ParseInt(index, 10, 64)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid validator index: %s", index)
 	}
 
-	// Find the matching validator
-	for _, v := range storage.Validators {
-		if v.Index == index {
-			return &v, nil
+	var validator models.ValidatorRecord
+	err = configs.ValidatorsCollections.FindOne(ctx, bson.M{"_id": id}).Decode(&validator)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("validator not found")
 		}
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("validator not found")
+	return &validator, nil
 }
@@ -0,0 +1,66 @@
+package models
+
+// BeaconValidatorPerformanceResponse represents the beacon validator performance
+// endpoint. All slices are aligned with PublicKeys and describe the previous epoch.
+type BeaconValidatorPerformanceResponse struct {
+	PublicKeys           []string `json:"publicKeys"`
+	InclusionDistances   []string `json:"inclusionDistances"`
+	CorrectlyVotedSource []bool   `json:"correctlyVotedSource"`
+	CorrectlyVotedTarget []bool   `json:"correctlyVotedTarget"`
+	CorrectlyVotedHead   []bool   `json:"correctlyVotedHead"`
+	MissingValidators    []string `json:"missingValidators"`
+}
+
+// BeaconParticipationResponse represents the beacon validator participation endpoint
+type BeaconParticipationResponse struct {
+	Epoch         string `json:"epoch"`
+	Finalized     bool   `json:"finalized"`
+	Participation struct {
+		CurrentEpochActiveGwei           string `json:"currentEpochActiveGwei"`
+		CurrentEpochAttestingGwei        string `json:"currentEpochAttestingGwei"`
+		CurrentEpochTargetAttestingGwei  string `json:"currentEpochTargetAttestingGwei"`
+		PreviousEpochActiveGwei          string `json:"previousEpochActiveGwei"`
+		PreviousEpochAttestingGwei       string `json:"previousEpochAttestingGwei"`
+		PreviousEpochTargetAttestingGwei string `json:"previousEpochTargetAttestingGwei"`
+		PreviousEpochHeadAttestingGwei   string `json:"previousEpochHeadAttestingGwei"`
+	} `json:"participation"`
+}
+
+// ValidatorAttestationRecord is one validator's attestation result for one epoch
+type ValidatorAttestationRecord struct {
+	ValidatorIndex    int64   `bson:"validatorIndex" json:"validatorIndex"`
+	Epoch             int64   `bson:"epoch" json:"epoch"`
+	Included          bool    `bson:"included" json:"included"`
+	InclusionDistance int64   `bson:"inclusionDistance" json:"inclusionDistance"` // 0 when not included
+	CorrectSource     bool    `bson:"correctSource" json:"correctSource"`
+	CorrectTarget     bool    `bson:"correctTarget" json:"correctTarget"`
+	CorrectHead       bool    `bson:"correctHead" json:"correctHead"`
+	Score             float64 `bson:"score" json:"score"` // Effectiveness for this epoch, 0-1
+}
+
+// AttestationPerformance holds a validator's rolling attestation statistics
+type AttestationPerformance struct {
+	Effectiveness          float64 `bson:"effectiveness" json:"effectiveness"` // Exponential moving average of Score, 0-1
+	Epochs                 int64   `bson:"epochs" json:"epochs"`
+	Included               int64   `bson:"included" json:"included"`
+	CorrectSource          int64   `bson:"correctSource" json:"correctSource"`
+	CorrectTarget          int64   `bson:"correctTarget" json:"correctTarget"`
+	CorrectHead            int64   `bson:"correctHead" json:"correctHead"`
+	TotalInclusionDistance int64   `bson:"totalInclusionDistance" json:"totalInclusionDistance"`
+	LastEpoch              int64   `bson:"lastEpoch" json:"lastEpoch"`
+}
+
+// NetworkParticipationRecord holds network-wide attestation participation for one epoch
+type NetworkParticipationRecord struct {
+	Epoch                int64   `bson:"epoch" json:"epoch"`
+	ActiveGwei           string  `bson:"activeGwei" json:"activeGwei"`
+	AttestingGwei        string  `bson:"attestingGwei" json:"attestingGwei"`
+	TargetAttestingGwei  string  `bson:"targetAttestingGwei" json:"targetAttestingGwei"`
+	HeadAttestingGwei    string  `bson:"headAttestingGwei" json:"headAttestingGwei"`
+	ParticipationRate    float64 `bson:"participationRate" json:"participationRate"`       // Attesting / active
+	TargetParticipation  float64 `bson:"targetParticipation" json:"targetParticipation"`   // Target attesting / active
+	HeadParticipation    float64 `bson:"headParticipation" json:"headParticipation"`       // Head attesting / active
+	AverageEffectiveness float64 `bson:"averageEffectiveness" json:"averageEffectiveness"` // Mean per-validator score
+	ValidatorsTracked    int     `bson:"validatorsTracked" json:"validatorsTracked"`
+	Timestamp            int64   `bson:"timestamp" json:"timestamp"`
+}
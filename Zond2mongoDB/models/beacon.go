@@ -0,0 +1,119 @@
+package models
+
+import "encoding/json"
+
+// BeaconGenesisResponse represents the response from the beacon node genesis endpoint
+type BeaconGenesisResponse struct {
+	GenesisTime            string `json:"genesisTime"` // RFC3339 timestamp
+	DepositContractAddress string `json:"depositContractAddress"`
+	GenesisValidatorsRoot  string `json:"genesisValidatorsRoot"`
+}
+
+// BeaconBlocksResponse represents the response from the beacon blocks list endpoint
+type BeaconBlocksResponse struct {
+	BlockContainers []json.RawMessage `json:"blockContainers"`
+	NextPageToken   string            `json:"nextPageToken"`
+	TotalSize       int               `json:"totalSize"`
+}
+
+// SignedBeaconBlock is the fork-specific signed block held by a block container.
+// Byte fields are base64 encoded by the beacon API.
+type SignedBeaconBlock struct {
+	Block struct {
+		Slot          string `json:"slot"`
+		ProposerIndex string `json:"proposerIndex"`
+		ParentRoot    string `json:"parentRoot"`
+		Body          struct {
+			Graffiti         string `json:"graffiti"`
+			ExecutionPayload struct {
+				BlockHash   string `json:"blockHash"`
+				BlockNumber string `json:"blockNumber"`
+			} `json:"executionPayload"`
+			Deposits []struct {
+				Data struct {
+					PublicKey string `json:"publicKey"`
+					Amount    string `json:"amount"` // Decimal string, gwei
+				} `json:"data"`
+			} `json:"deposits"`
+			ProposerSlashings []struct {
+				Header1 struct {
+					Header struct {
+						ProposerIndex string `json:"proposerIndex"`
+					} `json:"header"`
+				} `json:"header1"`
+			} `json:"proposerSlashings"`
+			AttesterSlashings []struct {
+				Attestation1 struct {
+					AttestingIndices []string `json:"attestingIndices"`
+				} `json:"attestation1"`
+				Attestation2 struct {
+					AttestingIndices []string `json:"attestingIndices"`
+				} `json:"attestation2"`
+			} `json:"attesterSlashings"`
+		} `json:"body"`
+	} `json:"block"`
+}
+
+// BeaconBlockInfo is the slot-level data extracted from a beacon block
+type BeaconBlockInfo struct {
+	Slot                 int64
+	ProposerIndex        int64
+	Graffiti             string
+	BlockRoot            string // 0x-prefixed hex
+	ParentRoot           string // 0x-prefixed hex
+	ExecutionBlockHash   string // 0x-prefixed hex
+	ExecutionBlockNumber string // Decimal string
+	Canonical            bool
+	Deposits             []BeaconDeposit // Deposits the block credited to validator balances
+	Slashings            []BeaconSlashing
+}
+
+// BeaconDeposit is a deposit processed by a beacon block
+type BeaconDeposit struct {
+	PublicKeyHex string
+	Amount       int64 // gwei
+}
+
+// BeaconSlashing is a validator slashed by a beacon block
+type BeaconSlashing struct {
+	ValidatorIndex int64
+	Epoch          int64  // Epoch of the block that included the slashing
+	Reason         string // proposer_slashing or attester_slashing
+}
+
+// BeaconAssignmentsResponse represents a page of the validator assignments endpoint
+type BeaconAssignmentsResponse struct {
+	Epoch         string             `json:"epoch"`
+	Assignments   []BeaconAssignment `json:"assignments"`
+	NextPageToken string             `json:"nextPageToken"`
+	TotalSize     int                `json:"totalSize"`
+}
+
+type BeaconAssignment struct {
+	ValidatorIndex string   `json:"validatorIndex"`
+	ProposerSlots  []string `json:"proposerSlots"`
+	AttesterSlot   string   `json:"attesterSlot"`
+	CommitteeIndex string   `json:"committeeIndex"`
+}
+
+// Slot statuses
+const (
+	SlotStatusProposed = "proposed"
+	SlotStatusMissed   = "missed"
+	SlotStatusOrphaned = "orphaned"
+)
+
+// SlotRecord is stored in the slots collection, one document per beacon slot
+type SlotRecord struct {
+	ID                   int64  `bson:"_id" json:"-"` // Slot number
+	Slot                 int64  `bson:"slot" json:"slot"`
+	Epoch                int64  `bson:"epoch" json:"epoch"`
+	ProposerIndex        *int64 `bson:"proposerIndex" json:"proposerIndex"` // Nil if the duty is unknown
+	Status               string `bson:"status" json:"status"`               // proposed, missed or orphaned
+	ExecutionBlockHash   string `bson:"executionBlockHash,omitempty" json:"executionBlockHash,omitempty"`
+	ExecutionBlockNumber string `bson:"executionBlockNumber,omitempty" json:"executionBlockNumber,omitempty"` // Decimal string
+	BlockRoot            string `bson:"blockRoot,omitempty" json:"blockRoot,omitempty"`
+	Graffiti             string `bson:"graffiti,omitempty" json:"graffiti,omitempty"`
+	Timestamp            int64  `bson:"timestamp" json:"timestamp"` // Slot start, unix seconds
+	Finalized            bool   `bson:"finalized" json:"finalized"`
+}
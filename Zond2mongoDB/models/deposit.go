@@ -0,0 +1,21 @@
+package models
+
+// DepositEventSignature is topic0 of DepositEvent(bytes,bytes,bytes,bytes,bytes)
+const DepositEventSignature = "0x649bbc62d0e31342afea4e5cd82d4049e7e1ee912fc0889aa790803be39038c5"
+
+// DepositRecord is stored in the deposits collection, one document per deposit
+// contract DepositEvent keyed by the deposit index
+type DepositRecord struct {
+	ID                       int64  `bson:"_id" json:"-"` // Deposit index
+	Index                    int64  `bson:"index" json:"index"`
+	PublicKeyHex             string `bson:"publicKeyHex" json:"publicKeyHex"`                         // Lowercase hex without 0x, as on validator documents
+	WithdrawalCredentialsHex string `bson:"withdrawalCredentialsHex" json:"withdrawalCredentialsHex"` // Lowercase hex without 0x
+	Amount                   string `bson:"amount" json:"amount"`                                     // Decimal string, same unit as effectiveBalance
+	SignatureHex             string `bson:"signatureHex" json:"signatureHex"`
+	Sender                   string `bson:"sender" json:"sender"`
+	TxHash                   string `bson:"txHash" json:"txHash"`
+	BlockNumber              string `bson:"blockNumber" json:"blockNumber"` // hex string
+	BlockTimestamp           string `bson:"blockTimestamp" json:"blockTimestamp"`
+	LogIndex                 string `bson:"logIndex" json:"logIndex"`
+	ValidatorIndex           *int64 `bson:"validatorIndex,omitempty" json:"validatorIndex"` // Nil until the validator appears on the beacon chain
+}
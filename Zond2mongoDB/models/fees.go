@@ -0,0 +1,47 @@
+package models
+
+// FeeHistoryPercentiles are the effective priority fee percentiles requested
+// for every block, weighted by gas used as defined by zond_feeHistory
+var FeeHistoryPercentiles = []float64{10, 25, 50, 75, 90}
+
+// ZondFeeHistoryResponse represents the response from zond_feeHistory
+type ZondFeeHistoryResponse struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Result  struct {
+		OldestBlock   string     `json:"oldestBlock"`
+		BaseFeePerGas []string   `json:"baseFeePerGas"` // One entry per block plus the next block
+		GasUsedRatio  []float64  `json:"gasUsedRatio"`
+		Reward        [][]string `json:"reward"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// FeeHistoryRecord is stored in the feeHistory collection, one document per block.
+// Fee values are decimal strings in wei.
+type FeeHistoryRecord struct {
+	ID                int64     `bson:"_id" json:"-"` // Block number
+	BlockNumber       string    `bson:"blockNumber" json:"blockNumber"`
+	BaseFeePerGas     string    `bson:"baseFeePerGas" json:"baseFeePerGas"`
+	NextBaseFeePerGas string    `bson:"nextBaseFeePerGas" json:"nextBaseFeePerGas"`
+	GasUsedRatio      float64   `bson:"gasUsedRatio" json:"gasUsedRatio"`
+	GasUsed           string    `bson:"gasUsed" json:"gasUsed"`
+	BurntFee          string    `bson:"burntFee" json:"burntFee"` // baseFeePerGas * gasUsed
+	Percentiles       []float64 `bson:"percentiles" json:"percentiles"`
+	PriorityFees      []string  `bson:"priorityFees" json:"priorityFees"` // Effective priority fee at each percentile
+	Timestamp         int64     `bson:"timestamp" json:"timestamp"`
+}
+
+// TxFees is the EIP-1559 fee breakdown of a mined transaction. Values are
+// decimal strings in wei; BurntFee + PriorityFee == TotalFee.
+type TxFees struct {
+	GasUsed           string `bson:"gasUsed" json:"gasUsed"`
+	EffectiveGasPrice string `bson:"effectiveGasPrice" json:"effectiveGasPrice"`
+	BaseFeePerGas     string `bson:"baseFeePerGas" json:"baseFeePerGas"`
+	BurntFee          string `bson:"burntFee" json:"burntFee"`
+	PriorityFee       string `bson:"priorityFee" json:"priorityFee"` // Tip paid to the block proposer
+	TotalFee          string `bson:"totalFee" json:"totalFee"`
+}
@@ -0,0 +1,43 @@
+package models
+
+// Time-series bucket intervals and their length in seconds
+const (
+	StatsIntervalHour = "hour"
+	StatsIntervalDay  = "day"
+
+	StatsHourSeconds int64 = 3600
+	StatsDaySeconds  int64 = 86400
+)
+
+// StatsIntervals lists the bucket intervals maintained by the aggregator
+var StatsIntervals = map[string]int64{
+	StatsIntervalHour: StatsHourSeconds,
+	StatsIntervalDay:  StatsDaySeconds,
+}
+
+// NetworkStatsRecord is one hourly or daily bucket of the networkStats
+// collection. Sums are kept rather than averages so buckets can be extended
+// incrementally; wei amounts are decimal strings.
+type NetworkStatsRecord struct {
+	ID                  string `bson:"_id"` // "<interval>:<timestamp>"
+	Interval            string `bson:"interval"`
+	Timestamp           int64  `bson:"timestamp"` // Bucket start, Unix seconds UTC
+	BlockCount          int64  `bson:"blockCount"`
+	BlockTimeSum        int64  `bson:"blockTimeSum"` // Seconds since each block's parent
+	TxCount             int64  `bson:"txCount"`
+	ActiveAddresses     int64  `bson:"activeAddresses"`
+	NewAddresses        int64  `bson:"newAddresses"`
+	GasUsed             int64  `bson:"gasUsed"`
+	TotalFees           string `bson:"totalFees"`
+	FeeTxCount          int64  `bson:"feeTxCount"` // Transactions with a stored receipt, the divisor of the average fee
+	BurntFees           string `bson:"burntFees"`
+	ContractDeployments int64  `bson:"contractDeployments"`
+	TokenTransfers      int64  `bson:"tokenTransfers"`
+	UpdatedAt           int64  `bson:"updatedAt"`
+}
+
+// StatsBucketStart returns the start of the bucket of the given interval containing timestamp
+func StatsBucketStart(timestamp int64, interval string) int64 {
+	size := StatsIntervals[interval]
+	return timestamp - timestamp%size
+}
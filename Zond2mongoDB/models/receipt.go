@@ -6,29 +6,76 @@ type TransactionReceipt struct {
 	ID      int    `json:"id"`
 	Result  struct {
 		BlockHash         string `json:"blockHash"`
-		BlockNumber      string `json:"blockNumber"`
-		ContractAddress  string `json:"contractAddress"`
+		BlockNumber       string `json:"blockNumber"`
+		ContractAddress   string `json:"contractAddress"`
 		CumulativeGasUsed string `json:"cumulativeGasUsed"`
-		From             string `json:"from"`
-		GasUsed         string `json:"gasUsed"`
-		Logs            []Log  `json:"logs"`
-		LogsBloom       string `json:"logsBloom"`
-		Status          string `json:"status"`
-		To              string `json:"to"`
-		TransactionHash string `json:"transactionHash"`
-		TransactionIndex string `json:"transactionIndex"`
+		EffectiveGasPrice string `json:"effectiveGasPrice"`
+		From              string `json:"from"`
+		GasUsed           string `json:"gasUsed"`
+		Logs              []Log  `json:"logs"`
+		LogsBloom         string `json:"logsBloom"`
+		Status            string `json:"status"`
+		To                string `json:"to"`
+		TransactionHash   string `json:"transactionHash"`
+		TransactionIndex  string `json:"transactionIndex"`
+		Type              string `json:"type"`
 	} `json:"result"`
 }
 
 // Log represents a log entry in a transaction receipt
 type Log struct {
-	Address          string   `json:"address"`
-	Topics          []string `json:"topics"`
-	Data            string   `json:"data"`
-	BlockNumber     string   `json:"blockNumber"`
-	TransactionHash string   `json:"transactionHash"`
-	TransactionIndex string  `json:"transactionIndex"`
-	BlockHash       string   `json:"blockHash"`
-	LogIndex        string   `json:"logIndex"`
-	Removed         bool     `json:"removed"`
+	Address          string   `json:"address" bson:"address"`
+	Topics           []string `json:"topics" bson:"topics"`
+	Data             string   `json:"data" bson:"data"`
+	BlockNumber      string   `json:"blockNumber" bson:"blockNumber"`
+	TransactionHash  string   `json:"transactionHash" bson:"transactionHash"`
+	TransactionIndex string   `json:"transactionIndex" bson:"transactionIndex"`
+	BlockHash        string   `json:"blockHash" bson:"blockHash"`
+	LogIndex         string   `json:"logIndex" bson:"logIndex"`
+	Removed          bool     `json:"removed" bson:"removed"`
+}
+
+// ReceiptRecord is a transaction receipt as stored in the receipts collection,
+// keyed by transaction hash. Quantities keep the node's hex encoding.
+type ReceiptRecord struct {
+	TxHash            string `bson:"_id"`
+	BlockHash         string `bson:"blockHash"`
+	BlockNumber       string `bson:"blockNumber"`
+	TransactionIndex  string `bson:"transactionIndex"`
+	From              string `bson:"from"`
+	To                string `bson:"to,omitempty"`
+	ContractAddress   string `bson:"contractAddress,omitempty"` // Set for contract creations
+	Status            string `bson:"status"`
+	Type              string `bson:"type"`
+	GasUsed           string `bson:"gasUsed"`
+	CumulativeGasUsed string `bson:"cumulativeGasUsed"`
+	EffectiveGasPrice string `bson:"effectiveGasPrice"`
+	LogsBloom         string `bson:"logsBloom"`
+	Logs              []Log  `bson:"logs"`
+	Timestamp         string `bson:"timestamp"` // Block timestamp
+}
+
+// NewReceiptRecord converts a receipt response into its stored form
+func NewReceiptRecord(receipt *TransactionReceipt, blockTimestamp string) ReceiptRecord {
+	logs := receipt.Result.Logs
+	if logs == nil {
+		logs = []Log{}
+	}
+	return ReceiptRecord{
+		TxHash:            receipt.Result.TransactionHash,
+		BlockHash:         receipt.Result.BlockHash,
+		BlockNumber:       receipt.Result.BlockNumber,
+		TransactionIndex:  receipt.Result.TransactionIndex,
+		From:              receipt.Result.From,
+		To:                receipt.Result.To,
+		ContractAddress:   receipt.Result.ContractAddress,
+		Status:            receipt.Result.Status,
+		Type:              receipt.Result.Type,
+		GasUsed:           receipt.Result.GasUsed,
+		CumulativeGasUsed: receipt.Result.CumulativeGasUsed,
+		EffectiveGasPrice: receipt.Result.EffectiveGasPrice,
+		LogsBloom:         receipt.Result.LogsBloom,
+		Logs:              logs,
+		Timestamp:         blockTimestamp,
+	}
 }
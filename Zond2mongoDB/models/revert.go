@@ -0,0 +1,38 @@
+package models
+
+// Revert reason kinds
+const (
+	RevertTypeError   = "error"   // Error(string) from require/revert
+	RevertTypePanic   = "panic"   // Panic(uint256) from assert, overflow, etc.
+	RevertTypeCustom  = "custom"  // Solidity custom error
+	RevertTypeVM      = "vm"      // VM failure without revert data, e.g. out of gas
+	RevertTypeUnknown = "unknown" // Revert data that could not be decoded
+)
+
+// Revert reason sources
+const (
+	RevertSourceTrace = "trace" // debug_traceTransaction
+	RevertSourceCall  = "call"  // zond_call replay at the parent block
+)
+
+// RevertReason explains why a transaction with receipt status 0x0 failed.
+// It is stored on the transfer document.
+type RevertReason struct {
+	Type   string `bson:"type" json:"type"`
+	Reason string `bson:"reason" json:"reason"`
+	Data   string `bson:"data,omitempty" json:"data,omitempty"` // Raw revert data
+	Source string `bson:"source" json:"source"`
+}
+
+// ZondCallResult represents a zond_call response, including the revert data
+// returned in the error of a reverted call
+type ZondCallResult struct {
+	Jsonrpc string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Result  string `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error,omitempty"`
+}
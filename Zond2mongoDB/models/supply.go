@@ -0,0 +1,25 @@
+package models
+
+// SupplyRecord is the supply breakdown stored in the supply collection. All
+// amounts are decimal strings in wei (Shor) so they stay exact.
+//
+// CurrentSupply is ExecutionBalances + Staked; TotalIssued adds the burnt
+// fees back. Circulating is ExecutionBalances - LockedInContracts.
+// WithdrawalPending is part of Staked: the balance the beacon chain owes to
+// withdrawal addresses but has not swept yet.
+type SupplyRecord struct {
+	ID                string `bson:"_id" json:"-"` // Always "current"
+	TotalIssued       string `bson:"totalIssued" json:"totalIssued"`
+	CurrentSupply     string `bson:"currentSupply" json:"currentSupply"`
+	Circulating       string `bson:"circulating" json:"circulating"`
+	ExecutionBalances string `bson:"executionBalances" json:"executionBalances"` // Sum of known address balances, deposit contract excluded
+	LockedInContracts string `bson:"lockedInContracts" json:"lockedInContracts"` // Balances of contract addresses
+	Staked            string `bson:"staked" json:"staked"`                       // Sum of validator balances
+	WithdrawalPending string `bson:"withdrawalPending" json:"withdrawalPending"`
+	BurntFees         string `bson:"burntFees" json:"burntFees"`
+	AddressCount      int64  `bson:"addressCount" json:"addressCount"`
+	MissingBalances   int64  `bson:"missingBalances" json:"missingBalances"` // Addresses left out for lack of an exact balance
+	ValidatorEpoch    int64  `bson:"validatorEpoch" json:"validatorEpoch"`   // Epoch of the validator balance snapshot
+	BurntFeesBlock    int64  `bson:"burntFeesBlock" json:"burntFeesBlock"`   // Last block included in BurntFees
+	UpdatedAt         int64  `bson:"updatedAt" json:"updatedAt"`
+}
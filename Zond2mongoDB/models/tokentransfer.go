@@ -13,4 +13,8 @@ type TokenTransfer struct {
 	TokenDecimals   uint8  `bson:"tokenDecimals"`
 	TokenName       string `bson:"tokenName"`
 	TransferType    string `bson:"transferType"` // "direct" for direct transfers, "event" for Transfer events
+	// Chain position used for keyset pagination; direct transfers have log index -1
+	BlockNum int64 `bson:"blockNum"`
+	TxIndex  int64 `bson:"txIndex"`
+	LogIndex int64 `bson:"logIndex"`
 }
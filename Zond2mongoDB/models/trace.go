@@ -19,6 +19,8 @@ type TraceResult struct {
 	Calls        []Call `json:"calls"`
 	Value        string `json:"value"`
 	TraceAddress []int  `json:"traceAddress"`
+	Error        string `json:"error"`
+	RevertReason string `json:"revertReason"`
 }
 
 type Call struct {
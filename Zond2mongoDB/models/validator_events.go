@@ -0,0 +1,37 @@
+package models
+
+// Validator event types
+const (
+	ValidatorEventDeposited    = "deposited"
+	ValidatorEventActivated    = "activated"
+	ValidatorEventExit         = "exit"
+	ValidatorEventSlashed      = "slashed"
+	ValidatorEventWithdrawable = "withdrawable"
+)
+
+// Validator exit reasons
+const (
+	ExitReasonVoluntary = "voluntary_exit"
+	ExitReasonSlashed   = "slashed"
+)
+
+// Slashing reasons
+const (
+	SlashingReasonProposer = "proposer_slashing"
+	SlashingReasonAttester = "attester_slashing"
+)
+
+// FarFutureEpoch is the epoch value used by the beacon chain for "never"
+const FarFutureEpoch = "18446744073709551615"
+
+// ValidatorEventRecord is stored in the validator_events collection whenever a
+// refresh observes a validator changing state
+type ValidatorEventRecord struct {
+	ValidatorIndex int64  `bson:"validatorIndex" json:"validatorIndex"`
+	PublicKeyHex   string `bson:"publicKeyHex" json:"publicKeyHex"`
+	Type           string `bson:"type" json:"type"`                         // deposited, activated, exit, slashed or withdrawable
+	Epoch          int64  `bson:"epoch" json:"epoch"`                       // Epoch the transition takes effect
+	Reason         string `bson:"reason,omitempty" json:"reason,omitempty"` // Set for exits
+	DetectedEpoch  int64  `bson:"detectedEpoch" json:"detectedEpoch"`       // Epoch of the refresh that observed it
+	Timestamp      int64  `bson:"timestamp" json:"timestamp"`
+}
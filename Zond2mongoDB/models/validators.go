@@ -3,7 +3,9 @@ package models
 import (
 	"encoding/base64"
 	"encoding/hex"
+	"math"
 	"strconv"
+	"strings"
 )
 
 // Legacy validator models
@@ -47,26 +49,38 @@ type ValidatorDetails struct {
 	WithdrawableEpoch          string `json:"withdrawableEpoch"`
 }
 
-// MongoDB storage model
-type ValidatorStorage struct {
-	ID         string            `bson:"_id" json:"_id"`
-	Epoch      string            `bson:"epoch" json:"epoch"`           // Stored as decimal
-	Validators []ValidatorRecord `bson:"validators" json:"validators"` // All validator details
-	UpdatedAt  string            `bson:"updatedAt" json:"updatedAt"`   // Unix timestamp
-}
-
+// ValidatorRecord is stored as one document per validator, keyed by index
 type ValidatorRecord struct {
-	Index                      string `bson:"index" json:"index"`                                       // Decimal string
-	PublicKeyHex               string `bson:"publicKeyHex" json:"publicKeyHex"`                         // Converted from base64 to hex
-	WithdrawalCredentialsHex   string `bson:"withdrawalCredentialsHex" json:"withdrawalCredentialsHex"` // Converted from base64 to hex
-	EffectiveBalance           string `bson:"effectiveBalance" json:"effectiveBalance"`                 // Decimal string
+	ID                         int64  `bson:"_id" json:"-"`                                                   // Numeric validator index
+	Index                      string `bson:"index" json:"index"`                                             // Decimal string
+	PublicKeyHex               string `bson:"publicKeyHex" json:"publicKeyHex"`                               // Converted from base64 to hex
+	WithdrawalCredentialsHex   string `bson:"withdrawalCredentialsHex" json:"withdrawalCredentialsHex"`       // Converted from base64 to hex
+	WithdrawalAddress          string `bson:"withdrawalAddress,omitempty" json:"withdrawalAddress,omitempty"` // Z-address of 0x01 credentials
+	EffectiveBalance           string `bson:"effectiveBalance" json:"effectiveBalance"`                       // Decimal string
 	Slashed                    bool   `bson:"slashed" json:"slashed"`
 	ActivationEligibilityEpoch string `bson:"activationEligibilityEpoch" json:"activationEligibilityEpoch"` // Decimal string
 	ActivationEpoch            string `bson:"activationEpoch" json:"activationEpoch"`                       // Decimal string
 	ExitEpoch                  string `bson:"exitEpoch" json:"exitEpoch"`                                   // Decimal string
 	WithdrawableEpoch          string `bson:"withdrawableEpoch" json:"withdrawableEpoch"`                   // Decimal string
-	SlotNumber                 string `bson:"slotNumber" json:"slotNumber"`                                 // Decimal string
-	IsLeader                   bool   `bson:"isLeader" json:"isLeader"`
+	ActivationEpochNum         int64  `bson:"activationEpochNum" json:"-"`                                  // ActivationEpoch for indexed range queries
+	ExitEpochNum               int64  `bson:"exitEpochNum" json:"-"`                                        // ExitEpoch for indexed range queries
+	Status                     string `bson:"status" json:"status"`                                         // Status as of Epoch
+	Epoch                      string `bson:"epoch" json:"epoch"`                                           // Epoch of the last refresh
+	UpdatedAt                  int64  `bson:"updatedAt" json:"updatedAt"`                                   // Unix timestamp
+
+	// Maintained by the attestation tracker; omitted here so refreshes don't reset it
+	AttestationPerformance *AttestationPerformance `bson:"attestationPerformance,omitempty" json:"attestationPerformance,omitempty"`
+}
+
+// WithdrawalAddressFromCredentials returns the Z-address encoded in 0x01
+// withdrawal credentials (prefix, 11 zero bytes, 20 byte address), or an empty
+// string for any other credential type
+func WithdrawalAddressFromCredentials(credentialsHex string) string {
+	credentialsHex = strings.ToLower(credentialsHex)
+	if len(credentialsHex) != 64 || !strings.HasPrefix(credentialsHex, "01"+strings.Repeat("00", 11)) {
+		return ""
+	}
+	return "Z" + credentialsHex[24:]
 }
 
 // Helper methods for base64 to hex conversion
@@ -119,15 +133,15 @@ func (v *ValidatorDetails) ToAddress() string {
 
 // EpochInfo represents the current epoch state from beacon chain
 type EpochInfo struct {
-	ID              string `bson:"_id" json:"_id"`                           // Always "current"
-	HeadEpoch       string `bson:"headEpoch" json:"headEpoch"`               // Current head epoch
-	HeadSlot        string `bson:"headSlot" json:"headSlot"`                 // Current head slot
-	FinalizedEpoch  string `bson:"finalizedEpoch" json:"finalizedEpoch"`     // Last finalized epoch
-	JustifiedEpoch  string `bson:"justifiedEpoch" json:"justifiedEpoch"`     // Last justified epoch
-	FinalizedSlot   string `bson:"finalizedSlot" json:"finalizedSlot"`       // Last finalized slot
-	JustifiedSlot   string `bson:"justifiedSlot" json:"justifiedSlot"`       // Last justified slot
-	GenesisTime     string `bson:"genesisTime" json:"genesisTime"`           // Genesis timestamp
-	UpdatedAt       int64  `bson:"updatedAt" json:"updatedAt"`               // Last update timestamp
+	ID             string `bson:"_id" json:"_id"`                       // Always "current"
+	HeadEpoch      string `bson:"headEpoch" json:"headEpoch"`           // Current head epoch
+	HeadSlot       string `bson:"headSlot" json:"headSlot"`             // Current head slot
+	FinalizedEpoch string `bson:"finalizedEpoch" json:"finalizedEpoch"` // Last finalized epoch
+	JustifiedEpoch string `bson:"justifiedEpoch" json:"justifiedEpoch"` // Last justified epoch
+	FinalizedSlot  string `bson:"finalizedSlot" json:"finalizedSlot"`   // Last finalized slot
+	JustifiedSlot  string `bson:"justifiedSlot" json:"justifiedSlot"`   // Last justified slot
+	GenesisTime    string `bson:"genesisTime" json:"genesisTime"`       // Genesis timestamp
+	UpdatedAt      int64  `bson:"updatedAt" json:"updatedAt"`           // Last update timestamp
 }
 
 // BeaconChainHeadResponse represents the response from beacon chain head endpoint
@@ -160,6 +174,16 @@ type ValidatorHistoryRecord struct {
 	TotalStaked     string `bson:"totalStaked" json:"totalStaked"` // Sum of effective balances
 }
 
+// EpochNumber parses a decimal epoch for range queries. FarFutureEpoch does
+// not fit an int64, so it and unparsable values become math.MaxInt64.
+func EpochNumber(epoch string) int64 {
+	n, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return math.MaxInt64
+	}
+	return n
+}
+
 // GetValidatorStatus computes the validator status based on current epoch
 func GetValidatorStatus(activationEpoch, exitEpoch string, slashed bool, currentEpoch int64) string {
 	activation, _ := strconv.ParseInt(activationEpoch, 10, 64)
@@ -176,3 +200,57 @@ func GetValidatorStatus(activationEpoch, exitEpoch string, slashed bool, current
 	}
 	return "active"
 }
+
+// ValidatorSyncCheckpoint tracks progress of a validator refresh so an
+// interrupted run can resume from the last stored page
+type ValidatorSyncCheckpoint struct {
+	ID             string `bson:"_id" json:"_id"`                       // Always "validator_refresh"
+	Epoch          string `bson:"epoch" json:"epoch"`                   // Epoch the refresh was started for
+	PageToken      string `bson:"pageToken" json:"pageToken"`           // Token of the next page to fetch
+	PagesProcessed int    `bson:"pagesProcessed" json:"pagesProcessed"` // Pages stored so far
+	TotalSize      int    `bson:"totalSize" json:"totalSize"`           // Validator set size reported by the beacon API
+	Completed      bool   `bson:"completed" json:"completed"`
+	UpdatedAt      int64  `bson:"updatedAt" json:"updatedAt"`
+}
+
+// BeaconValidatorBalancesResponse represents a page of the beacon chain balances endpoint
+type BeaconValidatorBalancesResponse struct {
+	Epoch         string                   `json:"epoch"`
+	Balances      []BeaconValidatorBalance `json:"balances"`
+	NextPageToken string                   `json:"nextPageToken"`
+	TotalSize     int                      `json:"totalSize"`
+}
+
+type BeaconValidatorBalance struct {
+	PublicKey string `json:"publicKey"`
+	Index     string `json:"index"`
+	Balance   string `json:"balance"` // Decimal string, gwei
+	Status    string `json:"status"`
+}
+
+// ValidatorBalanceSnapshot is a per-validator balance snapshot for one epoch.
+// Reward, Penalty and Withdrawn are the changes since PreviousEpoch, in gwei.
+type ValidatorBalanceSnapshot struct {
+	ValidatorIndex   int64  `bson:"validatorIndex" json:"validatorIndex"`
+	Epoch            int64  `bson:"epoch" json:"epoch"`
+	PreviousEpoch    *int64 `bson:"previousEpoch,omitempty" json:"previousEpoch"` // Nil for a validator's first snapshot
+	Balance          string `bson:"balance" json:"balance"`                       // Decimal string, gwei
+	EffectiveBalance string `bson:"effectiveBalance" json:"effectiveBalance"`     // Decimal string, gwei
+	Reward           int64  `bson:"reward" json:"reward"`
+	Penalty          int64  `bson:"penalty" json:"penalty"`
+	Withdrawn        int64  `bson:"withdrawn" json:"withdrawn"`
+	Timestamp        int64  `bson:"timestamp" json:"timestamp"`
+}
+
+// NetworkRewardsRecord holds network-wide balance totals for one snapshot epoch
+type NetworkRewardsRecord struct {
+	Epoch                 int64  `bson:"epoch" json:"epoch"`
+	PreviousEpoch         *int64 `bson:"previousEpoch,omitempty" json:"previousEpoch"` // Nil for the first snapshot
+	ValidatorsCount       int    `bson:"validatorsCount" json:"validatorsCount"`
+	TotalBalance          string `bson:"totalBalance" json:"totalBalance"`                   // Decimal string, gwei
+	TotalEffectiveBalance string `bson:"totalEffectiveBalance" json:"totalEffectiveBalance"` // Decimal string, gwei
+	Rewards               int64  `bson:"rewards" json:"rewards"`
+	Penalties             int64  `bson:"penalties" json:"penalties"`
+	Withdrawn             int64  `bson:"withdrawn" json:"withdrawn"`
+	Timestamp             int64  `bson:"timestamp" json:"timestamp"`
+}
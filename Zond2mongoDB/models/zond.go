@@ -22,6 +22,12 @@ type ZondDatabaseBlock struct {
 	Jsonrpc string `json:"jsonrpc"`
 	ID      int    `json:"id"`
 	Result  Result `json:"result"`
+	// BlockNum is Result.Number as an integer so blocks can be paged by number
+	BlockNum int64 `json:"-" bson:"blockNum"`
+	// TimestampNum is Result.Timestamp as an integer so blocks can be found by time
+	TimestampNum int64 `json:"-" bson:"timestampNum"`
+	// ProposerAttempts counts the runs that could not attribute the block yet
+	ProposerAttempts int `json:"-" bson:"proposerAttempts,omitempty"`
 }
 
 type Withdrawal struct {
@@ -140,6 +146,10 @@ type ZondLogsResponse struct {
 	Id      int    `json:"id"`
 	Jsonrpc string `json:"jsonrpc"`
 	Result  []Log  `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
 }
 
 type Vote struct {
@@ -0,0 +1,512 @@
+package rpc
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	genesisTimeMu sync.Mutex
+	genesisTime   int64
+)
+
+// GetGenesisTime returns the beacon chain genesis time as a unix timestamp.
+// The value never changes, so it is cached after the first successful call.
+func GetGenesisTime() (int64, error) {
+	genesisTimeMu.Lock()
+	defer genesisTimeMu.Unlock()
+
+	if genesisTime != 0 {
+		return genesisTime, nil
+	}
+
+	body, err := beaconGet("/zond/v1alpha1/node/genesis")
+	if err != nil {
+		return 0, err
+	}
+	if body == nil {
+		return 0, fmt.Errorf("genesis not available from beacon API")
+	}
+
+	var genesis models.BeaconGenesisResponse
+	if err := json.Unmarshal(body, &genesis); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal genesis response: %v", err)
+	}
+
+	t, err := time.Parse(time.RFC3339, genesis.GenesisTime)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse genesis time %q: %v", genesis.GenesisTime, err)
+	}
+
+	genesisTime = t.Unix()
+	return genesisTime, nil
+}
+
+// GetBeaconBlocksBySlot returns every beacon block the node knows for a slot,
+// canonical or not. An empty result means no block was seen for the slot.
+func GetBeaconBlocksBySlot(slot int64) ([]models.BeaconBlockInfo, error) {
+	body, err := beaconGet("/zond/v1alpha1/blocks?slot=" + strconv.FormatInt(slot, 10))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, nil
+	}
+
+	var response models.BeaconBlocksResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal beacon blocks response: %v", err)
+	}
+
+	blocks := make([]models.BeaconBlockInfo, 0, len(response.BlockContainers))
+	for _, raw := range response.BlockContainers {
+		info, err := parseBeaconBlockContainer(raw)
+		if err != nil {
+			zap.L().Warn("Skipping undecodable beacon block container",
+				zap.Int64("slot", slot),
+				zap.Error(err))
+			continue
+		}
+		blocks = append(blocks, *info)
+	}
+
+	return blocks, nil
+}
+
+// GetCanonicalBeaconBlock returns the canonical beacon block for a slot, or
+// nil if the slot has no canonical block
+func GetCanonicalBeaconBlock(slot int64) (*models.BeaconBlockInfo, error) {
+	blocks, err := GetBeaconBlocksBySlot(slot)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blocks {
+		if blocks[i].Canonical {
+			return &blocks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetBeaconBlocksByEpoch returns every beacon block the node knows for the
+// slots of an epoch, canonical or not
+func GetBeaconBlocksByEpoch(epoch int64) ([]models.BeaconBlockInfo, error) {
+	var blocks []models.BeaconBlockInfo
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("epoch", strconv.FormatInt(epoch, 10))
+		params.Set("page_size", strconv.Itoa(configs.SLOTS_PER_EPOCH))
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		body, err := beaconGet("/zond/v1alpha1/blocks?" + params.Encode())
+		if err != nil {
+			return nil, err
+		}
+		if body == nil {
+			return blocks, nil
+		}
+
+		var page models.BeaconBlocksResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal beacon blocks response: %v", err)
+		}
+
+		for _, raw := range page.BlockContainers {
+			info, err := parseBeaconBlockContainer(raw)
+			if err != nil {
+				zap.L().Warn("Skipping undecodable beacon block container",
+					zap.Int64("epoch", epoch),
+					zap.Error(err))
+				continue
+			}
+			blocks = append(blocks, *info)
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		if page.NextPageToken == pageToken {
+			return nil, fmt.Errorf("beacon API returned repeated page token %q", page.NextPageToken)
+		}
+		pageToken = page.NextPageToken
+	}
+
+	return blocks, nil
+}
+
+// GetCanonicalBeaconBlocksByEpoch returns the canonical beacon blocks of an
+// epoch keyed by slot. Slots without a canonical block are absent.
+func GetCanonicalBeaconBlocksByEpoch(epoch int64) (map[int64]*models.BeaconBlockInfo, error) {
+	all, err := GetBeaconBlocksByEpoch(epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make(map[int64]*models.BeaconBlockInfo)
+	for i := range all {
+		if all[i].Canonical {
+			blocks[all[i].Slot] = &all[i]
+		}
+	}
+	return blocks, nil
+}
+
+// slashingSearchEpochs bounds how many epochs are searched for the block that
+// slashed a validator
+const slashingSearchEpochs = 32
+
+// slashingFinder looks up the beacon blocks that slashed validators, fetching
+// each epoch's blocks at most once
+type slashingFinder struct {
+	epochs map[int64][]models.BeaconSlashing
+}
+
+func newSlashingFinder() *slashingFinder {
+	return &slashingFinder{epochs: make(map[int64][]models.BeaconSlashing)}
+}
+
+// Find returns the slashing of a validator included after afterEpoch and up to
+// upToEpoch, searching the newest epochs first. It returns nil if no slashing
+// is found within slashingSearchEpochs.
+func (f *slashingFinder) Find(validatorIndex, afterEpoch, upToEpoch int64) (*models.BeaconSlashing, error) {
+	from := max(afterEpoch+1, upToEpoch-slashingSearchEpochs+1, 0)
+	for epoch := upToEpoch; epoch >= from; epoch-- {
+		slashings, ok := f.epochs[epoch]
+		if !ok {
+			blocks, err := GetCanonicalBeaconBlocksByEpoch(epoch)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get beacon blocks for epoch %d: %w", epoch, err)
+			}
+			slashings = []models.BeaconSlashing{}
+			for _, b := range blocks {
+				slashings = append(slashings, b.Slashings...)
+			}
+			f.epochs[epoch] = slashings
+		}
+		for i := range slashings {
+			if slashings[i].ValidatorIndex == validatorIndex {
+				return &slashings[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// parseBeaconBlockContainer decodes a block container. The signed block is held
+// under a fork-specific key (e.g. "capellaBlock"), so it is located by suffix.
+func parseBeaconBlockContainer(raw json.RawMessage) (*models.BeaconBlockInfo, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	info := &models.BeaconBlockInfo{}
+	if v, ok := fields["blockRoot"]; ok {
+		var root string
+		if err := json.Unmarshal(v, &root); err == nil {
+			info.BlockRoot = base64ToPrefixedHex(root)
+		}
+	}
+	if v, ok := fields["canonical"]; ok {
+		_ = json.Unmarshal(v, &info.Canonical)
+	}
+
+	var signed *models.SignedBeaconBlock
+	for key, v := range fields {
+		if key == "blockRoot" || !strings.HasSuffix(key, "Block") {
+			continue
+		}
+		var candidate models.SignedBeaconBlock
+		if err := json.Unmarshal(v, &candidate); err == nil && candidate.Block.Slot != "" {
+			signed = &candidate
+			break
+		}
+	}
+	if signed == nil {
+		return nil, fmt.Errorf("no signed block in container")
+	}
+
+	slot, err := strconv.ParseInt(signed.Block.Slot, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slot %q: %v", signed.Block.Slot, err)
+	}
+	proposer, err := strconv.ParseInt(signed.Block.ProposerIndex, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposer index %q: %v", signed.Block.ProposerIndex, err)
+	}
+
+	info.Slot = slot
+	info.ProposerIndex = proposer
+	info.ParentRoot = base64ToPrefixedHex(signed.Block.ParentRoot)
+	info.Graffiti = decodeGraffiti(signed.Block.Body.Graffiti)
+	info.ExecutionBlockHash = base64ToPrefixedHex(signed.Block.Body.ExecutionPayload.BlockHash)
+	info.ExecutionBlockNumber = signed.Block.Body.ExecutionPayload.BlockNumber
+	for _, d := range signed.Block.Body.Deposits {
+		amount, err := strconv.ParseInt(d.Data.Amount, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deposit amount %q: %v", d.Data.Amount, err)
+		}
+		info.Deposits = append(info.Deposits, models.BeaconDeposit{
+			PublicKeyHex: models.Base64ToHex(d.Data.PublicKey),
+			Amount:       amount,
+		})
+	}
+
+	epoch := slot / configs.SLOTS_PER_EPOCH
+	for _, ps := range signed.Block.Body.ProposerSlashings {
+		index, err := strconv.ParseInt(ps.Header1.Header.ProposerIndex, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slashed proposer index %q: %v", ps.Header1.Header.ProposerIndex, err)
+		}
+		info.Slashings = append(info.Slashings, models.BeaconSlashing{
+			ValidatorIndex: index,
+			Epoch:          epoch,
+			Reason:         models.SlashingReasonProposer,
+		})
+	}
+	// An attester slashing slashes the validators that signed both attestations
+	for _, as := range signed.Block.Body.AttesterSlashings {
+		first := make(map[string]bool, len(as.Attestation1.AttestingIndices))
+		for _, i := range as.Attestation1.AttestingIndices {
+			first[i] = true
+		}
+		for _, i := range as.Attestation2.AttestingIndices {
+			if !first[i] {
+				continue
+			}
+			index, err := strconv.ParseInt(i, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid slashed attester index %q: %v", i, err)
+			}
+			info.Slashings = append(info.Slashings, models.BeaconSlashing{
+				ValidatorIndex: index,
+				Epoch:          epoch,
+				Reason:         models.SlashingReasonAttester,
+			})
+		}
+	}
+
+	return info, nil
+}
+
+// beaconGet performs a GET against the beacon API. A 404 yields a nil body
+// and no error so callers can treat it as "not found".
+func beaconGet(path string) ([]byte, error) {
+	beaconchainURL := os.Getenv("BEACONCHAIN_API")
+	if beaconchainURL == "" {
+		return nil, fmt.Errorf("BEACONCHAIN_API environment variable not set")
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimRight(beaconchainURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response from beacon API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from beacon API: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	return body, nil
+}
+
+// base64ToPrefixedHex converts a base64 encoded byte field to 0x-prefixed hex
+func base64ToPrefixedHex(b64 string) string {
+	hex := models.Base64ToHex(b64)
+	if hex == "" {
+		return ""
+	}
+	return "0x" + hex
+}
+
+// decodeGraffiti converts the 32-byte graffiti field to text, dropping zero padding
+func decodeGraffiti(b64 string) string {
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(data), "\x00")
+}
+
+// GetCreditedDeposits returns the deposits processed by the canonical blocks of
+// epochs from up to but not including until, which are the deposits credited
+// between the balances reported for those two epochs
+func GetCreditedDeposits(from, until int64) ([]models.BeaconDeposit, error) {
+	var deposits []models.BeaconDeposit
+	for epoch := from; epoch < until; epoch++ {
+		blocks, err := GetCanonicalBeaconBlocksByEpoch(epoch)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			deposits = append(deposits, block.Deposits...)
+		}
+	}
+	return deposits, nil
+}
+
+// GetEffectiveBalances returns the effective balance of every validator at an
+// epoch, keyed by validator index, paging through the validators endpoint
+func GetEffectiveBalances(epoch int64) (map[int64]int64, error) {
+	balances := make(map[int64]int64)
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("epoch", strconv.FormatInt(epoch, 10))
+		params.Set("page_size", strconv.Itoa(validatorPageSize))
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		body, err := beaconGet("/zond/v1alpha1/validators?" + params.Encode())
+		if err != nil {
+			return nil, err
+		}
+		if body == nil {
+			return nil, fmt.Errorf("validators for epoch %d not available from beacon API", epoch)
+		}
+
+		var page models.BeaconValidatorResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal validators response: %v", err)
+		}
+
+		for _, v := range page.ValidatorList {
+			index, err := strconv.ParseInt(v.Index, 10, 64)
+			if err != nil {
+				continue
+			}
+			if balance, err := strconv.ParseInt(v.Validator.EffectiveBalance, 10, 64); err == nil {
+				balances[index] = balance
+			}
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		if page.NextPageToken == pageToken {
+			return nil, fmt.Errorf("beacon API returned repeated page token %q", page.NextPageToken)
+		}
+		pageToken = page.NextPageToken
+	}
+
+	return balances, nil
+}
+
+// GetProposerDuties returns the proposer validator index for each slot of an
+// epoch, paging through the assignments of the whole validator set
+func GetProposerDuties(epoch int64) (map[int64]int64, error) {
+	duties := make(map[int64]int64)
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("epoch", strconv.FormatInt(epoch, 10))
+		params.Set("page_size", strconv.Itoa(validatorPageSize))
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		body, err := beaconGet("/zond/v1alpha1/validators/assignments?" + params.Encode())
+		if err != nil {
+			return nil, err
+		}
+		if body == nil {
+			return duties, nil
+		}
+
+		var page models.BeaconAssignmentsResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal assignments response: %v", err)
+		}
+
+		for _, a := range page.Assignments {
+			index, err := strconv.ParseInt(a.ValidatorIndex, 10, 64)
+			if err != nil {
+				continue
+			}
+			for _, s := range a.ProposerSlots {
+				if slot, err := strconv.ParseInt(s, 10, 64); err == nil {
+					duties[slot] = index
+				}
+			}
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		if page.NextPageToken == pageToken {
+			return nil, fmt.Errorf("beacon API returned repeated page token %q", page.NextPageToken)
+		}
+		pageToken = page.NextPageToken
+	}
+
+	return duties, nil
+}
+
+// GetValidatorPerformance returns the previous-epoch attestation performance
+// of the given validator indices
+func GetValidatorPerformance(indices []int64) (*models.BeaconValidatorPerformanceResponse, error) {
+	params := url.Values{}
+	for _, index := range indices {
+		params.Add("indices", strconv.FormatInt(index, 10))
+	}
+
+	body, err := beaconGet("/zond/v1alpha1/validators/performance?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("validator performance not available from beacon API")
+	}
+
+	var performance models.BeaconValidatorPerformanceResponse
+	if err := json.Unmarshal(body, &performance); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal performance response: %v", err)
+	}
+	return &performance, nil
+}
+
+// GetValidatorParticipation returns network-wide participation for an epoch
+func GetValidatorParticipation(epoch int64) (*models.BeaconParticipationResponse, error) {
+	body, err := beaconGet("/zond/v1alpha1/validators/participation?epoch=" + strconv.FormatInt(epoch, 10))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("participation for epoch %d not available from beacon API", epoch)
+	}
+
+	var participation models.BeaconParticipationResponse
+	if err := json.Unmarshal(body, &participation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal participation response: %v", err)
+	}
+	return &participation, nil
+}
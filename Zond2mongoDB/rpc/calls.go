@@ -4,7 +4,6 @@ import (
 	"Zond2mongoDB/configs"
 	"Zond2mongoDB/models"
 	"Zond2mongoDB/services"
-	"Zond2mongoDB/utils"
 	"Zond2mongoDB/validation"
 	"bytes"
 	"encoding/json"
@@ -13,6 +12,7 @@ import (
 	"io/ioutil"
 	"math/big"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -224,11 +224,14 @@ func GetContractAddress(txHash string) (string, string, error) {
 	return ContractAddress.Result.ContractAddress, ContractAddress.Result.Status, nil
 }
 
-func CallDebugTraceTransaction(hash string) (transactionType string, callType string, from string, to string, input uint64, output uint64, traceAddress []int, value float32, gas uint64, gasUsed uint64, addressFunctionidentifier string, amountFunctionIdentifier uint64) {
+// CallDebugTraceTransaction traces a transaction with the call tracer. value
+// is in QRL for display; valueWei is the exact value as a decimal string and
+// callError the error of the call, empty when it succeeded.
+func CallDebugTraceTransaction(hash string) (transactionType string, callType string, from string, to string, input uint64, output uint64, traceAddress []int, value float32, gas uint64, gasUsed uint64, addressFunctionidentifier string, amountFunctionIdentifier uint64, valueWei string, callError string) {
 	// Validate transaction hash
 	if err := validation.ValidateHexString(hash, validation.HashLength); err != nil {
 		zap.L().Error("Invalid transaction hash", zap.Error(err))
-		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0
+		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0, "", ""
 	}
 
 	var tracerResponse models.TraceResponse
@@ -248,39 +251,40 @@ func CallDebugTraceTransaction(hash string) (transactionType string, callType st
 	b, err := json.Marshal(group)
 	if err != nil {
 		zap.L().Error("Failed JSON marshal", zap.Error(err))
-		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0
+		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0, "", ""
 	}
 
 	req, err := http.NewRequest("POST", os.Getenv("NODE_URL"), bytes.NewBuffer([]byte(b)))
 	if err != nil {
 		zap.L().Error("Failed to create request", zap.Error(err))
-		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0
+		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0, "", ""
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := GetHTTPClient().Do(req)
 	if err != nil {
 		zap.L().Error("Failed to execute request", zap.Error(err))
-		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0
+		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0, "", ""
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		zap.L().Error("Failed to read response body", zap.Error(err))
-		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0
+		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0, "", ""
 	}
 
 	err = json.Unmarshal([]byte(string(body)), &tracerResponse)
 	if err != nil {
 		zap.L().Error("Failed to unmarshal response", zap.Error(err))
-		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0
+		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0, "", ""
 	}
 
 	// Initialize default values for gas and gasUsed
 	gas = 0
 	gasUsed = 0
 	value = 0 // Initialize value to 0
+	valueWei = "0"
 
 	// Validate and parse gas values
 	if tracerResponse.Result.Gas != "" {
@@ -311,6 +315,7 @@ func CallDebugTraceTransaction(hash string) (transactionType string, callType st
 			// Convert hex value to big.Int
 			valueBigInt := new(big.Int)
 			valueBigInt.SetString(tracerResponse.Result.Value[2:], 16)
+			valueWei = valueBigInt.String()
 
 			// Convert to float32 (with proper scaling)
 			divisor := new(big.Float).SetFloat64(float64(configs.QUANTA))
@@ -334,7 +339,7 @@ func CallDebugTraceTransaction(hash string) (transactionType string, callType st
 		tracerResponse.Result.Type == "CALL"
 
 	if !hasValidCallData {
-		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0
+		return "", "", "", "", 0, 0, nil, 0, 0, 0, "", 0, "", ""
 	}
 
 	// Validate addresses and convert to Z format
@@ -451,7 +456,9 @@ func CallDebugTraceTransaction(hash string) (transactionType string, callType st
 		gas,
 		gasUsed,
 		addressFunctionidentifier,
-		amountFunctionIdentifier
+		amountFunctionIdentifier,
+		valueWei,
+		tracerResponse.Result.Error
 }
 
 func GetBalance(address string) (string, error) {
@@ -509,8 +516,13 @@ func GetBalance(address string) (string, error) {
 	return result.Result, nil
 }
 
-func GetValidators() error {
-	zap.L().Info("Starting GetValidators call to beacon chain API")
+// validatorPageSize is the number of validators requested per beacon API page
+const validatorPageSize = 250
+
+// GetValidators pages through the full validator set from the beacon API and
+// stores every page, checkpointing progress in sync_state
+func GetValidators(currentEpoch string) error {
+	zap.L().Info("Starting GetValidators call to beacon chain API", zap.String("epoch", currentEpoch))
 
 	beaconchainURL := os.Getenv("BEACONCHAIN_API")
 	if beaconchainURL == "" {
@@ -521,22 +533,31 @@ func GetValidators() error {
 	baseURL := strings.TrimRight(beaconchainURL, "/") + "/zond/v1alpha1/validators"
 	client := GetHTTPClient()
 
-	// Get current epoch from latest block
-	latestBlock, err := GetLatestBlock()
+	// Resume an interrupted refresh from its last checkpoint. Pages that were
+	// already stored are not fetched again; the remaining ones are stored with
+	// the current epoch.
+	checkpoint, err := services.GetValidatorSyncCheckpoint()
 	if err != nil {
-		return fmt.Errorf("failed to get latest block: %v", err)
-	}
-	currentEpoch := strconv.FormatUint(uint64(utils.HexToInt(latestBlock).Int64()/128), 10)
-
-	pageToken := ""
-	maxPages := 3 // Configurable based on needs
-	currentPage := 0
-
-	for currentPage < maxPages {
-		requestURL := baseURL
-		if pageToken != "" {
-			requestURL += "?page_token=" + pageToken
+		return fmt.Errorf("failed to get validator sync checkpoint: %v", err)
+	}
+	if checkpoint != nil && !checkpoint.Completed && checkpoint.PageToken != "" {
+		zap.L().Info("Resuming interrupted validator refresh",
+			zap.String("page_token", checkpoint.PageToken),
+			zap.Int("pages_processed", checkpoint.PagesProcessed),
+			zap.String("started_epoch", checkpoint.Epoch))
+		checkpoint.Epoch = currentEpoch
+	} else {
+		checkpoint = &models.ValidatorSyncCheckpoint{Epoch: currentEpoch}
+	}
+
+	slashings := newSlashingFinder()
+	for {
+		params := url.Values{}
+		params.Set("page_size", strconv.Itoa(validatorPageSize))
+		if checkpoint.PageToken != "" {
+			params.Set("page_token", checkpoint.PageToken)
 		}
+		requestURL := baseURL + "?" + params.Encode()
 
 		req, err := http.NewRequest("GET", requestURL, nil)
 		if err != nil {
@@ -566,27 +587,107 @@ func GetValidators() error {
 		}
 
 		// Store this page of validators using the validator service
-		err = services.StoreValidators(beaconResponse, currentEpoch)
+		err = services.StoreValidators(beaconResponse, currentEpoch, slashings.Find)
 		if err != nil {
 			return fmt.Errorf("failed to store validators: %v", err)
 		}
 
-		currentPage++
+		// Guard against a beacon node that keeps returning the same token
+		if beaconResponse.NextPageToken != "" && beaconResponse.NextPageToken == checkpoint.PageToken {
+			return fmt.Errorf("beacon API returned repeated page token %q", beaconResponse.NextPageToken)
+		}
 
-		// Check if there's a next page
-		if beaconResponse.NextPageToken == "" {
+		checkpoint.PagesProcessed++
+		checkpoint.TotalSize = beaconResponse.TotalSize
+		checkpoint.PageToken = beaconResponse.NextPageToken
+		checkpoint.Completed = beaconResponse.NextPageToken == ""
+		if err := services.SaveValidatorSyncCheckpoint(checkpoint); err != nil {
+			zap.L().Warn("Failed to save validator sync checkpoint", zap.Error(err))
+		}
+
+		if checkpoint.Completed {
 			break
 		}
-		pageToken = beaconResponse.NextPageToken
+	}
+
+	// Record aggregate statistics once the whole page set has been stored
+	if err := services.StoreValidatorHistory(currentEpoch); err != nil {
+		zap.L().Warn("Failed to store validator history", zap.Error(err))
 	}
 
 	zap.L().Info("Completed fetching validators",
-		zap.Int("pages_processed", currentPage),
+		zap.Int("pages_processed", checkpoint.PagesProcessed),
+		zap.Int("total_size", checkpoint.TotalSize),
 		zap.String("current_epoch", currentEpoch))
 
 	return nil
 }
 
+// GetValidatorBalances pages through the actual balances of all validators for
+// the given epoch from the beacon API
+func GetValidatorBalances(epoch string) ([]models.BeaconValidatorBalance, error) {
+	beaconchainURL := os.Getenv("BEACONCHAIN_API")
+	if beaconchainURL == "" {
+		return nil, fmt.Errorf("BEACONCHAIN_API environment variable not set")
+	}
+
+	baseURL := strings.TrimRight(beaconchainURL, "/") + "/zond/v1alpha1/validators/balances"
+	client := GetHTTPClient()
+
+	var balances []models.BeaconValidatorBalance
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("epoch", epoch)
+		params.Set("page_size", strconv.Itoa(validatorPageSize))
+		if pageToken != "" {
+			params.Set("page_token", pageToken)
+		}
+
+		req, err := http.NewRequest("GET", baseURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %v", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get response from beacon API: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status code from beacon API: %d", resp.StatusCode)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %v", err)
+		}
+
+		var page models.BeaconValidatorBalancesResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+		}
+
+		balances = append(balances, page.Balances...)
+
+		if page.NextPageToken == "" {
+			break
+		}
+		if page.NextPageToken == pageToken {
+			return nil, fmt.Errorf("beacon API returned repeated page token %q", page.NextPageToken)
+		}
+		pageToken = page.NextPageToken
+	}
+
+	zap.L().Debug("Fetched validator balances",
+		zap.String("epoch", epoch),
+		zap.Int("count", len(balances)))
+
+	return balances, nil
+}
+
 // GetBeaconChainHead fetches the current chain head information from the beacon chain API
 func GetBeaconChainHead() (*models.BeaconChainHeadResponse, error) {
 	beaconchainURL := os.Getenv("BEACONCHAIN_API")
@@ -808,6 +909,101 @@ func ZondGetBlockLogs(blockNumber string, topics []string) (*models.ZondLogsResp
 	return &responseData, nil
 }
 
+// ZondGetLogs retrieves the logs emitted by a contract over a block range,
+// optionally filtered by topics
+func ZondGetLogs(fromBlock string, toBlock string, address string, topics []string) ([]models.Log, error) {
+	filter := map[string]interface{}{
+		"fromBlock": fromBlock,
+		"toBlock":   toBlock,
+		"address":   address,
+	}
+	if len(topics) > 0 {
+		filter["topics"] = topics
+	}
+
+	group := models.JsonRPC{
+		Jsonrpc: "2.0",
+		Method:  "zond_getLogs",
+		Params:  []interface{}{filter},
+		ID:      1,
+	}
+
+	b, err := json.Marshal(group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", os.Getenv("NODE_URL"), bytes.NewBuffer(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response from RPC call: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var responseData models.ZondLogsResponse
+	if err := json.Unmarshal(body, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal logs response: %v", err)
+	}
+	if responseData.Error != nil {
+		return nil, fmt.Errorf("zond_getLogs error %d: %s", responseData.Error.Code, responseData.Error.Message)
+	}
+
+	return responseData.Result, nil
+}
+
+// ZondFeeHistory returns base fees, gas used ratios and effective priority fee
+// percentiles for blockCount blocks ending at newestBlock
+func ZondFeeHistory(blockCount int, newestBlock string, percentiles []float64) (*models.ZondFeeHistoryResponse, error) {
+	group := models.JsonRPC{
+		Jsonrpc: "2.0",
+		Method:  "zond_feeHistory",
+		Params:  []interface{}{fmt.Sprintf("0x%x", blockCount), newestBlock, percentiles},
+		ID:      1,
+	}
+
+	b, err := json.Marshal(group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", os.Getenv("NODE_URL"), bytes.NewBuffer(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response from RPC call: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var history models.ZondFeeHistoryResponse
+	if err := json.Unmarshal(body, &history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fee history response: %v", err)
+	}
+	if history.Error != nil {
+		return nil, fmt.Errorf("zond_feeHistory error %d: %s", history.Error.Code, history.Error.Message)
+	}
+
+	return &history, nil
+}
+
 // GetTxDetailsByHash retrieves transaction details by hash
 func GetTxDetailsByHash(txHash string) (*models.TransactionResult, error) {
 	zap.L().Info("Getting transaction details", zap.String("txHash", txHash))
@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"Zond2mongoDB/models"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// depositEventFields is the number of dynamic bytes arguments in a DepositEvent
+const depositEventFields = 5
+
+// GetDepositContractAddress returns the staking deposit contract address. The
+// DEPOSIT_CONTRACT_ADDRESS environment variable takes precedence over the
+// address reported by the beacon node genesis endpoint.
+func GetDepositContractAddress() (string, error) {
+	if address := os.Getenv("DEPOSIT_CONTRACT_ADDRESS"); address != "" {
+		return address, nil
+	}
+
+	body, err := beaconGet("/zond/v1alpha1/node/genesis")
+	if err != nil {
+		return "", err
+	}
+	if body == nil {
+		return "", fmt.Errorf("genesis not available from beacon API")
+	}
+
+	var genesis models.BeaconGenesisResponse
+	if err := json.Unmarshal(body, &genesis); err != nil {
+		return "", fmt.Errorf("failed to unmarshal genesis response: %v", err)
+	}
+
+	address := models.Base64ToHex(genesis.DepositContractAddress)
+	if address == "" {
+		return "", fmt.Errorf("genesis response has no deposit contract address")
+	}
+	return "Z" + address, nil
+}
+
+// DecodeDepositEvent decodes the ABI encoded data of a DepositEvent log. The
+// amount and index are little-endian uint64 values, as emitted by the contract.
+func DecodeDepositEvent(log models.Log) (*models.DepositRecord, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(log.Data, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log data: %v", err)
+	}
+
+	fields := make([][]byte, depositEventFields)
+	for i := range fields {
+		fields[i], err = abiDynamicBytes(data, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode deposit field %d: %v", i, err)
+		}
+	}
+
+	if len(fields[2]) != 8 || len(fields[4]) != 8 {
+		return nil, fmt.Errorf("unexpected amount or index length")
+	}
+	index := binary.LittleEndian.Uint64(fields[4])
+
+	return &models.DepositRecord{
+		ID:                       int64(index),
+		Index:                    int64(index),
+		PublicKeyHex:             hex.EncodeToString(fields[0]),
+		WithdrawalCredentialsHex: hex.EncodeToString(fields[1]),
+		Amount:                   strconv.FormatUint(binary.LittleEndian.Uint64(fields[2]), 10),
+		SignatureHex:             hex.EncodeToString(fields[3]),
+		TxHash:                   log.TransactionHash,
+		BlockNumber:              log.BlockNumber,
+		LogIndex:                 log.LogIndex,
+	}, nil
+}
+
+// abiDynamicBytes returns the dynamic bytes argument at position i of ABI encoded data
+func abiDynamicBytes(data []byte, i int) ([]byte, error) {
+	head := i * 32
+	if len(data) < head+32 {
+		return nil, fmt.Errorf("data too short for argument head")
+	}
+	// Bounds are compared against the remaining data so that huge values
+	// cannot overflow the sums
+	offset := new(big.Int).SetBytes(data[head : head+32])
+	if !offset.IsInt64() || offset.Int64() > int64(len(data)-32) {
+		return nil, fmt.Errorf("argument offset out of range")
+	}
+	start := int(offset.Int64())
+
+	length := new(big.Int).SetBytes(data[start : start+32])
+	if !length.IsInt64() || length.Int64() > int64(len(data)-start-32) {
+		return nil, fmt.Errorf("argument length out of range")
+	}
+	return data[start+32 : start+32+int(length.Int64())], nil
+}
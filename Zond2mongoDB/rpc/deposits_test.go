@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestAbiDynamicBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		index   int
+		want    string
+		wantErr bool
+	}{
+		{name: "first argument", data: word("20") + word("3") + "abcdef" + strings.Repeat("0", 58), want: "abcdef"},
+		{name: "second argument", data: word("40") + word("80") + word("1") + "aa" + strings.Repeat("0", 62) + word("2") + "bbcc" + strings.Repeat("0", 60), index: 1, want: "bbcc"},
+		{name: "empty bytes", data: word("20") + word("0"), want: ""},
+		{name: "missing head", data: word("20"), index: 1, wantErr: true},
+		{name: "empty data", data: "", wantErr: true},
+		{name: "offset past the data", data: word("40") + word("0"), wantErr: true},
+		{name: "offset near max int64", data: word("7fffffffffffffff") + word("0"), wantErr: true},
+		{name: "offset above uint64", data: strings.Repeat("f", 64) + word("0"), wantErr: true},
+		{name: "length past the data", data: word("20") + word("21") + strings.Repeat("0", 64), wantErr: true},
+		{name: "length near max int64", data: word("20") + word("7fffffffffffffff"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := abiDynamicBytes(data, tt.index)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %x, wanted an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("got %x, wanted %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package rpc
+
+import (
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/utils"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/sha3"
+)
+
+// Selectors of the revert payloads emitted by the Solidity compiler
+const (
+	SIG_ERROR = "08c379a0" // Error(string)
+	SIG_PANIC = "4e487b71" // Panic(uint256)
+)
+
+// customError describes a custom error by its name and canonical argument types
+type customError struct {
+	Name string
+	Args []string
+}
+
+// abiEntry is the part of a contract ABI entry needed to decode custom errors
+type abiEntry struct {
+	Type   string     `json:"type"`
+	Name   string     `json:"name"`
+	Inputs []abiParam `json:"inputs"`
+}
+
+type abiParam struct {
+	Type       string     `json:"type"`
+	Components []abiParam `json:"components"`
+}
+
+// knownCustomErrors maps selectors of widely used custom errors (OpenZeppelin
+// token, access and utility contracts) to their definitions
+var knownCustomErrors = map[string]customError{
+	"e450d38c": {"ERC20InsufficientBalance", []string{"address", "uint256", "uint256"}},
+	"96c6fd1e": {"ERC20InvalidSender", []string{"address"}},
+	"ec442f05": {"ERC20InvalidReceiver", []string{"address"}},
+	"fb8f41b2": {"ERC20InsufficientAllowance", []string{"address", "uint256", "uint256"}},
+	"e602df05": {"ERC20InvalidApprover", []string{"address"}},
+	"94280d62": {"ERC20InvalidSpender", []string{"address"}},
+	"89c62b64": {"ERC721InvalidOwner", []string{"address"}},
+	"7e273289": {"ERC721NonexistentToken", []string{"uint256"}},
+	"64283d7b": {"ERC721IncorrectOwner", []string{"address", "uint256", "address"}},
+	"177e802f": {"ERC721InsufficientApproval", []string{"address", "uint256"}},
+	"118cdaa7": {"OwnableUnauthorizedAccount", []string{"address"}},
+	"1e4fbdf7": {"OwnableInvalidOwner", []string{"address"}},
+	"e2517d3f": {"AccessControlUnauthorizedAccount", []string{"address", "bytes32"}},
+	"3ee5aeb5": {"ReentrancyGuardReentrantCall", nil},
+	"d93c0665": {"EnforcedPause", nil},
+	"8dfc202b": {"ExpectedPause", nil},
+	"5274afe7": {"SafeERC20FailedOperation", []string{"address"}},
+	"9996b315": {"AddressEmptyCode", []string{"address"}},
+	"1425ea42": {"FailedInnerCall", nil},
+}
+
+// panicReasons describes the Panic(uint256) codes defined by Solidity
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "incorrectly encoded storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// GetRevertReason explains why a mined transaction failed. The call trace is
+// tried first; if it carries no revert data the transaction is replayed with
+// zond_call against the state of the parent block. The replay does not see
+// earlier transactions of the same block, so it can disagree with the
+// original execution. contractABI is the verified ABI of the called contract,
+// if any, and is used to decode its custom errors.
+func GetRevertReason(tx *models.Transaction, contractABI string) (*models.RevertReason, error) {
+	trace, traceErr := traceTransactionResult(tx.Hash)
+	if traceErr != nil {
+		zap.L().Debug("Trace unavailable for revert reason",
+			zap.String("txHash", tx.Hash),
+			zap.Error(traceErr))
+	}
+
+	if trace != nil && trace.Error != "" {
+		if data := trace.Output; len(data) > 2 {
+			reason := DecodeRevertData(data, contractABI)
+			if reason.Type == models.RevertTypeUnknown && trace.RevertReason != "" {
+				reason.Reason = trace.RevertReason
+			}
+			reason.Source = models.RevertSourceTrace
+			return reason, nil
+		}
+		if trace.RevertReason != "" {
+			return &models.RevertReason{
+				Type:   models.RevertTypeError,
+				Reason: trace.RevertReason,
+				Source: models.RevertSourceTrace,
+			}, nil
+		}
+		// Out of gas, invalid opcode and similar failures revert without data
+		if !strings.Contains(trace.Error, "execution reverted") {
+			return &models.RevertReason{
+				Type:   models.RevertTypeVM,
+				Reason: trace.Error,
+				Source: models.RevertSourceTrace,
+			}, nil
+		}
+	}
+
+	reason, err := replayTransaction(tx, contractABI)
+	if err != nil {
+		if trace != nil && trace.Error != "" {
+			return &models.RevertReason{
+				Type:   models.RevertTypeVM,
+				Reason: trace.Error,
+				Source: models.RevertSourceTrace,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to replay transaction: %v", err)
+	}
+	return reason, nil
+}
+
+// DecodeRevertData decodes Error(string), Panic(uint256) and custom error
+// payloads. Custom errors are looked up in contractABI first and then in the
+// built-in list of common errors. Undecodable data is reported as unknown with
+// its selector.
+func DecodeRevertData(data string, contractABI string) *models.RevertReason {
+	reason := &models.RevertReason{Type: models.RevertTypeUnknown, Data: data}
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	if err != nil || len(raw) < 4 {
+		reason.Reason = "execution reverted"
+		return reason
+	}
+	selector := hex.EncodeToString(raw[:4])
+	payload := raw[4:]
+
+	switch selector {
+	case SIG_ERROR:
+		message, err := abiDynamicBytes(payload, 0)
+		if err != nil {
+			reason.Reason = "malformed Error(string) revert data"
+			return reason
+		}
+		reason.Type = models.RevertTypeError
+		reason.Reason = string(message)
+		return reason
+
+	case SIG_PANIC:
+		if len(payload) < 32 {
+			reason.Reason = "malformed Panic(uint256) revert data"
+			return reason
+		}
+		code := new(big.Int).SetBytes(payload[:32])
+		description, ok := panicReasons[code.Uint64()]
+		if !code.IsUint64() || !ok {
+			description = "unknown panic code"
+		}
+		reason.Type = models.RevertTypePanic
+		reason.Reason = fmt.Sprintf("Panic(0x%x): %s", code, description)
+		return reason
+	}
+
+	known, ok := contractCustomErrors(contractABI)[selector]
+	if !ok {
+		known, ok = knownCustomErrors[selector]
+	}
+	if !ok {
+		reason.Type = models.RevertTypeCustom
+		reason.Reason = fmt.Sprintf("custom error 0x%s", selector)
+		return reason
+	}
+	headWords := 0
+	for _, argType := range known.Args {
+		headWords += abiHeadWords(argType)
+	}
+	if len(payload) < 32*headWords {
+		reason.Reason = fmt.Sprintf("malformed %s revert data", known.Name)
+		return reason
+	}
+
+	args := make([]string, 0, len(known.Args))
+	head := 0
+	for _, argType := range known.Args {
+		arg, err := decodeABIArgument(payload, head, argType)
+		if err != nil {
+			reason.Reason = fmt.Sprintf("malformed %s revert data", known.Name)
+			return reason
+		}
+		args = append(args, arg)
+		head += abiHeadWords(argType)
+	}
+	reason.Type = models.RevertTypeCustom
+	reason.Reason = fmt.Sprintf("%s(%s)", known.Name, strings.Join(args, ", "))
+	return reason
+}
+
+// decodeABIArgument formats the argument whose head is the i-th word of ABI
+// encoded data. Arrays and tuples are not decoded and are shown by their type.
+func decodeABIArgument(data []byte, i int, argType string) (string, error) {
+	word := data[i*32 : (i+1)*32]
+	switch {
+	case strings.ContainsAny(argType, "[("):
+		return argType, nil
+	case argType == "address":
+		return "Z" + hex.EncodeToString(word[12:]), nil
+	case argType == "bool":
+		return fmt.Sprintf("%t", word[31] != 0), nil
+	case strings.HasPrefix(argType, "uint"):
+		return new(big.Int).SetBytes(word).String(), nil
+	case strings.HasPrefix(argType, "int"):
+		value := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return value.String(), nil
+	case argType == "string":
+		value, err := abiDynamicBytes(data, i)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%q", value), nil
+	case argType == "bytes":
+		value, err := abiDynamicBytes(data, i)
+		if err != nil {
+			return "", err
+		}
+		return "0x" + hex.EncodeToString(value), nil
+	default:
+		return "0x" + hex.EncodeToString(word), nil
+	}
+}
+
+// abiHeadWords returns the number of words an argument takes in the head of
+// ABI encoded data. Static tuples and fixed-size arrays of static types are
+// encoded in place; every other type takes one word.
+func abiHeadWords(argType string) int {
+	if !abiIsStatic(argType) {
+		return 1
+	}
+	if strings.HasSuffix(argType, "]") {
+		open := strings.LastIndex(argType, "[")
+		length, err := strconv.Atoi(argType[open+1 : len(argType)-1])
+		if err != nil {
+			return 1
+		}
+		return length * abiHeadWords(argType[:open])
+	}
+	if strings.HasPrefix(argType, "(") {
+		words := 0
+		for _, component := range splitTupleTypes(argType) {
+			words += abiHeadWords(component)
+		}
+		return words
+	}
+	return 1
+}
+
+// abiIsStatic reports whether a type is encoded in place rather than by offset
+func abiIsStatic(argType string) bool {
+	switch {
+	case argType == "string" || argType == "bytes" || strings.HasSuffix(argType, "[]"):
+		return false
+	case strings.HasSuffix(argType, "]"):
+		return abiIsStatic(argType[:strings.LastIndex(argType, "[")])
+	case strings.HasPrefix(argType, "("):
+		for _, component := range splitTupleTypes(argType) {
+			if !abiIsStatic(component) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// splitTupleTypes returns the component types of a tuple type such as "(uint256,(address,bool))"
+func splitTupleTypes(tuple string) []string {
+	inner := tuple[1:strings.LastIndex(tuple, ")")]
+	if inner == "" {
+		return nil
+	}
+	var components []string
+	depth, start := 0, 0
+	for i, c := range inner {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				components = append(components, inner[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(components, inner[start:])
+}
+
+// contractCustomErrors returns the custom errors declared in a contract ABI,
+// keyed by selector. An empty or invalid ABI declares none.
+func contractCustomErrors(contractABI string) map[string]customError {
+	if contractABI == "" {
+		return nil
+	}
+	var entries []abiEntry
+	if err := json.Unmarshal([]byte(contractABI), &entries); err != nil {
+		zap.L().Debug("Ignoring undecodable contract ABI", zap.Error(err))
+		return nil
+	}
+
+	declared := make(map[string]customError)
+	for _, e := range entries {
+		if e.Type != "error" {
+			continue
+		}
+		args := make([]string, 0, len(e.Inputs))
+		for _, input := range e.Inputs {
+			args = append(args, canonicalABIType(input))
+		}
+		hash := sha3.NewLegacyKeccak256()
+		hash.Write([]byte(e.Name + "(" + strings.Join(args, ",") + ")"))
+		declared[hex.EncodeToString(hash.Sum(nil)[:4])] = customError{Name: e.Name, Args: args}
+	}
+	return declared
+}
+
+// canonicalABIType returns the type of a parameter as written in a signature,
+// expanding tuples into their component types
+func canonicalABIType(p abiParam) string {
+	if !strings.HasPrefix(p.Type, "tuple") {
+		return p.Type
+	}
+	components := make([]string, 0, len(p.Components))
+	for _, c := range p.Components {
+		components = append(components, canonicalABIType(c))
+	}
+	return "(" + strings.Join(components, ",") + ")" + strings.TrimPrefix(p.Type, "tuple")
+}
+
+// traceTransactionResult returns the top-level call frame of a transaction trace
+func traceTransactionResult(hash string) (*models.TraceResult, error) {
+	group := models.JsonRPC{
+		Jsonrpc: "2.0",
+		Method:  "debug_traceTransaction",
+		Params:  []interface{}{hash, map[string]string{"tracer": "callTracer"}},
+		ID:      1,
+	}
+	b, err := json.Marshal(group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := GetHTTPClient().Post(os.Getenv("NODE_URL"), "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make RPC request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var response models.TraceResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal trace: %v", err)
+	}
+	return &response.Result, nil
+}
+
+// replayTransaction re-executes a transaction with zond_call at its parent
+// block and decodes the revert data returned in the call error
+func replayTransaction(tx *models.Transaction, contractABI string) (*models.RevertReason, error) {
+	blockNumber := utils.HexToInt(tx.BlockNumber)
+	if blockNumber.Sign() == 0 {
+		return nil, fmt.Errorf("cannot replay a genesis transaction")
+	}
+	parent := "0x" + new(big.Int).Sub(blockNumber, big.NewInt(1)).Text(16)
+
+	call := map[string]string{
+		"from":  tx.From,
+		"gas":   tx.Gas,
+		"value": tx.Value,
+		"data":  tx.Data,
+	}
+	if tx.To != "" {
+		call["to"] = tx.To
+	}
+
+	payload := models.ZondCallPayload{
+		Jsonrpc: "2.0",
+		Id:      1,
+		Method:  "zond_call",
+		Params:  []interface{}{call, parent},
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := GetHTTPClient().Post(os.Getenv("NODE_URL"), "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make RPC request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	var result models.ZondCallResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal call result: %v", err)
+	}
+	if result.Error == nil {
+		return nil, fmt.Errorf("replay at block %s did not revert", parent)
+	}
+
+	var reason *models.RevertReason
+	if len(result.Error.Data) > 2 {
+		reason = DecodeRevertData(result.Error.Data, contractABI)
+	} else {
+		reason = &models.RevertReason{Type: models.RevertTypeVM, Reason: result.Error.Message}
+	}
+	reason.Source = models.RevertSourceCall
+	return reason, nil
+}
@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"Zond2mongoDB/models"
+	"strings"
+	"testing"
+)
+
+// word left-pads a hex value to one 32-byte ABI word
+func word(value string) string {
+	return strings.Repeat("0", 64-len(value)) + value
+}
+
+func TestDecodeRevertData(t *testing.T) {
+	// Error("Not enough") encoded as offset, length and padded bytes
+	notEnough := "0x" + SIG_ERROR + word("20") + word("a") + "4e6f7420656e6f756768" + strings.Repeat("0", 44)
+
+	// A contract ABI declaring its own errors
+	stakingABI := `[
+		{"type": "function", "name": "stake", "inputs": []},
+		{"type": "error", "name": "InsufficientStake", "inputs": [
+			{"name": "account", "type": "address"},
+			{"name": "needed", "type": "uint256"},
+			{"name": "note", "type": "string"}
+		]},
+		{"type": "error", "name": "Unauthorized", "inputs": [
+			{"name": "code", "type": "int256"},
+			{"name": "locked", "type": "bool"}
+		]},
+		{"type": "error", "name": "BadOrder", "inputs": [
+			{"name": "order", "type": "tuple", "components": [
+				{"name": "amount", "type": "uint256"},
+				{"name": "owner", "type": "address"}
+			]},
+			{"name": "proof", "type": "bytes"}
+		]}
+	]`
+
+	tests := []struct {
+		name       string
+		data       string
+		abi        string
+		wantType   string
+		wantReason string
+	}{
+		{
+			name:       "empty",
+			data:       "0x",
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "execution reverted",
+		},
+		{
+			name:       "shorter than a selector",
+			data:       "0x08c379",
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "execution reverted",
+		},
+		{
+			name:       "invalid hex",
+			data:       "0xzz",
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "execution reverted",
+		},
+		{
+			name:       "error string",
+			data:       notEnough,
+			wantType:   models.RevertTypeError,
+			wantReason: "Not enough",
+		},
+		{
+			name:       "empty error string",
+			data:       "0x" + SIG_ERROR + word("20") + word("0"),
+			wantType:   models.RevertTypeError,
+			wantReason: "",
+		},
+		{
+			name:       "truncated error string",
+			data:       notEnough[:len(notEnough)-64],
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed Error(string) revert data",
+		},
+		{
+			name:       "error without arguments",
+			data:       "0x" + SIG_ERROR,
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed Error(string) revert data",
+		},
+		{
+			name:       "error string offset past the data",
+			data:       "0x" + SIG_ERROR + word("40") + word("a"),
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed Error(string) revert data",
+		},
+		{
+			name:       "error string offset near max int64",
+			data:       "0x" + SIG_ERROR + word("7fffffffffffffff") + word("a"),
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed Error(string) revert data",
+		},
+		{
+			name:       "error string length near max int64",
+			data:       "0x" + SIG_ERROR + word("20") + word("7fffffffffffffff"),
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed Error(string) revert data",
+		},
+		{
+			name:       "error string offset above uint64",
+			data:       "0x" + SIG_ERROR + strings.Repeat("f", 64) + word("a"),
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed Error(string) revert data",
+		},
+		{
+			name:       "arithmetic panic",
+			data:       "0x" + SIG_PANIC + word("11"),
+			wantType:   models.RevertTypePanic,
+			wantReason: "Panic(0x11): arithmetic overflow or underflow",
+		},
+		{
+			name:       "assertion panic",
+			data:       "0x" + SIG_PANIC + word("1"),
+			wantType:   models.RevertTypePanic,
+			wantReason: "Panic(0x1): assertion failed",
+		},
+		{
+			name:       "unknown panic code",
+			data:       "0x" + SIG_PANIC + word("99"),
+			wantType:   models.RevertTypePanic,
+			wantReason: "Panic(0x99): unknown panic code",
+		},
+		{
+			name:       "panic code above uint64",
+			data:       "0x" + SIG_PANIC + "01" + strings.Repeat("0", 62),
+			wantType:   models.RevertTypePanic,
+			wantReason: "Panic(0x1" + strings.Repeat("0", 62) + "): unknown panic code",
+		},
+		{
+			name:       "truncated panic",
+			data:       "0x" + SIG_PANIC + "11",
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed Panic(uint256) revert data",
+		},
+		{
+			name:       "known custom error",
+			data:       "0xe450d38c" + word("ab") + word("5") + word("a"),
+			wantType:   models.RevertTypeCustom,
+			wantReason: "ERC20InsufficientBalance(Z" + strings.Repeat("0", 38) + "ab, 5, 10)",
+		},
+		{
+			name:       "known custom error without arguments",
+			data:       "0x3ee5aeb5",
+			wantType:   models.RevertTypeCustom,
+			wantReason: "ReentrancyGuardReentrantCall()",
+		},
+		{
+			name:       "truncated custom error",
+			data:       "0xe450d38c" + word("ab"),
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed ERC20InsufficientBalance revert data",
+		},
+		{
+			name:       "unknown custom error",
+			data:       "0xdeadbeef",
+			wantType:   models.RevertTypeCustom,
+			wantReason: "custom error 0xdeadbeef",
+		},
+		{
+			name:       "custom error from contract ABI",
+			data:       "0x554b7c12" + word("ab") + word("64") + word("60") + word("3") + "616263" + strings.Repeat("0", 58),
+			abi:        stakingABI,
+			wantType:   models.RevertTypeCustom,
+			wantReason: "InsufficientStake(Z" + strings.Repeat("0", 38) + `ab, 100, "abc")`,
+		},
+		{
+			name:       "signed and bool arguments from contract ABI",
+			data:       "0x562af449" + strings.Repeat("f", 64) + word("1"),
+			abi:        stakingABI,
+			wantType:   models.RevertTypeCustom,
+			wantReason: "Unauthorized(-1, true)",
+		},
+		{
+			name:       "tuple argument from contract ABI",
+			data:       "0x4de7ae9d" + word("1") + word("ab") + word("60") + word("1") + "ab" + strings.Repeat("0", 62),
+			abi:        stakingABI,
+			wantType:   models.RevertTypeCustom,
+			wantReason: "BadOrder((uint256,address), 0xab)",
+		},
+		{
+			name:       "malformed string argument from contract ABI",
+			data:       "0x554b7c12" + word("ab") + word("64") + word("600"),
+			abi:        stakingABI,
+			wantType:   models.RevertTypeUnknown,
+			wantReason: "malformed InsufficientStake revert data",
+		},
+		{
+			name:       "built-in error not in contract ABI",
+			data:       "0xe450d38c" + word("ab") + word("5") + word("a"),
+			abi:        stakingABI,
+			wantType:   models.RevertTypeCustom,
+			wantReason: "ERC20InsufficientBalance(Z" + strings.Repeat("0", 38) + "ab, 5, 10)",
+		},
+		{
+			name:       "invalid contract ABI",
+			data:       "0xdeadbeef",
+			abi:        "not an abi",
+			wantType:   models.RevertTypeCustom,
+			wantReason: "custom error 0xdeadbeef",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeRevertData(tt.data, tt.abi)
+			if got.Type != tt.wantType || got.Reason != tt.wantReason {
+				t.Errorf("got %q %q, wanted %q %q", got.Type, got.Reason, tt.wantType, tt.wantReason)
+			}
+			if got.Data != tt.data {
+				t.Errorf("got data %q, wanted %q", got.Data, tt.data)
+			}
+		})
+	}
+}
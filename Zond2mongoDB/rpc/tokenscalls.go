@@ -520,9 +520,10 @@ func ProcessTransferLogs(receipt *models.TransactionReceipt) []TransferEvent {
 			}
 
 			transfers = append(transfers, TransferEvent{
-				From:   from,
-				To:     to,
-				Amount: amount.String(),
+				From:     from,
+				To:       to,
+				Amount:   amount.String(),
+				LogIndex: log.LogIndex,
 			})
 		}
 	}
@@ -531,9 +532,10 @@ func ProcessTransferLogs(receipt *models.TransactionReceipt) []TransferEvent {
 }
 
 type TransferEvent struct {
-	From   string
-	To     string
-	Amount string
+	From     string
+	To       string
+	Amount   string
+	LogIndex string
 }
 
 // TrimLeftZeros trims leading zeros from hex string
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// effectivenessWindowEpochs is the span of the exponential moving average used
+// for the rolling effectiveness score (~100 epochs, a little over a week)
+const effectivenessWindowEpochs = 100
+
+// AttestationScore rates one epoch's attestation from 0 to 1: the share of
+// correct source/target/head votes, scaled down by the inclusion distance
+func AttestationScore(r models.ValidatorAttestationRecord) float64 {
+	if !r.Included {
+		return 0
+	}
+	correct := 0
+	for _, ok := range []bool{r.CorrectSource, r.CorrectTarget, r.CorrectHead} {
+		if ok {
+			correct++
+		}
+	}
+	distance := r.InclusionDistance
+	if distance < 1 {
+		distance = 1
+	}
+	return float64(correct) / 3 / float64(distance)
+}
+
+// HasParticipationRecord reports whether attestation performance was already stored for the epoch
+func HasParticipationRecord(epoch int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := configs.NetworkParticipationCollections.CountDocuments(ctx, bson.M{"epoch": epoch})
+	if err != nil {
+		configs.Logger.Error("Failed to check participation record", zap.Error(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetActiveValidatorIndices returns the indices of active validators keyed by public key hex
+func GetActiveValidatorIndices() (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cursor, err := configs.GetValidatorCollection().Find(ctx, bson.M{"status": "active"},
+		options.Find().SetProjection(bson.M{"publicKeyHex": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query active validators", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	indices := make(map[string]int64)
+	for cursor.Next(ctx) {
+		var v models.ValidatorRecord
+		if err := cursor.Decode(&v); err != nil {
+			continue
+		}
+		indices[v.PublicKeyHex] = v.ID
+	}
+	return indices, cursor.Err()
+}
+
+// StoreAttestationPerformance stores per-validator attestation results for an
+// epoch and folds them into each validator's rolling performance
+func StoreAttestationPerformance(epoch int64, records []models.ValidatorAttestationRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	indices := make([]int64, 0, len(records))
+	operations := make([]mongo.WriteModel, 0, len(records))
+	for i := range records {
+		records[i].Epoch = epoch
+		records[i].Score = AttestationScore(records[i])
+		indices = append(indices, records[i].ValidatorIndex)
+
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"validatorIndex": records[i].ValidatorIndex, "epoch": epoch}).
+			SetUpdate(bson.M{"$set": records[i]}).
+			SetUpsert(true))
+	}
+
+	if _, err := configs.ValidatorAttestationsCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false)); err != nil {
+		configs.Logger.Error("Failed to store validator attestations", zap.Error(err))
+		return err
+	}
+
+	// Load current rolling performance for the affected validators
+	cursor, err := configs.GetValidatorCollection().Find(ctx,
+		bson.M{"_id": bson.M{"$in": indices}},
+		options.Find().SetProjection(bson.M{"attestationPerformance": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to load attestation performance", zap.Error(err))
+		return err
+	}
+	current := make(map[int64]models.AttestationPerformance)
+	for cursor.Next(ctx) {
+		var v models.ValidatorRecord
+		if err := cursor.Decode(&v); err == nil && v.AttestationPerformance != nil {
+			current[v.ID] = *v.AttestationPerformance
+		}
+	}
+	cursor.Close(ctx)
+
+	alpha := 2.0 / (effectivenessWindowEpochs + 1)
+	operations = operations[:0]
+	for _, r := range records {
+		perf, seen := current[r.ValidatorIndex]
+		if seen && perf.LastEpoch >= epoch {
+			continue
+		}
+
+		if perf.Epochs == 0 {
+			perf.Effectiveness = r.Score
+		} else {
+			perf.Effectiveness = alpha*r.Score + (1-alpha)*perf.Effectiveness
+		}
+		perf.Epochs++
+		perf.LastEpoch = epoch
+		if r.Included {
+			perf.Included++
+			perf.TotalInclusionDistance += r.InclusionDistance
+		}
+		if r.CorrectSource {
+			perf.CorrectSource++
+		}
+		if r.CorrectTarget {
+			perf.CorrectTarget++
+		}
+		if r.CorrectHead {
+			perf.CorrectHead++
+		}
+
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": r.ValidatorIndex}).
+			SetUpdate(bson.M{"$set": bson.M{"attestationPerformance": perf}}))
+	}
+
+	if len(operations) > 0 {
+		if _, err := configs.GetValidatorCollection().BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false)); err != nil {
+			configs.Logger.Error("Failed to update attestation performance", zap.Error(err))
+			return err
+		}
+	}
+
+	configs.Logger.Info("Stored attestation performance",
+		zap.Int64("epoch", epoch),
+		zap.Int("validators", len(records)))
+	return nil
+}
+
+// StoreNetworkParticipation records network-wide participation for an epoch
+func StoreNetworkParticipation(record *models.NetworkParticipationRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	record.Timestamp = time.Now().Unix()
+	_, err := configs.NetworkParticipationCollections.UpdateOne(ctx,
+		bson.M{"epoch": record.Epoch},
+		bson.M{"$set": record},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store network participation", zap.Error(err))
+	}
+	return err
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+	"math/big"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// balanceWriteBatchSize caps the number of snapshot upserts sent per bulk write
+const balanceWriteBatchSize = 1000
+
+// HasBalanceSnapshot reports whether balances have already been recorded for the epoch
+func HasBalanceSnapshot(epoch int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := configs.NetworkRewardsCollections.CountDocuments(ctx, bson.M{"epoch": epoch})
+	if err != nil {
+		configs.Logger.Error("Failed to check balance snapshot", zap.Error(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// PreviousBalanceEpoch returns the epoch of the latest snapshot before epoch,
+// or false if there is none
+func PreviousBalanceEpoch(epoch int64) (int64, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	previous, found, err := findPreviousSnapshot(ctx, epoch)
+	return previous.Epoch, found, err
+}
+
+// StoreValidatorBalances records a balance snapshot for every validator at the
+// given epoch and derives rewards, penalties and withdrawals against the
+// previous snapshot. effective holds the effective balances at epoch and
+// deposits the deposits the beacon chain credited since the previous snapshot.
+// Network-wide totals are stored in network_rewards.
+func StoreValidatorBalances(epoch int64, balances []models.BeaconValidatorBalance, effective map[int64]int64, deposits []models.BeaconDeposit) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	previous, found, err := findPreviousSnapshot(ctx, epoch)
+	if err != nil {
+		return err
+	}
+	previousBalances := make(map[int64]snapshotBalance)
+	if found {
+		if previousBalances, err = loadSnapshotBalances(ctx, previous.Epoch); err != nil {
+			return err
+		}
+	}
+
+	credited, err := depositsByValidator(ctx, deposits)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	totalBalance := big.NewInt(0)
+	totalEffective := big.NewInt(0)
+	network := models.NetworkRewardsRecord{
+		Epoch:     epoch,
+		Timestamp: now,
+	}
+	if found {
+		network.PreviousEpoch = &previous.Epoch
+	}
+
+	operations := make([]mongo.WriteModel, 0, balanceWriteBatchSize)
+	flush := func() error {
+		if len(operations) == 0 {
+			return nil
+		}
+		_, err := configs.ValidatorBalancesCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+		operations = operations[:0]
+		return err
+	}
+
+	for _, b := range balances {
+		index, err := strconv.ParseInt(b.Index, 10, 64)
+		if err != nil {
+			continue
+		}
+		balance, err := strconv.ParseInt(b.Balance, 10, 64)
+		if err != nil {
+			configs.Logger.Warn("Skipping validator with invalid balance",
+				zap.String("index", b.Index),
+				zap.String("balance", b.Balance))
+			continue
+		}
+		effectiveBalance := effective[index]
+
+		snapshot := models.ValidatorBalanceSnapshot{
+			ValidatorIndex:   index,
+			Epoch:            epoch,
+			Balance:          b.Balance,
+			EffectiveBalance: strconv.FormatInt(effectiveBalance, 10),
+			Timestamp:        now,
+		}
+		if before, ok := previousBalances[index]; ok {
+			snapshot.PreviousEpoch = &previous.Epoch
+			snapshot.Reward, snapshot.Penalty, snapshot.Withdrawn = splitBalanceChange(before.balance, balance, before.effective, credited[index])
+		}
+
+		network.ValidatorsCount++
+		network.Rewards += snapshot.Reward
+		network.Penalties += snapshot.Penalty
+		network.Withdrawn += snapshot.Withdrawn
+		totalBalance.Add(totalBalance, big.NewInt(balance))
+		totalEffective.Add(totalEffective, big.NewInt(effectiveBalance))
+
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"validatorIndex": index, "epoch": epoch}).
+			SetUpdate(bson.M{"$set": snapshot}).
+			SetUpsert(true))
+
+		if len(operations) >= balanceWriteBatchSize {
+			if err := flush(); err != nil {
+				configs.Logger.Error("Failed to store validator balance snapshots", zap.Error(err))
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		configs.Logger.Error("Failed to store validator balance snapshots", zap.Error(err))
+		return err
+	}
+
+	network.TotalBalance = totalBalance.String()
+	network.TotalEffectiveBalance = totalEffective.String()
+
+	// Written last so an interrupted snapshot is retried on the next run
+	_, err = configs.NetworkRewardsCollections.UpdateOne(ctx,
+		bson.M{"epoch": epoch},
+		bson.M{"$set": network},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		configs.Logger.Error("Failed to store network rewards", zap.Error(err))
+		return err
+	}
+
+	configs.Logger.Info("Stored validator balance snapshot",
+		zap.Int64("epoch", epoch),
+		zap.Int64("previousEpoch", previous.Epoch),
+		zap.Int("validators", network.ValidatorsCount),
+		zap.Int64("rewards", network.Rewards),
+		zap.Int64("penalties", network.Penalties))
+	return nil
+}
+
+// splitBalanceChange attributes the balance change between two snapshots to
+// rewards, penalties and withdrawals. Deposits credited between the snapshots
+// are added to the previous balance so that top-ups are not counted as
+// rewards. Withdrawal sweeps are not visible on the beacon balance endpoint, so
+// a drop is treated as a withdrawal when the balance went to zero (full
+// withdrawal) or, up to the excess, when the funded balance was above the
+// effective balance of the previous snapshot (partial withdrawal).
+func splitBalanceChange(previous, current, effective, deposited int64) (reward, penalty, withdrawn int64) {
+	funded := previous + deposited
+	delta := current - funded
+	switch {
+	case delta >= 0:
+		return delta, 0, 0
+	case current == 0:
+		return 0, 0, funded
+	}
+
+	drop := -delta
+	if funded > effective {
+		withdrawn = min(funded-effective, drop)
+		drop -= withdrawn
+	}
+	return 0, drop, withdrawn
+}
+
+// snapshotBalance is a validator's actual and effective balance at a snapshot
+type snapshotBalance struct {
+	balance, effective int64
+}
+
+// findPreviousSnapshot returns the network record of the latest snapshot
+// before epoch, or false if there is none
+func findPreviousSnapshot(ctx context.Context, epoch int64) (models.NetworkRewardsRecord, bool, error) {
+	var previous models.NetworkRewardsRecord
+	err := configs.NetworkRewardsCollections.FindOne(ctx,
+		bson.M{"epoch": bson.M{"$lt": epoch}},
+		options.FindOne().SetSort(bson.D{{Key: "epoch", Value: -1}}),
+	).Decode(&previous)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return previous, false, nil
+		}
+		configs.Logger.Error("Failed to get previous balance snapshot", zap.Error(err))
+		return previous, false, err
+	}
+	return previous, true, nil
+}
+
+// loadSnapshotBalances returns the balances recorded at a snapshot epoch, keyed by validator index
+func loadSnapshotBalances(ctx context.Context, epoch int64) (map[int64]snapshotBalance, error) {
+	cursor, err := configs.ValidatorBalancesCollections.Find(ctx,
+		bson.M{"epoch": epoch},
+		options.Find().SetProjection(bson.M{"validatorIndex": 1, "balance": 1, "effectiveBalance": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to query previous balances", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	balances := make(map[int64]snapshotBalance)
+	for cursor.Next(ctx) {
+		var snapshot models.ValidatorBalanceSnapshot
+		if err := cursor.Decode(&snapshot); err != nil {
+			continue
+		}
+		balance, err := strconv.ParseInt(snapshot.Balance, 10, 64)
+		if err != nil {
+			continue
+		}
+		effective, _ := strconv.ParseInt(snapshot.EffectiveBalance, 10, 64)
+		balances[snapshot.ValidatorIndex] = snapshotBalance{balance: balance, effective: effective}
+	}
+
+	return balances, cursor.Err()
+}
+
+// depositsByValidator sums credited deposits per validator index, matching
+// their public keys against the stored validators
+func depositsByValidator(ctx context.Context, deposits []models.BeaconDeposit) (map[int64]int64, error) {
+	credited := make(map[int64]int64)
+	if len(deposits) == 0 {
+		return credited, nil
+	}
+
+	amounts := make(map[string]int64, len(deposits))
+	keys := make([]string, 0, len(deposits))
+	for _, d := range deposits {
+		if _, ok := amounts[d.PublicKeyHex]; !ok {
+			keys = append(keys, d.PublicKeyHex)
+		}
+		amounts[d.PublicKeyHex] += d.Amount
+	}
+
+	cursor, err := configs.GetValidatorCollection().Find(ctx,
+		bson.M{"publicKeyHex": bson.M{"$in": keys}},
+		options.Find().SetProjection(bson.M{"publicKeyHex": 1}))
+	if err != nil {
+		configs.Logger.Error("Failed to match deposits to validators", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var v models.ValidatorRecord
+		if err := cursor.Decode(&v); err != nil {
+			continue
+		}
+		credited[v.ID] += amounts[v.PublicKeyHex]
+	}
+
+	return credited, cursor.Err()
+}
@@ -0,0 +1,35 @@
+package services
+
+import "testing"
+
+func TestSplitBalanceChange(t *testing.T) {
+	tests := []struct {
+		name                         string
+		previous, current, effective int64
+		deposited                    int64
+		reward, penalty, withdrawn   int64
+	}{
+		{name: "unchanged", previous: 40000, current: 40000, effective: 40000},
+		{name: "reward", previous: 40000, current: 40012, effective: 40000, reward: 12},
+		{name: "penalty", previous: 40000, current: 39990, effective: 40000, penalty: 10},
+		{name: "full withdrawal", previous: 40000, current: 0, effective: 40000, withdrawn: 40000},
+		{name: "partial withdrawal", previous: 40030, current: 40000, effective: 40000, withdrawn: 30},
+		{name: "partial withdrawal and penalty", previous: 40030, current: 39995, effective: 40000, penalty: 5, withdrawn: 30},
+		{name: "top-up", previous: 40000, current: 41000, effective: 40000, deposited: 1000},
+		{name: "top-up and reward", previous: 40000, current: 41012, effective: 40000, deposited: 1000, reward: 12},
+		{name: "top-up swept", previous: 40000, current: 40000, effective: 40000, deposited: 1000, withdrawn: 1000},
+		{name: "top-up swept and penalty", previous: 40000, current: 39990, effective: 40000, deposited: 1000, penalty: 10, withdrawn: 1000},
+		{name: "top-up of a pending validator", previous: 20000, current: 40000, effective: 20000, deposited: 20000},
+		{name: "top-up and full withdrawal", previous: 40000, current: 0, effective: 40000, deposited: 1000, withdrawn: 41000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reward, penalty, withdrawn := splitBalanceChange(tt.previous, tt.current, tt.effective, tt.deposited)
+			if reward != tt.reward || penalty != tt.penalty || withdrawn != tt.withdrawn {
+				t.Errorf("got reward %d, penalty %d, withdrawn %d, wanted %d, %d, %d",
+					reward, penalty, withdrawn, tt.reward, tt.penalty, tt.withdrawn)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"context"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// loadStoredValidators returns the currently stored state of the given validators, keyed by index
+func loadStoredValidators(ctx context.Context, indices []int64) (map[int64]models.ValidatorRecord, error) {
+	projection := bson.M{
+		"publicKeyHex":      1,
+		"slashed":           1,
+		"activationEpoch":   1,
+		"exitEpoch":         1,
+		"withdrawableEpoch": 1,
+		"status":            1,
+		"epoch":             1,
+	}
+	cursor, err := configs.GetValidatorCollection().Find(ctx,
+		bson.M{"_id": bson.M{"$in": indices}},
+		options.Find().SetProjection(projection))
+	if err != nil {
+		configs.Logger.Error("Failed to query stored validators", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stored := make(map[int64]models.ValidatorRecord, len(indices))
+	for cursor.Next(ctx) {
+		var v models.ValidatorRecord
+		if err := cursor.Decode(&v); err != nil {
+			continue
+		}
+		stored[v.ID] = v
+	}
+
+	return stored, cursor.Err()
+}
+
+// detectValidatorEvents compares the stored state of a validator with its
+// refreshed state and returns the transitions between them. slashing is the
+// beacon slashing of a newly slashed validator, if it was found; otherwise the
+// slashing is dated to the refresh that observed it.
+func detectValidatorEvents(previous, current models.ValidatorRecord, slashing *models.BeaconSlashing, currentEpoch int64, now int64) []models.ValidatorEventRecord {
+	previousEpoch, err := strconv.ParseInt(previous.Epoch, 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	event := newValidatorEvent(current, currentEpoch, now)
+
+	var events []models.ValidatorEventRecord
+
+	// Statuses are moved on every epoch between refreshes, so activations are
+	// found by their epoch rather than by a status change
+	if activation, err := strconv.ParseInt(current.ActivationEpoch, 10, 64); err == nil &&
+		activation > previousEpoch && activation <= currentEpoch {
+		events = append(events, event(models.ValidatorEventActivated, activation, ""))
+	}
+
+	if !previous.Slashed && current.Slashed {
+		if slashing != nil {
+			events = append(events, event(models.ValidatorEventSlashed, slashing.Epoch, slashing.Reason))
+		} else {
+			events = append(events, event(models.ValidatorEventSlashed, currentEpoch, ""))
+		}
+	}
+
+	// The exit epoch is set as soon as an exit is initiated. Slashed validators
+	// are exited by the protocol; anything else is treated as a voluntary exit.
+	if previous.ExitEpoch == models.FarFutureEpoch && current.ExitEpoch != models.FarFutureEpoch {
+		if exit, err := strconv.ParseInt(current.ExitEpoch, 10, 64); err == nil {
+			reason := models.ExitReasonVoluntary
+			if current.Slashed {
+				reason = models.ExitReasonSlashed
+			}
+			events = append(events, event(models.ValidatorEventExit, exit, reason))
+		}
+	}
+
+	if current.WithdrawableEpoch != models.FarFutureEpoch {
+		if withdrawable, err := strconv.ParseInt(current.WithdrawableEpoch, 10, 64); err == nil &&
+			withdrawable > previousEpoch && withdrawable <= currentEpoch {
+			events = append(events, event(models.ValidatorEventWithdrawable, withdrawable, ""))
+		}
+	}
+
+	return events
+}
+
+// detectNewValidatorEvents returns the events of a validator seen for the first
+// time. A validator still waiting for activation gets a deposited event, dated
+// to its activation eligibility epoch once assigned. Validators that are
+// already active produce no events, so the initial sync does not flood the
+// collection with historic activations.
+func detectNewValidatorEvents(current models.ValidatorRecord, currentEpoch int64, now int64) []models.ValidatorEventRecord {
+	if current.ActivationEpochNum <= currentEpoch {
+		return nil
+	}
+
+	epoch := currentEpoch
+	if current.ActivationEligibilityEpoch != models.FarFutureEpoch {
+		if eligibility, err := strconv.ParseInt(current.ActivationEligibilityEpoch, 10, 64); err == nil {
+			epoch = eligibility
+		}
+	}
+	event := newValidatorEvent(current, currentEpoch, now)
+	return []models.ValidatorEventRecord{event(models.ValidatorEventDeposited, epoch, "")}
+}
+
+// newValidatorEvent returns a constructor for events of a validator observed at currentEpoch
+func newValidatorEvent(current models.ValidatorRecord, currentEpoch int64, now int64) func(eventType string, epoch int64, reason string) models.ValidatorEventRecord {
+	return func(eventType string, epoch int64, reason string) models.ValidatorEventRecord {
+		return models.ValidatorEventRecord{
+			ValidatorIndex: current.ID,
+			PublicKeyHex:   current.PublicKeyHex,
+			Type:           eventType,
+			Epoch:          epoch,
+			Reason:         reason,
+			DetectedEpoch:  currentEpoch,
+			Timestamp:      now,
+		}
+	}
+}
+
+// storeValidatorEvents upserts validator events so a resumed refresh does not duplicate them
+func storeValidatorEvents(ctx context.Context, events []models.ValidatorEventRecord) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	operations := make([]mongo.WriteModel, 0, len(events))
+	for _, e := range events {
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"validatorIndex": e.ValidatorIndex, "type": e.Type, "epoch": e.Epoch}).
+			SetUpdate(bson.M{"$setOnInsert": e}).
+			SetUpsert(true))
+	}
+
+	_, err := configs.ValidatorEventsCollections.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		configs.Logger.Error("Failed to store validator events", zap.Error(err))
+		return err
+	}
+
+	configs.Logger.Info("Recorded validator events", zap.Int("count", len(events)))
+	return nil
+}
@@ -15,120 +15,133 @@ import (
 	"go.uber.org/zap"
 )
 
-// StoreValidators stores validator data from the beacon chain response
-func StoreValidators(beaconResponse models.BeaconValidatorResponse, currentEpoch string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// validatorCheckpointID is the sync_state document tracking validator refresh progress
+const validatorCheckpointID = "validator_refresh"
+
+// SlashingFinder returns the beacon slashing of a validator included after
+// afterEpoch and up to upToEpoch, or nil if it cannot be found
+type SlashingFinder func(validatorIndex, afterEpoch, upToEpoch int64) (*models.BeaconSlashing, error)
+
+// StoreValidators upserts one document per validator from a page of the beacon
+// chain response. findSlashing is used to date newly slashed validators.
+func StoreValidators(beaconResponse models.BeaconValidatorResponse, currentEpoch string, findSlashing SlashingFinder) error {
+	if len(beaconResponse.ValidatorList) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// Parse current epoch for status calculation
 	currentEpochInt, _ := strconv.ParseInt(currentEpoch, 10, 64)
+	now := time.Now().Unix()
+
+	indices := make([]int64, 0, len(beaconResponse.ValidatorList))
+	for _, v := range beaconResponse.ValidatorList {
+		if index, err := strconv.ParseInt(v.Index, 10, 64); err == nil {
+			indices = append(indices, index)
+		}
+	}
+	stored, err := loadStoredValidators(ctx, indices)
+	if err != nil {
+		return err
+	}
 
-	// Convert each validator
-	newValidators := make([]models.ValidatorRecord, 0, len(beaconResponse.ValidatorList))
+	var events []models.ValidatorEventRecord
+	operations := make([]mongo.WriteModel, 0, len(beaconResponse.ValidatorList))
 	for _, v := range beaconResponse.ValidatorList {
-		// Determine if this validator is the leader for their slot (simplified: based on index mod)
-		slotNum, _ := strconv.ParseInt(v.Index, 10, 64)
-		isLeader := slotNum%128 == 0 // Simplified leader selection
+		index, err := strconv.ParseInt(v.Index, 10, 64)
+		if err != nil {
+			configs.Logger.Warn("Skipping validator with invalid index",
+				zap.String("index", v.Index),
+				zap.Error(err))
+			continue
+		}
 
+		withdrawalCredentials := models.Base64ToHex(v.Validator.WithdrawalCredentials)
 		record := models.ValidatorRecord{
+			ID:                         index,
 			Index:                      v.Index,
 			PublicKeyHex:               models.Base64ToHex(v.Validator.PublicKey),
-			WithdrawalCredentialsHex:   models.Base64ToHex(v.Validator.WithdrawalCredentials),
+			WithdrawalCredentialsHex:   withdrawalCredentials,
+			WithdrawalAddress:          models.WithdrawalAddressFromCredentials(withdrawalCredentials),
 			EffectiveBalance:           v.Validator.EffectiveBalance,
 			Slashed:                    v.Validator.Slashed,
 			ActivationEligibilityEpoch: v.Validator.ActivationEligibilityEpoch,
 			ActivationEpoch:            v.Validator.ActivationEpoch,
 			ExitEpoch:                  v.Validator.ExitEpoch,
 			WithdrawableEpoch:          v.Validator.WithdrawableEpoch,
-			SlotNumber:                 v.Index,
-			IsLeader:                   isLeader,
-		}
-		newValidators = append(newValidators, record)
-	}
-
-	// First try to get existing document
-	var storage models.ValidatorStorage
-	err := configs.GetValidatorCollection().FindOne(ctx, bson.M{"_id": "validators"}).Decode(&storage)
-	if err != nil && err != mongo.ErrNoDocuments {
-		configs.Logger.Error("Failed to get existing validator document", zap.Error(err))
-		return err
-	}
-
-	if err == mongo.ErrNoDocuments {
-		// Create new document if it doesn't exist
-		storage = models.ValidatorStorage{
-			ID:         "validators",
-			Epoch:      currentEpoch,
-			UpdatedAt:  fmt.Sprintf("%d", time.Now().Unix()),
-			Validators: newValidators,
-		}
-	} else {
-		// Update existing validators and add new ones
-		// Create a map of existing validators by public key for quick lookup
-		existingValidatorIndex := make(map[string]int)
-		for i, v := range storage.Validators {
-			existingValidatorIndex[v.PublicKeyHex] = i
+			ActivationEpochNum:         models.EpochNumber(v.Validator.ActivationEpoch),
+			ExitEpochNum:               models.EpochNumber(v.Validator.ExitEpoch),
+			Status:                     models.GetValidatorStatus(v.Validator.ActivationEpoch, v.Validator.ExitEpoch, v.Validator.Slashed, currentEpochInt),
+			Epoch:                      currentEpoch,
+			UpdatedAt:                  now,
 		}
 
-		// Update existing validators or append new ones
-		for _, v := range newValidators {
-			if idx, exists := existingValidatorIndex[v.PublicKeyHex]; exists {
-				// Update existing validator's mutable fields
-				storage.Validators[idx].EffectiveBalance = v.EffectiveBalance
-				storage.Validators[idx].Slashed = v.Slashed
-				storage.Validators[idx].ExitEpoch = v.ExitEpoch
-				storage.Validators[idx].WithdrawableEpoch = v.WithdrawableEpoch
-				storage.Validators[idx].SlotNumber = v.SlotNumber
-				storage.Validators[idx].IsLeader = v.IsLeader
-			} else {
-				// Add new validator
-				storage.Validators = append(storage.Validators, v)
+		if previous, ok := stored[index]; ok {
+			var slashing *models.BeaconSlashing
+			if !previous.Slashed && record.Slashed {
+				previousEpoch, _ := strconv.ParseInt(previous.Epoch, 10, 64)
+				slashing, err = findSlashing(index, previousEpoch, currentEpochInt)
+				if err != nil {
+					return fmt.Errorf("failed to find slashing of validator %d: %w", index, err)
+				}
 			}
+			events = append(events, detectValidatorEvents(previous, record, slashing, currentEpochInt, now)...)
+		} else {
+			events = append(events, detectNewValidatorEvents(record, currentEpochInt, now)...)
 		}
 
-		// Update epoch and timestamp
-		storage.Epoch = currentEpoch
-		storage.UpdatedAt = fmt.Sprintf("%d", time.Now().Unix())
+		operations = append(operations, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": index}).
+			SetUpdate(bson.M{"$set": record}).
+			SetUpsert(true))
 	}
 
-	// Upsert the document
-	opts := options.Update().SetUpsert(true)
-	filter := bson.M{"_id": "validators"}
-	update := bson.M{"$set": storage}
+	if len(operations) == 0 {
+		return nil
+	}
 
-	_, err = configs.GetValidatorCollection().UpdateOne(ctx, filter, update, opts)
-	if err != nil {
-		configs.Logger.Error("Failed to update validator document", zap.Error(err))
+	// Events are written before the validator documents so a failed page is
+	// detected again when the refresh resumes
+	if err := storeValidatorEvents(ctx, events); err != nil {
 		return err
 	}
 
-	// Store validator history for this epoch AFTER updating storage
-	// Use the accumulated validators (storage.Validators) not just the new ones
-	if err := StoreValidatorHistory(storage.Validators, currentEpoch, currentEpochInt); err != nil {
-		configs.Logger.Warn("Failed to store validator history", zap.Error(err))
-		// Don't fail the main operation
+	result, err := configs.GetValidatorCollection().BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		configs.Logger.Error("Failed to upsert validator documents", zap.Error(err))
+		return err
 	}
 
 	configs.Logger.Info("Successfully updated validators",
-		zap.Int("newCount", len(newValidators)),
-		zap.Int("totalCount", len(storage.Validators)),
+		zap.Int("pageCount", len(operations)),
+		zap.Int64("inserted", result.UpsertedCount),
+		zap.Int64("modified", result.ModifiedCount),
 		zap.String("epoch", currentEpoch))
 	return nil
 }
 
-// GetValidators retrieves all validators from storage
-func GetValidators() (*models.ValidatorStorage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetValidators retrieves all validators ordered by index
+func GetValidators() ([]models.ValidatorRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	var storage models.ValidatorStorage
-	err := configs.GetValidatorCollection().FindOne(ctx, bson.M{"_id": "validators"}).Decode(&storage)
+	cursor, err := configs.GetValidatorCollection().Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
 	if err != nil {
-		configs.Logger.Error("Failed to get validator document", zap.Error(err))
+		configs.Logger.Error("Failed to query validators", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var validators []models.ValidatorRecord
+	if err := cursor.All(ctx, &validators); err != nil {
+		configs.Logger.Error("Failed to decode validators", zap.Error(err))
 		return nil, err
 	}
 
-	return &storage, nil
+	return validators, nil
 }
 
 // GetValidatorByPublicKey retrieves a specific validator by their public key
@@ -136,23 +149,56 @@ func GetValidatorByPublicKey(publicKeyHex string) (*models.ValidatorRecord, erro
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var storage models.ValidatorStorage
-	err := configs.GetValidatorCollection().FindOne(ctx, bson.M{
-		"validators.publicKeyHex": publicKeyHex,
-	}).Decode(&storage)
-
+	var validator models.ValidatorRecord
+	err := configs.GetValidatorCollection().FindOne(ctx, bson.M{"publicKeyHex": publicKeyHex}).Decode(&validator)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("validator not found")
+		}
 		return nil, err
 	}
 
-	// Find the matching validator
-	for _, v := range storage.Validators {
-		if v.PublicKeyHex == publicKeyHex {
-			return &v, nil
+	return &validator, nil
+}
+
+// GetValidatorSyncCheckpoint returns the progress of the last validator refresh,
+// or nil if no refresh has been recorded yet
+func GetValidatorSyncCheckpoint() (*models.ValidatorSyncCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var checkpoint models.ValidatorSyncCheckpoint
+	err := configs.GetCollection(configs.DB, "sync_state").FindOne(ctx, bson.M{"_id": validatorCheckpointID}).Decode(&checkpoint)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
 		}
+		configs.Logger.Error("Failed to get validator sync checkpoint", zap.Error(err))
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("validator not found")
+	return &checkpoint, nil
+}
+
+// SaveValidatorSyncCheckpoint records the progress of the current validator refresh
+func SaveValidatorSyncCheckpoint(checkpoint *models.ValidatorSyncCheckpoint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	checkpoint.ID = validatorCheckpointID
+	checkpoint.UpdatedAt = time.Now().Unix()
+
+	opts := options.Update().SetUpsert(true)
+	filter := bson.M{"_id": validatorCheckpointID}
+	update := bson.M{"$set": checkpoint}
+
+	_, err := configs.GetCollection(configs.DB, "sync_state").UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		configs.Logger.Error("Failed to save validator sync checkpoint", zap.Error(err))
+		return err
+	}
+
+	return nil
 }
 
 // StoreEpochInfo stores the current epoch information from beacon chain head
@@ -187,19 +233,79 @@ func StoreEpochInfo(chainHead *models.BeaconChainHeadResponse) error {
 	return nil
 }
 
-// StoreValidatorHistory computes and stores validator statistics for the current epoch
-func StoreValidatorHistory(validators []models.ValidatorRecord, epoch string, currentEpochInt int64) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// UpdateValidatorStatuses moves validators whose activation or exit epoch has
+// been reached to their new status, so the stored status stays current between
+// refreshes, and records the validator totals once per epoch
+func UpdateValidatorStatuses(epoch int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	var activeCount, pendingCount, exitedCount, slashedCount int
+	transitions := []struct {
+		from, to, field string
+	}{
+		{"pending", "active", "activationEpochNum"},
+		{"active", "exited", "exitEpochNum"},
+	}
+	for _, t := range transitions {
+		result, err := configs.GetValidatorCollection().UpdateMany(ctx,
+			bson.M{"status": t.from, t.field: bson.M{"$lte": epoch}},
+			bson.M{"$set": bson.M{"status": t.to}})
+		if err != nil {
+			configs.Logger.Error("Failed to update validator statuses",
+				zap.String("from", t.from),
+				zap.String("to", t.to),
+				zap.Error(err))
+			return err
+		}
+		if result.ModifiedCount > 0 {
+			configs.Logger.Info("Updated validator statuses",
+				zap.String("from", t.from),
+				zap.String("to", t.to),
+				zap.Int64("count", result.ModifiedCount),
+				zap.Int64("epoch", epoch))
+		}
+	}
+
+	count, err := configs.ValidatorHistoryCollections.CountDocuments(ctx, bson.M{"epoch": strconv.FormatInt(epoch, 10)})
+	if err != nil {
+		configs.Logger.Error("Failed to check validator history", zap.Error(err))
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return StoreValidatorHistory(strconv.FormatInt(epoch, 10))
+}
+
+// StoreValidatorHistory computes validator statistics from the stored validator
+// documents and records them for the given epoch
+func StoreValidatorHistory(epoch string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	projection := options.Find().SetProjection(bson.M{"status": 1, "effectiveBalance": 1})
+	cursor, err := configs.GetValidatorCollection().Find(ctx, bson.M{}, projection)
+	if err != nil {
+		configs.Logger.Error("Failed to query validators for history", zap.Error(err))
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var validatorsCount, activeCount, pendingCount, exitedCount, slashedCount int
 	totalStaked := big.NewInt(0)
 
-	for _, v := range validators {
-		// Calculate status
-		status := models.GetValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpochInt)
+	for cursor.Next(ctx) {
+		var v struct {
+			Status           string `bson:"status"`
+			EffectiveBalance string `bson:"effectiveBalance"`
+		}
+		if err := cursor.Decode(&v); err != nil {
+			configs.Logger.Warn("Failed to decode validator for history", zap.Error(err))
+			continue
+		}
+		validatorsCount++
 
-		switch status {
+		switch v.Status {
 		case "active":
 			activeCount++
 		case "pending":
@@ -215,11 +321,15 @@ func StoreValidatorHistory(validators []models.ValidatorRecord, epoch string, cu
 			totalStaked.Add(totalStaked, balance)
 		}
 	}
+	if err := cursor.Err(); err != nil {
+		configs.Logger.Error("Validator cursor error while computing history", zap.Error(err))
+		return err
+	}
 
 	record := &models.ValidatorHistoryRecord{
 		Epoch:           epoch,
 		Timestamp:       time.Now().Unix(),
-		ValidatorsCount: len(validators),
+		ValidatorsCount: validatorsCount,
 		ActiveCount:     activeCount,
 		PendingCount:    pendingCount,
 		ExitedCount:     exitedCount,
@@ -232,7 +342,7 @@ func StoreValidatorHistory(validators []models.ValidatorRecord, epoch string, cu
 	filter := bson.M{"epoch": record.Epoch}
 	update := bson.M{"$set": record}
 
-	_, err := configs.ValidatorHistoryCollections.UpdateOne(ctx, filter, update, opts)
+	_, err = configs.ValidatorHistoryCollections.UpdateOne(ctx, filter, update, opts)
 	if err != nil {
 		configs.Logger.Error("Failed to insert validator history", zap.Error(err))
 		return err
@@ -0,0 +1,158 @@
+package synchroniser
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/services"
+	"fmt"
+	"math"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// performanceBatchSize is the number of validator indices sent per performance request
+const performanceBatchSize = 100
+
+// syncAttestationsPeriodically records attestation performance for the last completed epoch
+func syncAttestationsPeriodically() {
+	if err := syncAttestationPerformance(); err != nil {
+		configs.Logger.Error("Failed to sync attestation performance", zap.Error(err))
+	}
+}
+
+// syncAttestationPerformance stores per-validator attestation results and
+// network participation for the epoch before the head epoch. The beacon node
+// only reports performance for that epoch, so a missed run leaves a gap.
+func syncAttestationPerformance() error {
+	chainHead, err := rpc.GetBeaconChainHead()
+	if err != nil {
+		return fmt.Errorf("failed to get beacon chain head: %w", err)
+	}
+	headEpoch, err := strconv.ParseInt(chainHead.HeadEpoch, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid head epoch %q: %w", chainHead.HeadEpoch, err)
+	}
+	epoch := headEpoch - 1
+	if epoch < 0 {
+		return nil
+	}
+
+	exists, err := services.HasParticipationRecord(epoch)
+	if err != nil || exists {
+		return err
+	}
+
+	active, err := services.GetActiveValidatorIndices()
+	if err != nil {
+		return err
+	}
+	indices := make([]int64, 0, len(active))
+	for _, index := range active {
+		indices = append(indices, index)
+	}
+
+	records := make([]models.ValidatorAttestationRecord, 0, len(indices))
+	for start := 0; start < len(indices); start += performanceBatchSize {
+		end := start + performanceBatchSize
+		if end > len(indices) {
+			end = len(indices)
+		}
+
+		performance, err := rpc.GetValidatorPerformance(indices[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to get validator performance: %w", err)
+		}
+		records = append(records, attestationRecords(performance, active)...)
+	}
+
+	if err := services.StoreAttestationPerformance(epoch, records); err != nil {
+		return err
+	}
+
+	participation, err := rpc.GetValidatorParticipation(epoch)
+	if err != nil {
+		return fmt.Errorf("failed to get validator participation: %w", err)
+	}
+
+	// Stored last: its presence marks the epoch as done
+	return services.StoreNetworkParticipation(networkParticipation(epoch, participation, records))
+}
+
+// attestationRecords converts a performance response into per-validator records
+func attestationRecords(performance *models.BeaconValidatorPerformanceResponse, active map[string]int64) []models.ValidatorAttestationRecord {
+	records := make([]models.ValidatorAttestationRecord, 0, len(performance.PublicKeys)+len(performance.MissingValidators))
+
+	for i, key := range performance.PublicKeys {
+		index, ok := active[models.Base64ToHex(key)]
+		if !ok {
+			continue
+		}
+		record := models.ValidatorAttestationRecord{ValidatorIndex: index}
+		if i < len(performance.CorrectlyVotedSource) {
+			record.CorrectSource = performance.CorrectlyVotedSource[i]
+		}
+		if i < len(performance.CorrectlyVotedTarget) {
+			record.CorrectTarget = performance.CorrectlyVotedTarget[i]
+		}
+		if i < len(performance.CorrectlyVotedHead) {
+			record.CorrectHead = performance.CorrectlyVotedHead[i]
+		}
+		// Unincluded attestations are reported with a far-future distance
+		if i < len(performance.InclusionDistances) {
+			distance, err := strconv.ParseUint(performance.InclusionDistances[i], 10, 64)
+			if err == nil && distance > 0 && distance < math.MaxInt32 {
+				record.InclusionDistance = int64(distance)
+				record.Included = true
+			}
+		}
+		if record.CorrectSource {
+			record.Included = true
+		}
+		records = append(records, record)
+	}
+
+	for _, key := range performance.MissingValidators {
+		if index, ok := active[models.Base64ToHex(key)]; ok {
+			records = append(records, models.ValidatorAttestationRecord{ValidatorIndex: index})
+		}
+	}
+
+	return records
+}
+
+// networkParticipation builds the network record from the participation
+// response (its previous-epoch fields describe the requested epoch) and the
+// per-validator scores
+func networkParticipation(epoch int64, participation *models.BeaconParticipationResponse, records []models.ValidatorAttestationRecord) *models.NetworkParticipationRecord {
+	p := participation.Participation
+	record := &models.NetworkParticipationRecord{
+		Epoch:               epoch,
+		ActiveGwei:          p.PreviousEpochActiveGwei,
+		AttestingGwei:       p.PreviousEpochAttestingGwei,
+		TargetAttestingGwei: p.PreviousEpochTargetAttestingGwei,
+		HeadAttestingGwei:   p.PreviousEpochHeadAttestingGwei,
+		ValidatorsTracked:   len(records),
+	}
+
+	active, _ := strconv.ParseFloat(p.PreviousEpochActiveGwei, 64)
+	if active > 0 {
+		attesting, _ := strconv.ParseFloat(p.PreviousEpochAttestingGwei, 64)
+		target, _ := strconv.ParseFloat(p.PreviousEpochTargetAttestingGwei, 64)
+		head, _ := strconv.ParseFloat(p.PreviousEpochHeadAttestingGwei, 64)
+		record.ParticipationRate = attesting / active
+		record.TargetParticipation = target / active
+		record.HeadParticipation = head / active
+	}
+
+	if len(records) > 0 {
+		var total float64
+		for _, r := range records {
+			total += services.AttestationScore(r)
+		}
+		record.AverageEffectiveness = total / float64(len(records))
+	}
+
+	return record
+}
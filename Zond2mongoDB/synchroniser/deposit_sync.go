@@ -0,0 +1,128 @@
+package synchroniser
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/db"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/utils"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// depositLogRange is the number of blocks requested per zond_getLogs call
+	depositLogRange = 1000
+	// depositBlocksPerRun caps the blocks scanned per run so catching up does
+	// not hold the task for too long
+	depositBlocksPerRun = 50000
+)
+
+// syncDepositsPeriodically indexes new deposit contract events and links
+// deposits to validators once they appear on the beacon chain
+func syncDepositsPeriodically() {
+	if err := syncDeposits(); err != nil {
+		configs.Logger.Error("Failed to sync deposits", zap.Error(err))
+	}
+
+	linked, err := db.LinkDepositsToValidators()
+	if err != nil {
+		configs.Logger.Error("Failed to link deposits to validators", zap.Error(err))
+	} else if linked > 0 {
+		configs.Logger.Info("Linked deposits to validators", zap.Int("validators", linked))
+	}
+}
+
+// syncDeposits scans the blocks stored since the last run for DepositEvent
+// logs. Scanning stops at the last stored block so every deposit can be given
+// its block timestamp.
+func syncDeposits() error {
+	contract, err := rpc.GetDepositContractAddress()
+	if err != nil {
+		return fmt.Errorf("failed to get deposit contract address: %w", err)
+	}
+
+	lastBlock, err := db.GetLastDepositBlock()
+	if err != nil {
+		return err
+	}
+	latest := utils.HexToInt(db.GetLastKnownBlockNumber()).Int64()
+
+	from := lastBlock + 1
+	to := latest
+	if to-from+1 > depositBlocksPerRun {
+		to = from + depositBlocksPerRun - 1
+	}
+
+	senders := make(map[string]string)
+	for start := from; start <= to; start += depositLogRange {
+		end := start + depositLogRange - 1
+		if end > to {
+			end = to
+		}
+
+		logs, err := rpc.ZondGetLogs(utils.IntToHex(int(start)), utils.IntToHex(int(end)), contract,
+			[]string{models.DepositEventSignature})
+		if err != nil {
+			return fmt.Errorf("failed to get deposit logs for blocks %d-%d: %w", start, end, err)
+		}
+
+		deposits := make([]models.DepositRecord, 0, len(logs))
+		for _, log := range logs {
+			if log.Removed {
+				continue
+			}
+			deposit, err := rpc.DecodeDepositEvent(log)
+			if err != nil {
+				configs.Logger.Warn("Skipping undecodable deposit event",
+					zap.String("txHash", log.TransactionHash),
+					zap.String("logIndex", log.LogIndex),
+					zap.Error(err))
+				continue
+			}
+
+			sender, ok := senders[log.TransactionHash]
+			if !ok {
+				tx, err := rpc.GetTxDetailsByHash(log.TransactionHash)
+				if err != nil {
+					return fmt.Errorf("failed to get deposit transaction %s: %w", log.TransactionHash, err)
+				}
+				sender = normalizeDepositSender(tx.From)
+				senders[log.TransactionHash] = sender
+			}
+			deposit.Sender = sender
+
+			if block := db.GetBlockFromDB(log.BlockNumber); block != nil {
+				deposit.BlockTimestamp = block.Result.Timestamp
+			}
+
+			deposits = append(deposits, *deposit)
+		}
+
+		if err := db.UpsertDeposits(deposits); err != nil {
+			return err
+		}
+		if err := db.StoreLastDepositBlock(end); err != nil {
+			return err
+		}
+		if len(deposits) > 0 {
+			configs.Logger.Info("Indexed deposits",
+				zap.Int64("fromBlock", start),
+				zap.Int64("toBlock", end),
+				zap.Int("count", len(deposits)))
+		}
+	}
+
+	return nil
+}
+
+// normalizeDepositSender stores senders as Z followed by lowercase hex, the
+// same form used for token transfer addresses
+func normalizeDepositSender(address string) string {
+	address = strings.ToLower(address)
+	address = strings.TrimPrefix(address, "0x")
+	address = strings.TrimPrefix(address, "z")
+	return "Z" + address
+}
@@ -0,0 +1,141 @@
+package synchroniser
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/db"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/utils"
+	"fmt"
+	"math/big"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// feeHistoryBatchSize is the block count per zond_feeHistory call, the node's maximum
+	feeHistoryBatchSize = 1024
+	// feeHistoryBlocksPerRun caps the blocks processed per run while catching up
+	feeHistoryBlocksPerRun = 10 * feeHistoryBatchSize
+)
+
+// syncFeeHistoryPeriodically records fee market data for newly stored blocks
+func syncFeeHistoryPeriodically() {
+	if err := syncFeeHistory(); err != nil {
+		configs.Logger.Error("Failed to sync fee history", zap.Error(err))
+	}
+}
+
+// syncFeeHistory stores base fees and effective priority fee percentiles for
+// the blocks stored since the last run
+func syncFeeHistory() error {
+	checkpoint, err := db.GetFeeHistoryCheckpoint()
+	if err != nil {
+		return err
+	}
+	totalBurnt, ok := new(big.Int).SetString(checkpoint.TotalBurnt, 10)
+	if !ok {
+		return fmt.Errorf("invalid burnt fee total %q in fee history checkpoint", checkpoint.TotalBurnt)
+	}
+	latest := utils.HexToInt(db.GetLastKnownBlockNumber()).Int64()
+
+	from := checkpoint.Block + 1
+	to := latest
+	if to-from+1 > feeHistoryBlocksPerRun {
+		to = from + feeHistoryBlocksPerRun - 1
+	}
+
+	for start := from; start <= to; start += feeHistoryBatchSize {
+		end := start + feeHistoryBatchSize - 1
+		if end > to {
+			end = to
+		}
+		count := int(end - start + 1)
+
+		history, err := rpc.ZondFeeHistory(count, utils.IntToHex(int(end)), models.FeeHistoryPercentiles)
+		if err != nil {
+			return fmt.Errorf("failed to get fee history for blocks %d-%d: %w", start, end, err)
+		}
+		result := history.Result
+
+		// The node may return fewer blocks than requested if it has pruned state
+		oldest := utils.HexToInt(result.OldestBlock).Int64()
+		blocks := len(result.GasUsedRatio)
+		if len(result.BaseFeePerGas) < blocks+1 {
+			return fmt.Errorf("fee history for blocks %d-%d is missing base fees", start, end)
+		}
+		if oldest < start || oldest+int64(blocks)-1 > end {
+			return fmt.Errorf("fee history for blocks %d-%d returned blocks %d-%d", start, end, oldest, oldest+int64(blocks)-1)
+		}
+
+		// Pruned blocks get no fee history record, but their burnt fees are
+		// still counted from the stored blocks
+		numbers := make([]string, 0, end-start+1)
+		for n := start; n <= end; n++ {
+			numbers = append(numbers, utils.IntToHex(int(n)))
+		}
+		stored, err := db.GetStoredBlockInfo(numbers)
+		if err != nil {
+			return err
+		}
+		for _, number := range numbers[:oldest-start] {
+			block, ok := stored[number]
+			if !ok {
+				return fmt.Errorf("block %s is not stored yet", number)
+			}
+			totalBurnt.Add(totalBurnt, new(big.Int).Mul(block.BaseFeePerGas, block.GasUsed))
+		}
+		if oldest > start {
+			configs.Logger.Warn("Fee history pruned by node, counting burnt fees from stored blocks",
+				zap.Int64("fromBlock", start),
+				zap.Int64("toBlock", oldest-1))
+		}
+		numbers = numbers[oldest-start:]
+
+		records := make([]models.FeeHistoryRecord, 0, blocks)
+		for i := 0; i < blocks; i++ {
+			block, ok := stored[numbers[i]]
+			if !ok {
+				// The burnt total must not skip a block, retry once it is stored
+				return fmt.Errorf("block %s is not stored yet", numbers[i])
+			}
+			baseFee := utils.HexToInt(result.BaseFeePerGas[i])
+			burnt := new(big.Int).Mul(baseFee, block.GasUsed)
+			totalBurnt.Add(totalBurnt, burnt)
+
+			record := models.FeeHistoryRecord{
+				ID:                oldest + int64(i),
+				BlockNumber:       numbers[i],
+				BaseFeePerGas:     baseFee.String(),
+				NextBaseFeePerGas: utils.HexToInt(result.BaseFeePerGas[i+1]).String(),
+				GasUsedRatio:      result.GasUsedRatio[i],
+				GasUsed:           block.GasUsed.String(),
+				BurntFee:          burnt.String(),
+				Percentiles:       models.FeeHistoryPercentiles,
+				PriorityFees:      make([]string, 0, len(models.FeeHistoryPercentiles)),
+				Timestamp:         block.Timestamp,
+			}
+			if i < len(result.Reward) {
+				for _, reward := range result.Reward[i] {
+					record.PriorityFees = append(record.PriorityFees, utils.HexToInt(reward).String())
+				}
+			}
+			records = append(records, record)
+		}
+
+		if err := db.UpsertFeeHistory(records); err != nil {
+			return err
+		}
+		next := db.FeeHistoryCheckpoint{Block: end, TotalBurnt: totalBurnt.String()}
+		if err := db.StoreFeeHistoryCheckpoint(checkpoint, next); err != nil {
+			return err
+		}
+		checkpoint = next
+		configs.Logger.Info("Synced fee history",
+			zap.Int64("fromBlock", oldest),
+			zap.Int64("toBlock", end),
+			zap.Int("blocks", len(records)))
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package synchroniser
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/db"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/utils"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// networkStatsBatchSize is the number of blocks loaded per query
+	networkStatsBatchSize = 500
+	// networkStatsBlocksPerRun caps the blocks folded in per run while catching up
+	networkStatsBlocksPerRun = 20 * networkStatsBatchSize
+	// networkStatsLag keeps the aggregator behind the sync head so the token
+	// transfers and receipts of a block are stored before it is counted
+	networkStatsLag = 10
+)
+
+// statsBucket accumulates one bucket's totals before they are merged into networkStats
+type statsBucket struct {
+	record    models.NetworkStatsRecord
+	totalFees *big.Int
+	burntFees *big.Int
+	addresses map[string]struct{}
+}
+
+// syncNetworkStatsPeriodically folds newly stored blocks into the hourly and daily stats
+func syncNetworkStatsPeriodically() {
+	if err := syncNetworkStats(); err != nil {
+		configs.Logger.Error("Failed to sync network stats", zap.Error(err))
+	}
+}
+
+// syncNetworkStats extends the hourly and daily networkStats buckets with the
+// blocks stored since the last run. Blocks are processed in order and the run
+// stops at the first missing block so no block is skipped or counted twice.
+// The deltas of each range are stored as pending before any bucket changes, so
+// a run interrupted mid-range finishes it with the same deltas.
+func syncNetworkStats() error {
+	checkpoint, err := db.GetNetworkStatsCheckpoint()
+	if err != nil {
+		return err
+	}
+	latest := utils.HexToInt(db.GetLastKnownBlockNumber()).Int64()
+
+	from := checkpoint.Block + 1
+	to := latest - networkStatsLag
+	if to-from+1 > networkStatsBlocksPerRun {
+		to = from + networkStatsBlocksPerRun - 1
+	}
+
+	for start := from; start <= to; start += networkStatsBatchSize {
+		end := start + networkStatsBatchSize - 1
+		if end > to {
+			end = to
+		}
+		pending := checkpoint.Pending
+		if pending != nil && pending.From == start {
+			end = pending.To
+		} else {
+			pending = nil
+		}
+
+		numbers := make([]string, 0, end-start+1)
+		for n := start; n <= end; n++ {
+			numbers = append(numbers, utils.IntToHex(int(n)))
+		}
+
+		blocks, err := db.GetStoredBlocks(numbers)
+		if err != nil {
+			return err
+		}
+		fees, feeCounts, err := db.GetReceiptFeesByBlock(numbers)
+		if err != nil {
+			return err
+		}
+		tokenTransfers, err := db.CountTokenTransfersByBlock(numbers)
+		if err != nil {
+			return err
+		}
+
+		buckets := make(map[string]*statsBucket)
+		next := checkpoint
+		for i, number := range numbers {
+			block, ok := blocks[number]
+			if !ok {
+				// Retry from this block once the gap is filled
+				configs.Logger.Debug("Network stats waiting for missing block",
+					zap.String("blockNumber", number))
+				break
+			}
+
+			timestamp := utils.HexToInt(block.Result.Timestamp).Int64()
+			for interval := range models.StatsIntervals {
+				bucket := bucketFor(buckets, interval, timestamp)
+				addBlockToBucket(bucket, block, next.Timestamp, fees[number], feeCounts[number], tokenTransfers[number])
+			}
+			next = db.NetworkStatsCheckpoint{Block: start + int64(i), Timestamp: timestamp}
+		}
+
+		if next.Block == checkpoint.Block {
+			return nil
+		}
+		if pending == nil {
+			records, err := countStatsBuckets(buckets)
+			if err != nil {
+				return err
+			}
+			pending = &db.NetworkStatsRange{From: start, To: next.Block, Next: next, Records: records}
+			if err := db.StoreNetworkStatsPending(*pending); err != nil {
+				return err
+			}
+		}
+		next = pending.Next
+
+		if err := db.MarkActiveAddresses(addressActivity(buckets)); err != nil {
+			return err
+		}
+		if err := db.MergeNetworkStats(pending.ID(), pending.Records); err != nil {
+			return err
+		}
+		if err := db.StoreNetworkStatsCheckpoint(next); err != nil {
+			return err
+		}
+		configs.Logger.Info("Synced network stats",
+			zap.Int64("fromBlock", checkpoint.Block+1),
+			zap.Int64("toBlock", next.Block))
+
+		if next.Block < end {
+			return nil
+		}
+		checkpoint = next
+	}
+
+	return nil
+}
+
+// bucketFor returns the accumulator of the bucket containing timestamp, creating it if needed
+func bucketFor(buckets map[string]*statsBucket, interval string, timestamp int64) *statsBucket {
+	start := models.StatsBucketStart(timestamp, interval)
+	id := fmt.Sprintf("%s:%d", interval, start)
+	if bucket, ok := buckets[id]; ok {
+		return bucket
+	}
+	bucket := &statsBucket{
+		record: models.NetworkStatsRecord{
+			ID:        id,
+			Interval:  interval,
+			Timestamp: start,
+		},
+		totalFees: new(big.Int),
+		burntFees: new(big.Int),
+		addresses: make(map[string]struct{}),
+	}
+	buckets[id] = bucket
+	return bucket
+}
+
+// addBlockToBucket adds one block's totals to a bucket
+func addBlockToBucket(bucket *statsBucket, block models.ZondDatabaseBlock, parentTimestamp int64, fees *big.Int, feeCount int64, tokenTransfers int64) {
+	timestamp := utils.HexToInt(block.Result.Timestamp).Int64()
+	gasUsed := utils.HexToInt(block.Result.GasUsed)
+
+	bucket.record.BlockCount++
+	// The first block has no known parent timestamp
+	if parentTimestamp > 0 && timestamp >= parentTimestamp {
+		bucket.record.BlockTimeSum += timestamp - parentTimestamp
+	}
+	bucket.record.TxCount += int64(len(block.Result.Transactions))
+	bucket.record.GasUsed += gasUsed.Int64()
+	bucket.burntFees.Add(bucket.burntFees, new(big.Int).Mul(utils.HexToInt(block.Result.BaseFeePerGas), gasUsed))
+	if fees != nil {
+		bucket.totalFees.Add(bucket.totalFees, fees)
+	}
+	bucket.record.FeeTxCount += feeCount
+	bucket.record.TokenTransfers += tokenTransfers
+
+	for _, tx := range block.Result.Transactions {
+		if tx.To == "" {
+			bucket.record.ContractDeployments++
+		} else {
+			bucket.addresses[strings.ToLower(tx.To)] = struct{}{}
+		}
+		if tx.From != "" {
+			bucket.addresses[strings.ToLower(tx.From)] = struct{}{}
+		}
+	}
+}
+
+// countStatsBuckets counts the active and new addresses of the buckets against
+// the stored address activity and returns the bucket deltas. An address is
+// active in a bucket unless it was already marked active in it; it is new in
+// the first hour it appears in if it has never been stored. A day's new
+// addresses are those of its hours.
+func countStatsBuckets(buckets map[string]*statsBucket) ([]models.NetworkStatsRecord, error) {
+	ordered := make([]*statsBucket, 0, len(buckets))
+	seen := make(map[string]struct{})
+	for _, bucket := range buckets {
+		ordered = append(ordered, bucket)
+		for address := range bucket.addresses {
+			seen[address] = struct{}{}
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].record.Interval != ordered[j].record.Interval {
+			return ordered[i].record.Interval == models.StatsIntervalHour
+		}
+		return ordered[i].record.Timestamp < ordered[j].record.Timestamp
+	})
+
+	addresses := make([]string, 0, len(seen))
+	for address := range seen {
+		addresses = append(addresses, address)
+	}
+	stored, err := db.GetAddressActivity(addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := make(map[string]struct{})
+	newByDay := make(map[int64]int64)
+	records := make([]models.NetworkStatsRecord, 0, len(ordered))
+	for _, bucket := range ordered {
+		interval := bucket.record.Interval
+		for address := range bucket.addresses {
+			activity, known := stored[address]
+			if !known || activity.LastActive[interval] < bucket.record.Timestamp {
+				bucket.record.ActiveAddresses++
+			}
+			if _, done := counted[address]; interval == models.StatsIntervalHour && !known && !done {
+				counted[address] = struct{}{}
+				bucket.record.NewAddresses++
+			}
+		}
+
+		if interval == models.StatsIntervalHour {
+			newByDay[models.StatsBucketStart(bucket.record.Timestamp, models.StatsIntervalDay)] += bucket.record.NewAddresses
+		} else {
+			bucket.record.NewAddresses = newByDay[bucket.record.Timestamp]
+		}
+		bucket.record.TotalFees = bucket.totalFees.String()
+		bucket.record.BurntFees = bucket.burntFees.String()
+		records = append(records, bucket.record)
+	}
+	return records, nil
+}
+
+// addressActivity returns the latest bucket per interval and the first hourly
+// bucket of every address active in the buckets
+func addressActivity(buckets map[string]*statsBucket) []db.AddressActivity {
+	byAddress := make(map[string]*db.AddressActivity)
+	for _, bucket := range buckets {
+		interval, start := bucket.record.Interval, bucket.record.Timestamp
+		for address := range bucket.addresses {
+			a, ok := byAddress[address]
+			if !ok {
+				a = &db.AddressActivity{Address: address, LastActive: make(map[string]int64)}
+				byAddress[address] = a
+			}
+			if start > a.LastActive[interval] {
+				a.LastActive[interval] = start
+			}
+			if interval == models.StatsIntervalHour && (a.FirstSeen == 0 || start < a.FirstSeen) {
+				a.FirstSeen = start
+			}
+		}
+	}
+
+	activity := make([]db.AddressActivity, 0, len(byAddress))
+	for _, a := range byAddress {
+		activity = append(activity, *a)
+	}
+	return activity
+}
@@ -3,6 +3,7 @@ package synchroniser
 import (
 	"Zond2mongoDB/configs"
 	"Zond2mongoDB/db"
+	"Zond2mongoDB/models"
 	"Zond2mongoDB/rpc"
 	"Zond2mongoDB/services"
 	"Zond2mongoDB/utils"
@@ -236,6 +237,20 @@ func updateValidatorsPeriodically() {
 	}
 }
 
+// updateValidatorStatusesPeriodically keeps validator statuses current between refreshes
+func updateValidatorStatusesPeriodically() {
+	if err := syncValidatorStatuses(); err != nil {
+		configs.Logger.Error("Failed to update validator statuses", zap.Error(err))
+	}
+}
+
+// updateValidatorBalancesPeriodically stores the balance snapshot of the head epoch
+func updateValidatorBalancesPeriodically() {
+	if err := syncValidatorBalances(); err != nil {
+		configs.Logger.Error("Failed to update validator balances", zap.Error(err))
+	}
+}
+
 // updateDataPeriodically updates market data, wallet counts, and other statistics
 func updateDataPeriodically() {
 	// Update market data
@@ -257,8 +272,28 @@ func updateDataPeriodically() {
 	} else {
 		configs.Logger.Info("Successfully updated block sizes collection")
 	}
+
+	// Update supply breakdown
+	configs.Logger.Info("Updating supply...")
+	if err := db.UpdateTotalBalance(); err != nil {
+		configs.Logger.Error("Failed to update supply", zap.Error(err))
+	}
+
+	// Add paging sort keys to documents stored before they existed. New
+	// documents are stored with them, so one complete pass is enough.
+	if !sortKeysBackfilled {
+		complete, err := db.BackfillSortKeys()
+		if err != nil {
+			configs.Logger.Error("Failed to backfill sort keys", zap.Error(err))
+		}
+		sortKeysBackfilled = complete
+	}
 }
 
+// sortKeysBackfilled is set once BackfillSortKeys has filled in every document. It is
+// only read and written by updateDataPeriodically, which never overlaps itself.
+var sortKeysBackfilled bool
+
 // singleBlockInsertion starts continuous block monitoring with periodic tasks
 func singleBlockInsertion() {
 	configs.Logger.Info("Starting single block insertion process")
@@ -332,6 +367,18 @@ func singleBlockInsertion() {
 		}
 	}()
 
+	// Beacon and index tasks; each stores at most once per epoch or range, so
+	// running them on start is safe
+	runPeriodicTask(updateValidatorStatusesPeriodically, time.Minute, "validator_statuses")
+	runPeriodicTask(updateValidatorBalancesPeriodically, time.Minute*10, "validator_balances")
+	runPeriodicTask(syncAttestationsPeriodically, time.Minute*10, "attestation_performance")
+	runPeriodicTask(attributeProposersPeriodically, time.Minute, "proposer_attribution")
+	runPeriodicTask(syncSlotsPeriodically, time.Minute, "slot_sync")
+	runPeriodicTask(syncDepositsPeriodically, time.Minute, "deposit_sync")
+	runPeriodicTask(syncFeeHistoryPeriodically, time.Minute, "fee_history_sync")
+	runPeriodicTask(syncNetworkStatsPeriodically, time.Minute*5, "network_stats_sync")
+	runPeriodicTask(syncRevertReasonsPeriodically, time.Minute, "revert_reasons")
+
 	// Start periodic gap detection task (every 5 minutes)
 	go func() {
 		defer wg.Done()
@@ -356,26 +403,18 @@ func singleBlockInsertion() {
 
 // syncValidators fetches and stores validator data from the beacon chain
 func syncValidators() error {
-	// Get current epoch from latest block
-	latestBlock, err := rpc.GetLatestBlock()
-	if err != nil {
-		return fmt.Errorf("failed to get latest block: %w", err)
-	}
-	currentEpoch := strconv.FormatUint(uint64(utils.HexToInt(latestBlock).Int64()/128), 10)
-
-	// Fetch and store epoch info from beacon chain
+	// The beacon chain head is the source of truth for the current epoch
 	chainHead, err := rpc.GetBeaconChainHead()
 	if err != nil {
-		configs.Logger.Warn("Failed to get beacon chain head", zap.Error(err))
-		// Don't fail - continue with validator sync
-	} else {
-		if err := services.StoreEpochInfo(chainHead); err != nil {
-			configs.Logger.Warn("Failed to store epoch info", zap.Error(err))
-		}
+		return fmt.Errorf("failed to get beacon chain head: %w", err)
+	}
+	if err := services.StoreEpochInfo(chainHead); err != nil {
+		configs.Logger.Warn("Failed to store epoch info", zap.Error(err))
 	}
+	currentEpoch := chainHead.HeadEpoch
 
 	// Get validators from beacon chain
-	err = rpc.GetValidators()
+	err = rpc.GetValidators(currentEpoch)
 	if err != nil {
 		configs.Logger.Error("Failed to get validators", zap.Error(err))
 		return err
@@ -384,3 +423,66 @@ func syncValidators() error {
 	configs.Logger.Info("Successfully synced validators", zap.String("epoch", currentEpoch))
 	return nil
 }
+
+// syncValidatorStatuses brings the stored validator statuses and totals up to
+// the head epoch
+func syncValidatorStatuses() error {
+	chainHead, err := rpc.GetBeaconChainHead()
+	if err != nil {
+		return fmt.Errorf("failed to get beacon chain head: %w", err)
+	}
+	if err := services.StoreEpochInfo(chainHead); err != nil {
+		configs.Logger.Warn("Failed to store epoch info", zap.Error(err))
+	}
+
+	epoch, err := strconv.ParseInt(chainHead.HeadEpoch, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid head epoch %q: %w", chainHead.HeadEpoch, err)
+	}
+	return services.UpdateValidatorStatuses(epoch)
+}
+
+// syncValidatorBalances stores a balance snapshot for the current head epoch
+// unless one has already been recorded
+func syncValidatorBalances() error {
+	chainHead, err := rpc.GetBeaconChainHead()
+	if err != nil {
+		return fmt.Errorf("failed to get beacon chain head: %w", err)
+	}
+
+	epoch, err := strconv.ParseInt(chainHead.HeadEpoch, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid head epoch %q: %w", chainHead.HeadEpoch, err)
+	}
+
+	exists, err := services.HasBalanceSnapshot(epoch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	balances, err := rpc.GetValidatorBalances(chainHead.HeadEpoch)
+	if err != nil {
+		return fmt.Errorf("failed to get validator balances: %w", err)
+	}
+	effective, err := rpc.GetEffectiveBalances(epoch)
+	if err != nil {
+		return fmt.Errorf("failed to get effective balances: %w", err)
+	}
+
+	// Deposits count towards the epoch whose blocks credited them
+	previous, found, err := services.PreviousBalanceEpoch(epoch)
+	if err != nil {
+		return err
+	}
+	var deposits []models.BeaconDeposit
+	if found {
+		if deposits, err = rpc.GetCreditedDeposits(previous, epoch); err != nil {
+			return fmt.Errorf("failed to get credited deposits: %w", err)
+		}
+	}
+
+	return services.StoreValidatorBalances(epoch, balances, effective, deposits)
+}
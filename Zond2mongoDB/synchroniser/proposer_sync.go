@@ -0,0 +1,168 @@
+package synchroniser
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/db"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"Zond2mongoDB/utils"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// proposerBatchSize is the number of blocks attributed per run, both past
+	// the checkpoint and among the pending blocks below it
+	proposerBatchSize = 100
+	// proposerMaxAttempts is the number of runs a block whose slot carries
+	// another payload is retried before it is marked orphaned, even if its
+	// epoch is not final yet
+	proposerMaxAttempts = 10
+)
+
+// attributeProposersPeriodically links synced blocks to their beacon proposer
+func attributeProposersPeriodically() {
+	if err := attributeBlockProposers(); err != nil {
+		configs.Logger.Error("Failed to attribute block proposers", zap.Error(err))
+	}
+}
+
+// attributeBlockProposers maps execution blocks to the beacon block that
+// carried them. The slot is derived from the block timestamp and confirmed by
+// comparing the execution payload hash. Blocks are scanned in ascending order
+// from a stored checkpoint; blocks below it that could not be attributed yet
+// are retried until their epoch is finalized. Beacon blocks are fetched once
+// per epoch, with the configured RPC delay between requests.
+func attributeBlockProposers() error {
+	genesis, err := rpc.GetGenesisTime()
+	if err != nil {
+		return fmt.Errorf("failed to get genesis time: %w", err)
+	}
+	chainHead, err := rpc.GetBeaconChainHead()
+	if err != nil {
+		return fmt.Errorf("failed to get beacon chain head: %w", err)
+	}
+	finalizedEpoch, err := strconv.ParseInt(chainHead.FinalizedEpoch, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid finalized epoch %q: %w", chainHead.FinalizedEpoch, err)
+	}
+
+	checkpoint, err := db.GetProposerCheckpoint()
+	if err != nil {
+		return err
+	}
+	if checkpoint == 0 {
+		if err := db.ResetEmptySlotProposers(); err != nil {
+			return err
+		}
+	}
+	pendingBlocks, err := db.GetBlocksWithoutProposer(0, checkpoint, proposerBatchSize)
+	if err != nil {
+		return err
+	}
+	newBlocks, err := db.GetBlocksWithoutProposer(checkpoint, math.MaxInt64, proposerBatchSize)
+	if err != nil {
+		return err
+	}
+	blocks := append(pendingBlocks, newBlocks...)
+
+	var preGenesis []string
+	slots := make(map[string]int64, len(blocks))
+	epochs := make(map[int64][]models.ZondDatabaseBlock)
+	for _, block := range blocks {
+		timestamp := utils.HexToInt(block.Result.Timestamp).Int64()
+		if timestamp < genesis {
+			preGenesis = append(preGenesis, block.Result.Hash)
+			continue
+		}
+		slot := (timestamp - genesis) / configs.SECONDS_PER_SLOT
+		slots[block.Result.Hash] = slot
+		epoch := slot / configs.SLOTS_PER_EPOCH
+		epochs[epoch] = append(epochs[epoch], block)
+	}
+
+	if err := db.MarkBlocksWithoutProposer(preGenesis); err != nil {
+		return err
+	}
+
+	attributed, pending, orphaned := 0, 0, 0
+	requests := 0
+	for epoch, epochBlocks := range epochs {
+		if requests > 0 {
+			time.Sleep(getRPCDelay())
+		}
+		requests++
+
+		beaconBlocks, err := rpc.GetCanonicalBeaconBlocksByEpoch(epoch)
+		if err != nil {
+			// Leave the blocks unattributed so they are retried on the next run
+			return fmt.Errorf("failed to get beacon blocks for epoch %d: %w", epoch, err)
+		}
+		// An empty slot is only final once its epoch is, and an epoch without
+		// any canonical block means the beacon node could not serve it
+		final := epoch <= finalizedEpoch && len(beaconBlocks) > 0
+
+		for _, block := range epochBlocks {
+			slot := slots[block.Result.Hash]
+			beaconBlock, ok := beaconBlocks[slot]
+			switch {
+			case !ok && final:
+				if err := db.UpdateBlockProposer(block.Result.Hash, slot, nil); err != nil {
+					return err
+				}
+			case !ok:
+				if err := db.CountProposerAttempt(block.Result.Hash); err != nil {
+					return err
+				}
+				pending++
+			case !strings.EqualFold(beaconBlock.ExecutionBlockHash, block.Result.Hash):
+				// The slot went to another payload. Until the epoch is final
+				// either side may still change, so the block is retried a
+				// bounded number of times before it is taken as orphaned.
+				configs.Logger.Warn("Beacon block payload does not match execution block",
+					zap.Int64("slot", slot),
+					zap.String("blockHash", block.Result.Hash),
+					zap.String("payloadHash", beaconBlock.ExecutionBlockHash))
+				if final || block.ProposerAttempts+1 >= proposerMaxAttempts {
+					if err := db.MarkBlockOrphaned(block.Result.Hash, slot); err != nil {
+						return err
+					}
+					orphaned++
+					continue
+				}
+				if err := db.CountProposerAttempt(block.Result.Hash); err != nil {
+					return err
+				}
+				pending++
+			default:
+				if err := db.UpdateBlockProposer(block.Result.Hash, slot, beaconBlock); err != nil {
+					return err
+				}
+				attributed++
+			}
+		}
+	}
+
+	// Blocks past the checkpoint have all been looked at; the ones left
+	// pending are found again below it
+	if len(newBlocks) > 0 {
+		if err := db.StoreProposerCheckpoint(newBlocks[len(newBlocks)-1].BlockNum + 1); err != nil {
+			return err
+		}
+	}
+
+	if len(blocks) > 0 {
+		configs.Logger.Info("Attributed block proposers",
+			zap.Int("blocks", len(blocks)),
+			zap.Int("attributed", attributed),
+			zap.Int("pending", pending),
+			zap.Int("orphaned", orphaned),
+			zap.Int("preGenesis", len(preGenesis)))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package synchroniser
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/db"
+	"Zond2mongoDB/rpc"
+
+	"go.uber.org/zap"
+)
+
+// revertReasonBatchSize is the maximum number of failed transactions explained per run
+const revertReasonBatchSize = 50
+
+// syncRevertReasonsPeriodically explains failed transactions stored since the last run
+func syncRevertReasonsPeriodically() {
+	if err := syncRevertReasons(); err != nil {
+		configs.Logger.Error("Failed to sync revert reasons", zap.Error(err))
+	}
+}
+
+// syncRevertReasons determines the revert reasons of failed transactions that
+// block processing marked as pending. Custom errors are decoded with the
+// verified ABI of the called contract when there is one. Transactions that
+// cannot be explained are retried on later runs up to db.RevertReasonMaxAttempts.
+func syncRevertReasons() error {
+	pending, err := db.GetPendingRevertReasons(revertReasonBatchSize)
+	if err != nil {
+		return err
+	}
+
+	explained := 0
+	for _, p := range pending {
+		tx, err := db.GetBlockTransaction(p.BlockNumber, p.TxHash)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			configs.Logger.Warn("Transaction of pending revert reason is not stored",
+				zap.String("txHash", p.TxHash),
+				zap.String("blockNumber", p.BlockNumber))
+			if err := db.CountRevertAttempt(p); err != nil {
+				return err
+			}
+			continue
+		}
+
+		contractABI, err := db.GetVerifiedContractABI(tx.To)
+		if err != nil {
+			return err
+		}
+
+		reason, err := rpc.GetRevertReason(tx, contractABI)
+		if err != nil {
+			configs.Logger.Warn("Failed to determine revert reason",
+				zap.String("txHash", p.TxHash),
+				zap.Int("attempt", p.Attempts+1),
+				zap.Error(err))
+			if err := db.CountRevertAttempt(p); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := db.StoreRevertReason(p.TxHash, reason); err != nil {
+			return err
+		}
+		explained++
+	}
+
+	if explained > 0 {
+		configs.Logger.Info("Stored revert reasons", zap.Int("count", explained))
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package synchroniser
+
+import (
+	"Zond2mongoDB/configs"
+	"Zond2mongoDB/db"
+	"Zond2mongoDB/models"
+	"Zond2mongoDB/rpc"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// slotBatchEpochs is the maximum number of epochs of new slots stored per run
+const slotBatchEpochs = 8
+
+// epochSlots holds the beacon data of one epoch: every block seen for its
+// slots and, once needed for a missed slot, its proposer duties
+type epochSlots struct {
+	blocks map[int64][]models.BeaconBlockInfo
+	duties map[int64]int64
+}
+
+// syncSlotsPeriodically records new beacon slots and finalizes earlier ones
+func syncSlotsPeriodically() {
+	if err := syncSlots(); err != nil {
+		configs.Logger.Error("Failed to sync slots", zap.Error(err))
+	}
+}
+
+// syncSlots stores slots up to the one before the head slot. Slots stored
+// before finality are re-checked once finalized, since a block can still be
+// orphaned until then. Beacon blocks and proposer duties are fetched once per
+// epoch; an epoch whose data cannot be fetched ends the run and is retried on
+// the next one.
+func syncSlots() error {
+	genesis, err := rpc.GetGenesisTime()
+	if err != nil {
+		return fmt.Errorf("failed to get genesis time: %w", err)
+	}
+
+	chainHead, err := rpc.GetBeaconChainHead()
+	if err != nil {
+		return fmt.Errorf("failed to get beacon chain head: %w", err)
+	}
+	headSlot, err := strconv.ParseInt(chainHead.HeadSlot, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid head slot %q: %w", chainHead.HeadSlot, err)
+	}
+	finalizedSlot, err := strconv.ParseInt(chainHead.FinalizedSlot, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid finalized slot %q: %w", chainHead.FinalizedSlot, err)
+	}
+
+	epochs := make(map[int64]*epochSlots)
+	var records []models.SlotRecord
+
+	// Re-check slots that have become final since they were stored
+	unfinalized, err := db.GetUnfinalizedSlots(finalizedSlot, slotBatchEpochs*configs.SLOTS_PER_EPOCH)
+	if err != nil {
+		return err
+	}
+	refinalized := 0
+	for _, s := range unfinalized {
+		record, err := buildSlotRecord(s.Slot, genesis, finalizedSlot, epochs)
+		if err != nil {
+			configs.Logger.Warn("Stopping slot re-check early", zap.Int64("slot", s.Slot), zap.Error(err))
+			break
+		}
+		records = append(records, record)
+		refinalized++
+	}
+
+	// Advance through new slots, leaving the head slot until it is complete
+	lastSlot, err := db.GetLastSyncedSlot()
+	if err != nil {
+		return err
+	}
+	from := lastSlot + 1
+	to := headSlot - 1
+	if limit := (from/configs.SLOTS_PER_EPOCH+slotBatchEpochs)*configs.SLOTS_PER_EPOCH - 1; to > limit {
+		to = limit
+	}
+	for slot := from; slot <= to; slot++ {
+		record, err := buildSlotRecord(slot, genesis, finalizedSlot, epochs)
+		if err != nil {
+			// Store what we have so progress is not lost
+			to = slot - 1
+			configs.Logger.Warn("Stopping slot sync early", zap.Int64("slot", slot), zap.Error(err))
+			break
+		}
+		records = append(records, record)
+	}
+
+	if err := db.UpsertSlots(records); err != nil {
+		return err
+	}
+	if to >= from {
+		if err := db.StoreLastSyncedSlot(to); err != nil {
+			return err
+		}
+		configs.Logger.Info("Synced slots",
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+			zap.Int("refinalized", refinalized))
+	}
+	return nil
+}
+
+// loadEpochSlots returns the beacon blocks of an epoch, fetching them on first
+// use. Failed fetches are not cached so a later call retries them.
+func loadEpochSlots(epoch int64, epochs map[int64]*epochSlots) (*epochSlots, error) {
+	if data, ok := epochs[epoch]; ok {
+		return data, nil
+	}
+
+	blocks, err := rpc.GetBeaconBlocksByEpoch(epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get beacon blocks for epoch %d: %w", epoch, err)
+	}
+
+	data := &epochSlots{blocks: make(map[int64][]models.BeaconBlockInfo)}
+	for _, b := range blocks {
+		data.blocks[b.Slot] = append(data.blocks[b.Slot], b)
+	}
+	epochs[epoch] = data
+	return data, nil
+}
+
+// buildSlotRecord classifies a slot as proposed, orphaned or missed from the
+// beacon blocks seen for it. For missed slots the proposer is looked up from
+// the epoch's proposer duties, which must be available for the slot to be
+// stored.
+func buildSlotRecord(slot, genesis, finalizedSlot int64, epochs map[int64]*epochSlots) (models.SlotRecord, error) {
+	record := models.SlotRecord{
+		ID:        slot,
+		Slot:      slot,
+		Epoch:     slot / configs.SLOTS_PER_EPOCH,
+		Status:    models.SlotStatusMissed,
+		Timestamp: genesis + slot*configs.SECONDS_PER_SLOT,
+		Finalized: slot <= finalizedSlot,
+	}
+
+	data, err := loadEpochSlots(record.Epoch, epochs)
+	if err != nil {
+		return record, err
+	}
+
+	var block *models.BeaconBlockInfo
+	blocks := data.blocks[slot]
+	for i := range blocks {
+		if blocks[i].Canonical {
+			block = &blocks[i]
+			record.Status = models.SlotStatusProposed
+			break
+		}
+		if block == nil {
+			block = &blocks[i]
+			record.Status = models.SlotStatusOrphaned
+		}
+	}
+
+	if block != nil {
+		proposer := block.ProposerIndex
+		record.ProposerIndex = &proposer
+		record.ExecutionBlockHash = block.ExecutionBlockHash
+		record.ExecutionBlockNumber = block.ExecutionBlockNumber
+		record.BlockRoot = block.BlockRoot
+		record.Graffiti = block.Graffiti
+		return record, nil
+	}
+
+	if data.duties == nil {
+		duties, err := rpc.GetProposerDuties(record.Epoch)
+		if err != nil {
+			return record, fmt.Errorf("failed to get proposer duties for epoch %d: %w", record.Epoch, err)
+		}
+		if len(duties) == 0 {
+			return record, fmt.Errorf("no proposer duties returned for epoch %d", record.Epoch)
+		}
+		data.duties = duties
+	}
+	if proposer, ok := data.duties[slot]; ok {
+		record.ProposerIndex = &proposer
+	}
+
+	return record, nil
+}
@@ -26,4 +26,17 @@ var DailyTransactionsVolumeCollection *mongo.Collection = GetCollection(DB, "dai
 var EpochInfoCollection *mongo.Collection = GetCollection(DB, "epoch_info")
 var ValidatorHistoryCollection *mongo.Collection = GetCollection(DB, "validator_history")
 var PriceHistoryCollection *mongo.Collection = GetCollection(DB, "priceHistory")
+var ValidatorBalancesCollection *mongo.Collection = GetCollection(DB, "validator_balances")
+var NetworkRewardsCollection *mongo.Collection = GetCollection(DB, "network_rewards")
+var SlotsCollection *mongo.Collection = GetCollection(DB, "slots")
+var ValidatorAttestationsCollection *mongo.Collection = GetCollection(DB, "validator_attestations")
+var NetworkParticipationCollection *mongo.Collection = GetCollection(DB, "network_participation")
+var ValidatorEventsCollection *mongo.Collection = GetCollection(DB, "validator_events")
+var DepositsCollection *mongo.Collection = GetCollection(DB, "deposits")
+var FeeHistoryCollection *mongo.Collection = GetCollection(DB, "feeHistory")
+var ReceiptsCollection *mongo.Collection = GetCollection(DB, "receipts")
+var NetworkStatsCollection *mongo.Collection = GetCollection(DB, "networkStats")
+var SupplyCollection *mongo.Collection = GetCollection(DB, "supply")
+var AddressLabelsCollection *mongo.Collection = GetCollection(DB, "addressLabels")
+var VerifiedContractsCollection *mongo.Collection = GetCollection(DB, "verifiedContracts")
 var Validate = validator.New()
@@ -74,6 +74,31 @@ func createIndexes(db *mongo.Database) {
 			},
 			Options: options.Index().SetName("result_hash"),
 		},
+		{
+			Keys: bson.D{
+				{Key: "proposerIndex", Value: 1},
+				{Key: "slot", Value: -1},
+			},
+			Options: options.Index().SetName("proposerIndex_slot_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "blockNum", Value: -1},
+			},
+			Options: options.Index().SetName("blockNum_desc"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "result.timestamp", Value: 1},
+			},
+			Options: options.Index().SetName("result_timestamp"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "timestampNum", Value: 1},
+			},
+			Options: options.Index().SetName("timestampNum_idx"),
+		},
 	}
 
 	// Transactions collection indexes
@@ -90,12 +115,205 @@ func createIndexes(db *mongo.Database) {
 			},
 			Options: options.Index().SetName("tx_hash"),
 		},
+		{
+			Keys: bson.D{
+				{Key: "blockNum", Value: -1},
+				{Key: "txIndex", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("position_desc"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "from", Value: 1},
+				{Key: "blockNum", Value: -1},
+				{Key: "txIndex", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("from_position_desc"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "to", Value: 1},
+				{Key: "blockNum", Value: -1},
+				{Key: "txIndex", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("to_position_desc"),
+		},
+	}
+
+	// Internal transactions collection indexes, in insertion order and by block for range filters
+	internalTransactionsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "from", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("from_id_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "to", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("to_id_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "from", Value: 1},
+				{Key: "blockNum", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("from_blockNum_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "to", Value: 1},
+				{Key: "blockNum", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("to_blockNum_idx"),
+		},
+	}
+
+	// Validators collection indexes (one document per validator, _id is the index)
+	validatorsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "publicKeyHex", Value: 1},
+			},
+			Options: options.Index().SetName("publicKeyHex_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("status_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "withdrawalCredentialsHex", Value: 1},
+			},
+			Options: options.Index().SetName("withdrawalCredentialsHex_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "withdrawalAddress", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("withdrawalAddress_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "activationEpochNum", Value: 1},
+			},
+			Options: options.Index().SetName("status_activationEpochNum_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "exitEpochNum", Value: 1},
+			},
+			Options: options.Index().SetName("status_exitEpochNum_idx"),
+		},
+	}
+
+	// Case-insensitive collation of the autocomplete indexes, matched by search queries
+	caseInsensitive := &options.Collation{Locale: "en", Strength: 2}
+
+	// Address labels collection indexes (_id is the address)
+	addressLabelsIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "category", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetName("category_name_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetName("name_ci_idx").SetCollation(caseInsensitive),
+		},
+	}
+
+	// Addresses collection indexes for lookups and prefix search by address
+	addressesIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "id", Value: 1},
+			},
+			Options: options.Index().SetName("id_idx"),
+		},
+	}
+
+	// Contract collection indexes for address lookups and token search
+	contractCodeIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "address", Value: 1},
+			},
+			Options: options.Index().SetName("address_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "creatorAddress", Value: 1},
+			},
+			Options: options.Index().SetName("creatorAddress_idx"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "symbol", Value: 1},
+			},
+			Options: options.Index().SetName("symbol_ci_idx").SetCollation(caseInsensitive),
+		},
+		{
+			Keys: bson.D{
+				{Key: "name", Value: 1},
+				{Key: "_id", Value: 1},
+			},
+			Options: options.Index().SetName("name_id_ci_idx").SetCollation(caseInsensitive),
+		},
+	}
+
+	// Token transfer indexes for address activity in chain order
+	tokenTransfersIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "from", Value: 1},
+				{Key: "blockNum", Value: -1},
+				{Key: "txIndex", Value: -1},
+				{Key: "logIndex", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("from_position_desc"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "to", Value: 1},
+				{Key: "blockNum", Value: -1},
+				{Key: "txIndex", Value: -1},
+				{Key: "logIndex", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName("to_position_desc"),
+		},
 	}
 
 	// Check and create indexes if needed
 	collections := map[string][]mongo.IndexModel{
-		"blocks":               blocksIndexes,
-		"transactionByAddress": transactionsIndexes,
+		"blocks":                       blocksIndexes,
+		"transactionByAddress":         transactionsIndexes,
+		"internalTransactionByAddress": internalTransactionsIndexes,
+		"validators":                   validatorsIndexes,
+		"addressLabels":                addressLabelsIndexes,
+		"addresses":                    addressesIndexes,
+		"contractCode":                 contractCodeIndexes,
+		"tokenTransfers":               tokenTransfersIndexes,
 	}
 
 	for collName, indexes := range collections {
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getAttestationSummary derives attestation rates from a validator's rolling
+// performance and attaches its most recent per-epoch results
+func getAttestationSummary(ctx context.Context, v models.ValidatorRecord) (*models.AttestationSummary, error) {
+	perf := v.AttestationPerformance
+	if perf == nil || perf.Epochs == 0 {
+		return nil, nil
+	}
+
+	epochs := float64(perf.Epochs)
+	summary := &models.AttestationSummary{
+		Effectiveness:     perf.Effectiveness,
+		EpochsTracked:     perf.Epochs,
+		InclusionRate:     float64(perf.Included) / epochs,
+		CorrectSourceRate: float64(perf.CorrectSource) / epochs,
+		CorrectTargetRate: float64(perf.CorrectTarget) / epochs,
+		CorrectHeadRate:   float64(perf.CorrectHead) / epochs,
+		LastEpoch:         perf.LastEpoch,
+	}
+	if perf.Included > 0 {
+		summary.AverageInclusionDistance = float64(perf.TotalInclusionDistance) / float64(perf.Included)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "epoch", Value: -1}}).
+		SetLimit(10)
+
+	cursor, err := configs.ValidatorAttestationsCollection.Find(ctx, bson.M{"validatorIndex": v.ID}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator attestations: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	summary.Recent = make([]models.ValidatorAttestationRecord, 0)
+	if err := cursor.All(ctx, &summary.Recent); err != nil {
+		return nil, fmt.Errorf("failed to decode validator attestations: %v", err)
+	}
+
+	return summary, nil
+}
+
+// GetNetworkParticipation returns network-wide participation for the most recent epochs
+func GetNetworkParticipation(limit int) (*models.NetworkParticipationResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "epoch", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := configs.NetworkParticipationCollection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network participation: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := make([]models.NetworkParticipationRecord, 0)
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode network participation: %v", err)
+	}
+
+	return &models.NetworkParticipationResponse{History: history}, nil
+}
@@ -0,0 +1,419 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The lookups below load the documents of many keys in one query. GraphQL
+// resolvers use them so nested fields of a list cost one query per level
+// instead of one per item. Keys that are not stored are absent from the maps.
+
+// GetBlocksByNumbers returns the stored blocks with the given numbers, keyed by number
+func GetBlocksByNumbers(ctx context.Context, numbers []int64) (map[int64]models.ZondUint64Version, error) {
+	blocks := make(map[int64]models.ZondUint64Version, len(numbers))
+	if len(numbers) == 0 {
+		return blocks, nil
+	}
+
+	cursor, err := configs.BlocksCollection.Find(ctx, bson.M{"blockNum": bson.M{"$in": numbers}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var block models.ZondUint64Version
+		if err := cursor.Decode(&block); err != nil {
+			continue
+		}
+		blocks[HexToInt(block.Result.Number)] = block
+	}
+	return blocks, cursor.Err()
+}
+
+// GetTransactionsByHashes returns the mined transactions with the given
+// hashes, keyed by hash. Transactions are read from their stored blocks,
+// located through the receipt or, without one, the address index.
+func GetTransactionsByHashes(ctx context.Context, hashes []string) (map[string]models.Transaction, error) {
+	transactions := make(map[string]models.Transaction, len(hashes))
+	if len(hashes) == 0 {
+		return transactions, nil
+	}
+
+	wanted := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		wanted[strings.ToLower(hash)] = true
+	}
+
+	receipts, err := receiptsByHash(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]int64, 0, len(hashes))
+	missing := make([]string, 0)
+	for hash := range wanted {
+		if receipt, ok := receipts[hash]; ok {
+			numbers = append(numbers, HexToInt(receipt.BlockNumber))
+		} else {
+			missing = append(missing, hash)
+		}
+	}
+
+	if len(missing) > 0 {
+		cursor, err := configs.TransactionByAddressCollection.Find(ctx,
+			bson.M{"txHash": bson.M{"$in": missing}},
+			options.Find().SetProjection(bson.M{"blockNum": 1}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query transaction blocks: %v", err)
+		}
+		var positions []models.TransactionByAddress
+		if err := cursor.All(ctx, &positions); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction blocks: %v", err)
+		}
+		for _, position := range positions {
+			numbers = append(numbers, position.BlockNum)
+		}
+	}
+
+	blocks, err := GetBlocksByNumbers(ctx, numbers)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		for _, tx := range block.Result.Transactions {
+			if wanted[tx.Hash] {
+				transactions[tx.Hash] = tx
+			}
+		}
+	}
+	return transactions, nil
+}
+
+// GetReceiptsByHashes returns the stored receipts of the given transactions, keyed by hash
+func GetReceiptsByHashes(ctx context.Context, hashes []string) (map[string]models.Receipt, error) {
+	return receiptsByHash(ctx, hashes)
+}
+
+// GetAddressesByIDs returns the stored addresses, keyed by Z-prefix lowercase
+// address. BalanceWei is always set.
+func GetAddressesByIDs(ctx context.Context, addresses []string) (map[string]models.AddressRecord, error) {
+	records := make(map[string]models.AddressRecord, len(addresses))
+	if len(addresses) == 0 {
+		return records, nil
+	}
+
+	ids := make(bson.A, 0, len(addresses))
+	for _, address := range addresses {
+		ids = append(ids, strings.ToLower(normalizeAddress(address)))
+	}
+	cursor, err := configs.AddressesCollections.Find(ctx, bson.M{"id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query addresses: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var record models.AddressRecord
+		if err := cursor.Decode(&record); err != nil {
+			continue
+		}
+		if record.BalanceWei == "" {
+			// Addresses stored before exact balances were kept only have the QRL amount
+			record.BalanceWei = qrlToWei(record.Balance)
+		}
+		records[normalizeAddress(record.ID)] = record
+	}
+	return records, cursor.Err()
+}
+
+// GetContractsByAddresses returns the stored contracts, keyed by Z-prefix lowercase address
+func GetContractsByAddresses(ctx context.Context, addresses []string) (map[string]models.ContractInfo, error) {
+	contracts := make(map[string]models.ContractInfo, len(addresses))
+	if len(addresses) == 0 {
+		return contracts, nil
+	}
+
+	variants := make(bson.A, 0, 2*len(addresses))
+	for _, address := range addresses {
+		normalized := normalizeAddress(address)
+		variants = append(variants, normalized, strings.ToLower(normalized))
+	}
+	cursor, err := configs.ContractInfoCollection.Find(ctx, bson.M{"address": bson.M{"$in": variants}},
+		options.Find().SetProjection(bson.M{"contractCode": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contracts: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var contract models.ContractInfo
+		if err := cursor.Decode(&contract); err != nil {
+			continue
+		}
+		contracts[normalizeAddress(contract.ContractAddress)] = contract
+	}
+	return contracts, cursor.Err()
+}
+
+// GetTokenBalancesByHolders returns the token balances of the given holders,
+// keyed by Z-prefix lowercase holder address. Token metadata is not joined.
+func GetTokenBalancesByHolders(ctx context.Context, holders []string) (map[string][]models.TokenBalance, error) {
+	balances := make(map[string][]models.TokenBalance, len(holders))
+	if len(holders) == 0 {
+		return balances, nil
+	}
+
+	variants := make(bson.A, 0, 2*len(holders))
+	for _, holder := range holders {
+		normalized := normalizeAddress(holder)
+		variants = append(variants, normalized, strings.ToLower(normalized))
+	}
+	cursor, err := configs.GetCollection(configs.DB, "tokenBalances").Find(ctx,
+		bson.M{"holderAddress": bson.M{"$in": variants}},
+		options.Find().SetSort(bson.D{{Key: "contractAddress", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token balances: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var balance models.TokenBalance
+		if err := cursor.Decode(&balance); err != nil {
+			continue
+		}
+		key := normalizeAddress(balance.HolderAddress)
+		balances[key] = append(balances[key], balance)
+	}
+	return balances, cursor.Err()
+}
+
+// GetTokenTransfersByTxHashes returns the token transfers of the given
+// transactions in log order, keyed by transaction hash
+func GetTokenTransfersByTxHashes(ctx context.Context, hashes []string) (map[string][]models.TokenTransfer, error) {
+	transfers := make(map[string][]models.TokenTransfer, len(hashes))
+	if len(hashes) == 0 {
+		return transfers, nil
+	}
+
+	cursor, err := configs.GetCollection(configs.DB, "tokenTransfers").Find(ctx,
+		bson.M{"txHash": bson.M{"$in": hashes}},
+		options.Find().SetSort(bson.D{{Key: "logIndex", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token transfers: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var transfer models.TokenTransfer
+		if err := cursor.Decode(&transfer); err != nil {
+			continue
+		}
+		key := strings.ToLower(transfer.TxHash)
+		transfers[key] = append(transfers[key], transfer)
+	}
+	return transfers, cursor.Err()
+}
+
+// GetValidatorsByIndexes returns the validators with the given indexes, keyed
+// by index, with Status set to the status at the current epoch
+func GetValidatorsByIndexes(ctx context.Context, indexes []int64) (map[int64]models.ValidatorRecord, error) {
+	validators := make(map[int64]models.ValidatorRecord, len(indexes))
+	if len(indexes) == 0 {
+		return validators, nil
+	}
+
+	cursor, err := configs.ValidatorsCollections.Find(ctx, bson.M{"_id": bson.M{"$in": indexes}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validators: %v", err)
+	}
+	var records []models.ValidatorRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode validators: %v", err)
+	}
+	if len(records) == 0 {
+		return validators, nil
+	}
+
+	currentEpoch, err := getCurrentEpoch()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range records {
+		v.Status = getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch)
+		validators[v.ID] = v
+	}
+	return validators, nil
+}
+
+// AddressPage selects one page of the activity of an address. Cursor is a
+// token from EncodeCursor, empty for the first page, so pages can be map keys.
+type AddressPage struct {
+	Address string
+	Limit   int
+	Cursor  string
+}
+
+// Page is one page of items and the cursor of the page after it
+type Page[T any] struct {
+	Items []T
+	Next  string
+}
+
+// GetNonZeroTransactionPages returns pages of the value transfers of several
+// addresses, newest first, keyed by page
+func GetNonZeroTransactionPages(ctx context.Context, pages []AddressPage) (map[AddressPage]Page[models.TransactionByAddress], error) {
+	sort := keysetSort(transactionPositionFields)
+	pipelines := make([]mongo.Pipeline, 0, len(pages))
+	for _, page := range pages {
+		cursor, err := DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter, err := nonZeroTransactionsFilter(page.Address, cursor)
+		if err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$sort", Value: sort}},
+			{{Key: "$limit", Value: page.Limit}},
+			{{Key: "$project", Value: nonZeroTransactionProjection}},
+		})
+	}
+
+	results, err := aggregatePages[models.TransactionByAddress](ctx, configs.TransactionByAddressCollection, pipelines, sort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %v", err)
+	}
+	transactions := make(map[AddressPage]Page[models.TransactionByAddress], len(pages))
+	for i, page := range pages {
+		for j := range results[i] {
+			setTransactionDirection(&results[i][j], page.Address)
+		}
+		transactions[page] = Page[models.TransactionByAddress]{Items: results[i], Next: transactionsNextCursor(results[i], page.Limit)}
+	}
+	return transactions, nil
+}
+
+// GetTokenTransferPages returns pages of the token transfers sent or received
+// by several addresses, newest first, keyed by page
+func GetTokenTransferPages(ctx context.Context, pages []AddressPage) (map[AddressPage]Page[models.TokenTransfer], error) {
+	sort := keysetSort(tokenTransferPositionFields)
+	pipelines := make([]mongo.Pipeline, 0, len(pages))
+	for _, page := range pages {
+		cursor, err := DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter, err := tokenTransfersByAddressFilter(page.Address, cursor)
+		if err != nil {
+			return nil, err
+		}
+		pipelines = append(pipelines, mongo.Pipeline{
+			{{Key: "$match", Value: filter}},
+			{{Key: "$sort", Value: sort}},
+			{{Key: "$limit", Value: page.Limit}},
+		})
+	}
+
+	results, err := aggregatePages[models.TokenTransfer](ctx, configs.GetCollection(configs.DB, "tokenTransfers"), pipelines, sort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token transfers: %v", err)
+	}
+	transfers := make(map[AddressPage]Page[models.TokenTransfer], len(pages))
+	for i, page := range pages {
+		transfers[page] = Page[models.TokenTransfer]{Items: results[i], Next: tokenTransfersNextCursor(results[i], page.Limit)}
+	}
+	return transfers, nil
+}
+
+// GetValidatorsByWithdrawalAddresses returns the validators withdrawing to the
+// given addresses in index order, keyed by Z-prefix lowercase address
+func GetValidatorsByWithdrawalAddresses(ctx context.Context, addresses []string) (map[string][]models.ValidatorRecord, error) {
+	validators := make(map[string][]models.ValidatorRecord, len(addresses))
+	if len(addresses) == 0 {
+		return validators, nil
+	}
+
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		normalized = append(normalized, normalizeAddress(address))
+	}
+	cursor, err := configs.ValidatorsCollections.Find(ctx,
+		bson.M{"withdrawalAddress": bson.M{"$in": normalized}},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validators by withdrawal address: %v", err)
+	}
+	var records []models.ValidatorRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode validators: %v", err)
+	}
+	if len(records) == 0 {
+		return validators, nil
+	}
+
+	currentEpoch, err := getCurrentEpoch()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range records {
+		v.Status = getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch)
+		address := normalizeAddress(v.WithdrawalAddress)
+		validators[address] = append(validators[address], v)
+	}
+	return validators, nil
+}
+
+// aggregatePages reads one page per pipeline in a single query. The
+// pipelines are joined with $unionWith, so each still uses its indexes, and
+// their documents are tagged with the page they belong to, then sorted back
+// into page order.
+func aggregatePages[T any](ctx context.Context, collection *mongo.Collection, pipelines []mongo.Pipeline, sort bson.D) ([][]T, error) {
+	pages := make([][]T, len(pipelines))
+	if len(pipelines) == 0 {
+		return pages, nil
+	}
+
+	tagged := func(i int) mongo.Pipeline {
+		pipeline := append(mongo.Pipeline{}, pipelines[i]...)
+		return append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{"_page": i}}})
+	}
+	pipeline := tagged(0)
+	for i := 1; i < len(pipelines); i++ {
+		pipeline = append(pipeline, bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     collection.Name(),
+			"pipeline": tagged(i),
+		}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: append(bson.D{{Key: "_page", Value: 1}}, sort...)}})
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var tag struct {
+			Page int `bson:"_page"`
+		}
+		var item T
+		if err := cursor.Decode(&tag); err != nil || tag.Page < 0 || tag.Page >= len(pages) {
+			continue
+		}
+		if err := cursor.Decode(&item); err != nil {
+			continue
+		}
+		pages[tag.Page] = append(pages[tag.Page], item)
+	}
+	return pages, cursor.Err()
+}
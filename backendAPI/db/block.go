@@ -56,7 +56,9 @@ func GetLatestBlockFromSyncState() (string, error) {
 	return result.BlockNumber, nil
 }
 
-func ReturnLatestBlocks(page int, limit int) ([]models.Result, error) {
+// ReturnLatestBlocks returns a page of blocks, newest first, and the cursor of
+// the next page. A cursor continues after its block; otherwise page is used.
+func ReturnLatestBlocks(page int, limit int, cursor *PageCursor) ([]models.Result, string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	var blocks []models.Result
 	defer cancel()
@@ -72,19 +74,25 @@ func ReturnLatestBlocks(page int, limit int) ([]models.Result, error) {
 		{Key: "result.transactions", Value: 1},
 	}
 
+	positionFields := []string{"blockNum"}
 	opts := options.Find().
 		SetProjection(projection).
-		SetSort(primitive.D{{Key: "result.timestamp", Value: -1}})
-
-	if page == 0 {
-		page = 1
+		SetSort(keysetSort(positionFields))
+
+	filter := primitive.M{}
+	if cursor != nil {
+		filter = keysetAfter(positionFields, []interface{}{cursor.Block})
+	} else {
+		if page == 0 {
+			page = 1
+		}
+		opts.SetSkip(int64((page - 1) * limit))
 	}
-	opts.SetSkip(int64((page - 1) * limit))
 	opts.SetLimit(int64(limit))
 
-	results, err := configs.BlocksCollection.Find(ctx, primitive.D{}, opts)
+	results, err := configs.BlocksCollection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	defer results.Close(ctx)
@@ -96,7 +104,12 @@ func ReturnLatestBlocks(page int, limit int) ([]models.Result, error) {
 		blocks = append(blocks, singleBlock.Result)
 	}
 
-	return blocks, nil
+	next := ""
+	if len(blocks) == limit {
+		last, _ := strconv.ParseInt(strings.TrimPrefix(blocks[len(blocks)-1].Number, "0x"), 16, 64)
+		next = EncodeCursor(PageCursor{Block: last})
+	}
+	return blocks, next, nil
 }
 
 func CountBlocksNetwork() (int64, error) {
@@ -120,7 +133,7 @@ func ReturnHashToBlockNumber(query string) (uint64, error) {
 	filter := primitive.D{{Key: "result.hash", Value: query}}
 	err := configs.BlocksCollection.FindOne(ctx, filter).Decode(&result)
 	if err != nil {
-		return 0, fmt.Errorf("failed to find block: %v", err)
+		return 0, fmt.Errorf("failed to find block: %w", err)
 	}
 
 	// Convert hex string to uint64
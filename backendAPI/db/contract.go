@@ -4,16 +4,25 @@ import (
 	"backendAPI/configs"
 	"backendAPI/models"
 	"context"
+	"fmt"
 	"log"
 	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func ReturnContracts(page int64, limit int64, search string, isTokenFilter *bool) ([]models.ContractInfo, int64, error) {
+// ReturnContracts returns a page of contracts, latest first, and the cursor of
+// the next page. A cursor continues after its contract; otherwise page is used.
+// A search that is a full address matches the contract or creator address; any
+// other search is matched case-insensitively as a prefix of the token name or
+// symbol through the collation indexes, so user input is never interpreted as
+// a pattern. Name and symbol matches are ordered by name, which walks the name
+// index instead of sorting the matches in memory.
+func ReturnContracts(page int64, limit int64, search string, isTokenFilter *bool, cursor *PageCursor) ([]models.ContractInfo, int64, string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -28,24 +37,35 @@ func ReturnContracts(page int64, limit int64, search string, isTokenFilter *bool
 		filter = append(filter, bson.E{Key: "isToken", Value: *isTokenFilter})
 	}
 
-	// Add search if provided, using correct field names
-	if search != "" {
-		// Normalize the search address to lowercase for case-insensitive lookup
-		normalizedSearch := strings.ToLower(search)
-
-		// Also try with Z prefix in case user pastes address without it
-		searchWithZ := normalizedSearch
-		if !strings.HasPrefix(normalizedSearch, "z") {
-			searchWithZ = "z" + normalizedSearch
-		}
+	countOpts := options.Count().SetMaxTime(searchMaxTime)
+	findOpts := options.Find().SetMaxTime(searchMaxTime)
+
+	// Contracts are paged latest first, or by name for a name or symbol search
+	byName := false
 
-		// Zond addresses start with 'Z'. Search for both with and without Z prefix.
-		searchFilter := bson.D{
-			{Key: "$or", Value: bson.A{
-				bson.D{{Key: "address", Value: bson.M{"$in": bson.A{normalizedSearch, searchWithZ}}}},        // Match contract address
-				bson.D{{Key: "creatorAddress", Value: bson.M{"$in": bson.A{normalizedSearch, searchWithZ}}}}, // Match creator address
-				bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: normalizedSearch}, {Key: "$options", Value: "i"}}}}, // Match token name
-			}},
+	// Add search if provided, using correct field names
+	if search = strings.TrimSpace(search); search != "" {
+		var searchFilter bson.D
+		if hex := strings.ToLower(search); isAddressSearch(hex) {
+			// Contracts are stored with a lowercase "z" prefix by the syncer and
+			// "Z" by older code, so both spellings are matched
+			address := addressVariants(hex)
+			searchFilter = bson.D{
+				{Key: "$or", Value: bson.A{
+					bson.D{{Key: "address", Value: address}},        // Match contract address
+					bson.D{{Key: "creatorAddress", Value: address}}, // Match creator address
+				}},
+			}
+		} else {
+			searchFilter = bson.D{
+				{Key: "$or", Value: bson.A{
+					bson.D{{Key: "name", Value: prefixRange(search)}},   // Match token name
+					bson.D{{Key: "symbol", Value: prefixRange(search)}}, // Match token symbol
+				}},
+			}
+			countOpts.SetCollation(caseInsensitive)
+			findOpts.SetCollation(caseInsensitive)
+			byName = true
 		}
 		// Combine with existing filter
 		if len(filter) > 0 {
@@ -56,27 +76,47 @@ func ReturnContracts(page int64, limit int64, search string, isTokenFilter *bool
 	}
 
 	// Get total count for pagination
-	total, err := configs.ContractInfoCollection.CountDocuments(ctx, filter)
+	total, err := configs.ContractInfoCollection.CountDocuments(ctx, filter, countOpts)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", searchError(err)
 	}
 
 	// Set up pagination options
-	skip := page * limit
-	opts := options.Find().
-		SetSkip(skip).
-		SetLimit(limit).
-		SetSort(bson.D{{Key: "_id", Value: -1}}) // Latest first
+	findOpts.SetLimit(limit)
+	if byName {
+		positionFields := []string{"name", "_id"}
+		findOpts.SetSort(keysetSortAscending(positionFields))
+		if cursor != nil {
+			id, err := primitive.ObjectIDFromHex(cursor.Key)
+			if err != nil {
+				return nil, 0, "", ErrInvalidCursor
+			}
+			filter = bson.D{{Key: "$and", Value: bson.A{filter, keysetAfterAscending(positionFields, []interface{}{cursor.Name, id})}}}
+		}
+	} else {
+		positionFields := []string{"_id"}
+		findOpts.SetSort(keysetSort(positionFields)) // Latest first
+		if cursor != nil {
+			id, err := primitive.ObjectIDFromHex(cursor.Key)
+			if err != nil {
+				return nil, 0, "", ErrInvalidCursor
+			}
+			filter = bson.D{{Key: "$and", Value: bson.A{filter, keysetAfter(positionFields, []interface{}{id})}}}
+		}
+	}
+	if cursor == nil {
+		findOpts.SetSkip(page * limit)
+	}
 
-	cursor, err := configs.ContractInfoCollection.Find(ctx, filter, opts)
+	results, err := configs.ContractInfoCollection.Find(ctx, filter, findOpts)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", searchError(err)
 	}
-	defer cursor.Close(ctx)
+	defer results.Close(ctx)
 
 	// Decode directly into the slice of models.ContractInfo
-	if err := cursor.All(ctx, &contracts); err != nil {
-		return nil, 0, err
+	if err := results.All(ctx, &contracts); err != nil {
+		return nil, 0, "", searchError(err)
 	}
 
 	// Return empty slice instead of nil if no contracts found
@@ -84,7 +124,34 @@ func ReturnContracts(page int64, limit int64, search string, isTokenFilter *bool
 		contracts = make([]models.ContractInfo, 0)
 	}
 
-	return contracts, total, nil
+	next := ""
+	if int64(len(contracts)) == limit {
+		last := contracts[len(contracts)-1]
+		position := PageCursor{Key: last.ID.Hex()}
+		if byName {
+			position.Name = last.TokenName
+		}
+		next = EncodeCursor(position)
+	}
+	return contracts, total, next, nil
+}
+
+// GetVerifiedContract returns the verified source of a contract, or nil when
+// its source has not been verified
+func GetVerifiedContract(address string) (*models.VerifiedContract, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var contract models.VerifiedContract
+	err := configs.VerifiedContractsCollection.FindOne(ctx,
+		bson.M{"address": strings.ToLower(normalizeAddress(address))}).Decode(&contract)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get verified contract: %v", err)
+	}
+	return &contract, nil
 }
 
 func ReturnContractCode(address string) (models.ContractInfo, error) {
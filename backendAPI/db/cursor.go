@@ -0,0 +1,93 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrInvalidCursor is returned for page cursors that cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageCursor is the position of the last item of a page. Chain data is
+// positioned by block number, transaction index and log index; token holders
+// by balance and contracts by document id, or by name when searching, with Key
+// breaking ties. Clients only see it as an opaque token.
+type PageCursor struct {
+	Block   int64  `json:"b,omitempty"`
+	Tx      int64  `json:"t,omitempty"`
+	Log     int64  `json:"l,omitempty"`
+	Balance string `json:"v,omitempty"`
+	Name    string `json:"n,omitempty"`
+	Key     string `json:"k,omitempty"`
+}
+
+// EncodeCursor turns a position into an opaque URL-safe token
+func EncodeCursor(cursor PageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token produced by EncodeCursor. An empty token yields
+// a nil cursor, meaning the first page.
+func DecodeCursor(token string) (*PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// keysetAfter matches the documents that come after a position in a
+// descending sort on the given fields, e.g. for fields (a, b) and values
+// (x, y): a < x, or a == x and b < y
+func keysetAfter(fields []string, values []interface{}) bson.M {
+	return keysetPast(fields, values, "$lt")
+}
+
+// keysetAfterAscending is keysetAfter for an ascending sort
+func keysetAfterAscending(fields []string, values []interface{}) bson.M {
+	return keysetPast(fields, values, "$gt")
+}
+
+// keysetPast matches the documents past a position, comparing the first field
+// that differs with op
+func keysetPast(fields []string, values []interface{}, op string) bson.M {
+	clauses := make(bson.A, 0, len(fields))
+	for i := range fields {
+		clause := bson.M{}
+		for j := 0; j < i; j++ {
+			clause[fields[j]] = values[j]
+		}
+		clause[fields[i]] = bson.M{op: values[i]}
+		clauses = append(clauses, clause)
+	}
+	return bson.M{"$or": clauses}
+}
+
+// keysetSort sorts descending on the given fields
+func keysetSort(fields []string) bson.D {
+	return keysetOrder(fields, -1)
+}
+
+// keysetSortAscending sorts ascending on the given fields
+func keysetSortAscending(fields []string) bson.D {
+	return keysetOrder(fields, 1)
+}
+
+func keysetOrder(fields []string, direction int) bson.D {
+	sort := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		sort = append(sort, bson.E{Key: field, Value: direction})
+	}
+	return sort
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"backendAPI/models"
 	"testing"
 	"time"
 )
@@ -19,3 +20,25 @@ func TestDateTime(t *testing.T) {
 // 	got :=
 // 	want :=
 // }
+
+func TestGetBlockFees(t *testing.T) {
+	tests := []struct {
+		name          string
+		block         models.Result
+		baseFee, used string
+		burnt         string
+	}{
+		{name: "london block", block: models.Result{BaseFeePerGas: "0x3b9aca00", GasUsed: "0x5208"}, baseFee: "1000000000", used: "21000", burnt: "21000000000000"},
+		{name: "empty block", block: models.Result{BaseFeePerGas: "0x7", GasUsed: "0x0"}, baseFee: "7", used: "0", burnt: "0"},
+		{name: "before base fee", block: models.Result{GasUsed: "0x5208"}, baseFee: "0", used: "21000", burnt: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees := GetBlockFees(tt.block)
+			if fees.BaseFeePerGas != tt.baseFee || fees.GasUsed != tt.used || fees.BurntFee != tt.burnt {
+				t.Errorf("got %+v, wanted base fee %s, gas used %s, burnt %s", fees, tt.baseFee, tt.used, tt.burnt)
+			}
+		})
+	}
+}
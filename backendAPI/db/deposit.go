@@ -0,0 +1,74 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReturnDeposits returns a page of deposits, newest first, optionally filtered by sender address
+func ReturnDeposits(page, limit int64, sender string) (*models.DepositsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 25
+	}
+
+	filter := bson.M{}
+	if sender != "" {
+		filter["sender"] = normalizeAddress(sender)
+	}
+
+	total, err := configs.DepositsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count deposits: %v", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
+
+	cursor, err := configs.DepositsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deposits: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	deposits := make([]models.Deposit, 0)
+	if err := cursor.All(ctx, &deposits); err != nil {
+		return nil, fmt.Errorf("failed to decode deposits: %v", err)
+	}
+
+	return &models.DepositsResponse{
+		Deposits: deposits,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// getValidatorDeposits returns the deposits made for a validator public key, oldest first
+func getValidatorDeposits(ctx context.Context, publicKeyHex string) ([]models.Deposit, error) {
+	cursor, err := configs.DepositsCollection.Find(ctx, bson.M{"publicKeyHex": publicKeyHex},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator deposits: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	deposits := make([]models.Deposit, 0)
+	if err := cursor.All(ctx, &deposits); err != nil {
+		return nil, fmt.Errorf("failed to decode validator deposits: %v", err)
+	}
+	return deposits, nil
+}
@@ -0,0 +1,607 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EtherscanMaxResults is the largest result window (page x offset) of the Etherscan API
+const EtherscanMaxResults = 10000
+
+// ErrNoClosestBlock is returned by GetBlockNumberByTime when no block is on the requested side
+var ErrNoClosestBlock = errors.New("no closest block found")
+
+// EtherscanQuery selects the entries of an Etherscan account list action
+type EtherscanQuery struct {
+	Address         string
+	ContractAddress string
+	TxHash          string
+	StartBlock      int64
+	EndBlock        int64 // Inclusive; 0 means no upper bound
+	Page            int   // 1-based
+	Offset          int   // Page size
+	Descending      bool
+}
+
+// skip returns the number of entries before the requested page
+func (q EtherscanQuery) skip() int64 {
+	return int64((q.Page - 1) * q.Offset)
+}
+
+// blockRange matches integer block numbers within the query's block range
+func (q EtherscanQuery) blockRange() bson.M {
+	blockRange := bson.M{"$gte": q.StartBlock}
+	if q.EndBlock > 0 {
+		blockRange["$lte"] = q.EndBlock
+	}
+	return blockRange
+}
+
+// GetEtherscanBalance returns the current balance of an address in wei as a
+// decimal string, read from the node like /getBalance
+func GetEtherscanBalance(address string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	params, err := json.Marshal([]string{normalizeAddress(address), "latest"})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode balance request: %v", err)
+	}
+	result, rpcErr, err := forwardRPC(ctx, "zond_getBalance", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to get balance: %v", err)
+	}
+	if rpcErr != nil {
+		return "", fmt.Errorf("failed to get balance: %s", rpcErr.Message)
+	}
+
+	var balance string
+	if err := json.Unmarshal(result, &balance); err != nil || !strings.HasPrefix(balance, "0x") {
+		return "", fmt.Errorf("invalid balance %s", result)
+	}
+	wei, ok := new(big.Int).SetString(strings.TrimPrefix(balance, "0x"), 16)
+	if !ok {
+		return "", fmt.Errorf("invalid balance %s", balance)
+	}
+	return wei.String(), nil
+}
+
+// GetEtherscanTransactions returns a page of the transactions of an address in
+// chain order, enriched with the stored block transaction and receipt
+func GetEtherscanTransactions(q EtherscanQuery) ([]models.EtherscanTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"$or":      bson.A{bson.M{"from": addressVariants(q.Address)}, bson.M{"to": addressVariants(q.Address)}},
+		"blockNum": q.blockRange(),
+	}
+	opts := options.Find().
+		SetSort(etherscanSort(transactionPositionFields, q.Descending)).
+		SetSkip(q.skip()).
+		SetLimit(int64(q.Offset))
+
+	cursor, err := configs.TransactionByAddressCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var txs []models.TransactionByAddress
+	if err := cursor.All(ctx, &txs); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %v", err)
+	}
+
+	blockNums := make([]int64, 0, len(txs))
+	hashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		blockNums = append(blockNums, tx.BlockNum)
+		hashes = append(hashes, tx.TxHash)
+	}
+	details, err := loadChainDetails(ctx, blockNums, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.EtherscanTransaction, 0, len(txs))
+	for _, tx := range txs {
+		chainTx := details.transactions[tx.TxHash]
+		receipt := details.receipts[tx.TxHash]
+
+		value := hexToDecimal(chainTx.Value)
+		if chainTx.Hash == "" {
+			value = qrlToWei(tx.Amount)
+		}
+		timestamp, _ := exportTimestamp(tx.TimeStamp)
+		result = append(result, models.EtherscanTransaction{
+			BlockNumber:       strconv.FormatInt(tx.BlockNum, 10),
+			TimeStamp:         timestamp,
+			Hash:              tx.TxHash,
+			Nonce:             hexToDecimal(chainTx.Nonce),
+			BlockHash:         details.blockHashes[tx.BlockNum],
+			TransactionIndex:  strconv.FormatInt(tx.TxIndex, 10),
+			From:              etherscanAddress(tx.From),
+			To:                etherscanAddress(tx.To),
+			Value:             value,
+			Gas:               hexToDecimal(chainTx.Gas),
+			GasPrice:          hexToDecimal(chainTx.GasPrice),
+			IsError:           receiptIsError(receipt),
+			TxReceiptStatus:   receiptStatus(receipt),
+			Input:             inputOrEmpty(chainTx.Data),
+			ContractAddress:   etherscanAddress(receipt.ContractAddress),
+			CumulativeGasUsed: hexToDecimal(receipt.CumulativeGasUsed),
+			GasUsed:           hexToDecimal(receipt.GasUsed),
+			Confirmations:     details.confirmations(tx.BlockNum),
+			MethodID:          methodID(chainTx.Data),
+		})
+	}
+	return result, nil
+}
+
+// GetEtherscanInternalTransactions returns a page of the internal transactions
+// of an address or of a single transaction in chain order. Values are exact
+// wei and isError and errCode come from the traced call. Documents stored
+// before the syncer kept those are completed from the stored receipt and
+// block transaction.
+func GetEtherscanInternalTransactions(q EtherscanQuery) ([]models.EtherscanInternalTransaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	filter := bson.M{"hash": strings.ToLower(q.TxHash)}
+	if q.Address != "" {
+		filter = bson.M{"$or": bson.A{bson.M{"from": addressVariants(q.Address)}, bson.M{"to": addressVariants(q.Address)}}}
+	}
+
+	// Clients pass block ranges past the chain head, such as endblock=99999999,
+	// so the range is clamped to the stored blocks
+	head, err := storedChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head < 0 || q.StartBlock > head {
+		return []models.EtherscanInternalTransaction{}, nil
+	}
+	end := head
+	if q.EndBlock > 0 && q.EndBlock < head {
+		end = q.EndBlock
+	}
+	blocks := bson.M{"$gte": q.StartBlock, "$lte": end}
+
+	cursor, err := configs.InternalTransactionByAddressCollection.Find(ctx, withBlockFilter(filter, blocks),
+		options.Find().
+			SetSort(etherscanSort([]string{"blockNum", "_id"}, q.Descending)).
+			SetBatchSize(exportBatchSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query internal transactions: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	// Documents without a block number are checked against the time range of
+	// the blocks while reading, so the page is cut out here
+	var legacyRange *ExportRange
+	skip := q.skip()
+	var txs []internalTransaction
+	for cursor.Next(ctx) && len(txs) < q.Offset {
+		var tx internalTransaction
+		if err := cursor.Decode(&tx); err != nil {
+			continue
+		}
+		if tx.BlockNum == nil {
+			if legacyRange == nil {
+				start, last := uint64(q.StartBlock), uint64(end)
+				if legacyRange, err = exportTimeRange(ctx, ExportRange{FromBlock: &start, ToBlock: &last}); err != nil {
+					return nil, err
+				}
+			}
+			if !legacyRange.containsTime(tx.BlockTimestamp) {
+				continue
+			}
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read internal transactions: %v", err)
+	}
+
+	hashes := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash)
+	}
+	receipts, err := receiptsByHash(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Block transactions are only needed for the exact value of documents
+	// stored without one
+	var blockNums []int64
+	for _, tx := range txs {
+		if tx.ValueWei != "" {
+			continue
+		}
+		if tx.BlockNum != nil {
+			blockNums = append(blockNums, *tx.BlockNum)
+		} else if number, err := strconv.ParseInt(strings.TrimPrefix(receipts[tx.Hash].BlockNumber, "0x"), 16, 64); err == nil {
+			blockNums = append(blockNums, number)
+		}
+	}
+	details, err := loadChainDetails(ctx, blockNums, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.EtherscanInternalTransaction, 0, len(txs))
+	for _, tx := range txs {
+		traceID := make([]string, 0, len(tx.TraceAddress))
+		for _, index := range tx.TraceAddress {
+			traceID = append(traceID, strconv.Itoa(index))
+		}
+		timestamp, _ := exportTimestamp(tx.BlockTimestamp)
+		receipt := receipts[tx.Hash]
+
+		blockNumber := hexToDecimal(receipt.BlockNumber)
+		if tx.BlockNum != nil {
+			blockNumber = strconv.FormatInt(*tx.BlockNum, 10)
+		}
+		value := tx.ValueWei
+		if value == "" {
+			if blockTx, ok := details.transactions[tx.Hash]; ok {
+				value = hexToDecimal(blockTx.Value)
+			} else {
+				// Without the block the rounded QRL amount is all there is
+				value = qrlToWei(tx.Value)
+			}
+		}
+		isError, errCode := "0", ""
+		if tx.Error != nil {
+			if *tx.Error != "" {
+				isError, errCode = "1", *tx.Error
+			}
+		} else {
+			isError = receiptIsError(receipt)
+		}
+
+		result = append(result, models.EtherscanInternalTransaction{
+			BlockNumber: blockNumber,
+			TimeStamp:   timestamp,
+			Hash:        tx.Hash,
+			From:        etherscanAddress(tx.From),
+			To:          etherscanAddress(tx.To),
+			Value:       value,
+			Input:       "",
+			Type:        strings.ToLower(tx.Type),
+			Gas:         hexToDecimal(tx.Gas),
+			GasUsed:     hexToDecimal(tx.GasUsed),
+			TraceID:     strings.Join(traceID, "_"),
+			IsError:     isError,
+			ErrCode:     errCode,
+		})
+	}
+	return result, nil
+}
+
+// GetEtherscanTokenTransfers returns a page of the token transfers of an
+// address, of a token contract, or of an address within one token contract
+func GetEtherscanTokenTransfers(q EtherscanQuery) ([]models.EtherscanTokenTransfer, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	filter := bson.M{"blockNum": q.blockRange()}
+	if q.Address != "" {
+		filter["$or"] = bson.A{bson.M{"from": addressVariants(q.Address)}, bson.M{"to": addressVariants(q.Address)}}
+	}
+	if q.ContractAddress != "" {
+		filter["contractAddress"] = addressVariants(q.ContractAddress)
+	}
+	opts := options.Find().
+		SetSort(etherscanSort([]string{"blockNum", "txIndex", "logIndex", "txHash"}, q.Descending)).
+		SetSkip(q.skip()).
+		SetLimit(int64(q.Offset))
+
+	cursor, err := configs.GetCollection(configs.DB, "tokenTransfers").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query token transfers: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var transfers []models.TokenTransfer
+	if err := cursor.All(ctx, &transfers); err != nil {
+		return nil, fmt.Errorf("failed to decode token transfers: %v", err)
+	}
+
+	blockNums := make([]int64, 0, len(transfers))
+	hashes := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		blockNums = append(blockNums, transfer.BlockNum)
+		hashes = append(hashes, transfer.TxHash)
+	}
+	details, err := loadChainDetails(ctx, blockNums, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.EtherscanTokenTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		chainTx := details.transactions[transfer.TxHash]
+		receipt := details.receipts[transfer.TxHash]
+		timestamp, _ := exportTimestamp(transfer.Timestamp)
+		result = append(result, models.EtherscanTokenTransfer{
+			BlockNumber:       strconv.FormatInt(transfer.BlockNum, 10),
+			TimeStamp:         timestamp,
+			Hash:              transfer.TxHash,
+			Nonce:             hexToDecimal(chainTx.Nonce),
+			BlockHash:         details.blockHashes[transfer.BlockNum],
+			From:              etherscanAddress(transfer.From),
+			ContractAddress:   etherscanAddress(transfer.ContractAddress),
+			To:                etherscanAddress(transfer.To),
+			Value:             hexToDecimal(transfer.Amount),
+			TokenName:         transfer.TokenName,
+			TokenSymbol:       transfer.TokenSymbol,
+			TokenDecimal:      strconv.Itoa(transfer.TokenDecimals),
+			TransactionIndex:  strconv.FormatInt(transfer.TxIndex, 10),
+			Gas:               hexToDecimal(chainTx.Gas),
+			GasPrice:          hexToDecimal(chainTx.GasPrice),
+			GasUsed:           hexToDecimal(receipt.GasUsed),
+			CumulativeGasUsed: hexToDecimal(receipt.CumulativeGasUsed),
+			Input:             "deprecated",
+			Confirmations:     details.confirmations(transfer.BlockNum),
+		})
+	}
+	return result, nil
+}
+
+// GetEtherscanTokenBalance returns the token balance of an address in the
+// token's smallest unit, "0" if the address never held the token
+func GetEtherscanTokenBalance(contractAddress, address string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var balance models.TokenBalance
+	err := configs.GetCollection(configs.DB, "tokenBalances").FindOne(ctx, bson.M{
+		"contractAddress": addressVariants(contractAddress),
+		"holderAddress":   addressVariants(address),
+	}).Decode(&balance)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "0", nil
+		}
+		return "", fmt.Errorf("failed to get token balance: %v", err)
+	}
+	return hexToDecimal(balance.Balance), nil
+}
+
+// storedChainHead returns the number of the newest stored block, or -1 when
+// none is stored
+func storedChainHead(ctx context.Context) (int64, error) {
+	var block struct {
+		BlockNum int64 `bson:"blockNum"`
+	}
+	err := configs.BlocksCollection.FindOne(ctx, bson.M{},
+		options.FindOne().
+			SetSort(bson.D{{Key: "blockNum", Value: -1}}).
+			SetProjection(bson.M{"blockNum": 1})).Decode(&block)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return -1, nil
+		}
+		return 0, fmt.Errorf("failed to get chain head: %v", err)
+	}
+	return block.BlockNum, nil
+}
+
+// GetBlockNumberByTime returns the number of the last block at or before a
+// Unix timestamp, or of the first block at or after it
+func GetBlockNumberByTime(timestamp int64, after bool) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Hex timestamps do not order as strings, so the integer copy the syncer
+	// stores alongside them is searched
+	filter := bson.M{"timestampNum": bson.M{"$lte": timestamp}}
+	sort := bson.D{{Key: "timestampNum", Value: -1}}
+	if after {
+		filter = bson.M{"timestampNum": bson.M{"$gte": timestamp}}
+		sort = bson.D{{Key: "timestampNum", Value: 1}}
+	}
+
+	var block models.ZondUint64Version
+	err := configs.BlocksCollection.FindOne(ctx, filter,
+		options.FindOne().SetSort(sort).SetProjection(bson.M{"result.number": 1})).Decode(&block)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, ErrNoClosestBlock
+		}
+		return 0, fmt.Errorf("failed to find block by time: %v", err)
+	}
+	return strconv.ParseInt(strings.TrimPrefix(block.Result.Number, "0x"), 16, 64)
+}
+
+// GetEtherscanPrice returns the latest QRL price. Only the USD price is
+// tracked, so the BTC fields are left empty. Returns nil before the first
+// price update.
+func GetEtherscanPrice() (*models.EtherscanPrice, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var result models.CoinGecko
+	err := configs.CoinGeckoCollection.FindOne(ctx, bson.M{}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get price: %v", err)
+	}
+	return &models.EtherscanPrice{
+		EthUSD:          strconv.FormatFloat(result.PriceUSD, 'f', -1, 64),
+		EthUSDTimestamp: strconv.FormatInt(result.LastUpdated.Unix(), 10),
+	}, nil
+}
+
+// chainDetails holds the stored block transactions and receipts that complete
+// transaction list entries
+type chainDetails struct {
+	transactions map[string]models.Transaction
+	blockHashes  map[int64]string
+	receipts     map[string]models.Receipt
+	latestBlock  int64
+}
+
+// confirmations returns the number of blocks on top of a block, counting itself
+func (d chainDetails) confirmations(blockNum int64) string {
+	if d.latestBlock < blockNum {
+		return "0"
+	}
+	return strconv.FormatInt(d.latestBlock-blockNum+1, 10)
+}
+
+// loadChainDetails loads the transactions of the given blocks and the receipts
+// of the given transaction hashes
+func loadChainDetails(ctx context.Context, blockNums []int64, hashes []string) (chainDetails, error) {
+	details := chainDetails{
+		transactions: make(map[string]models.Transaction),
+		blockHashes:  make(map[int64]string),
+	}
+
+	if len(blockNums) > 0 {
+		cursor, err := configs.BlocksCollection.Find(ctx,
+			bson.M{"blockNum": bson.M{"$in": blockNums}},
+			options.Find().SetProjection(bson.M{"blockNum": 1, "result.hash": 1, "result.transactions": 1}))
+		if err != nil {
+			return details, fmt.Errorf("failed to query blocks: %v", err)
+		}
+		for cursor.Next(ctx) {
+			var block struct {
+				BlockNum int64 `bson:"blockNum"`
+				Result   struct {
+					Hash         string               `bson:"hash"`
+					Transactions []models.Transaction `bson:"transactions"`
+				} `bson:"result"`
+			}
+			if err := cursor.Decode(&block); err != nil {
+				continue
+			}
+			details.blockHashes[block.BlockNum] = block.Result.Hash
+			for _, tx := range block.Result.Transactions {
+				details.transactions[tx.Hash] = tx
+			}
+		}
+		cursor.Close(ctx)
+	}
+
+	receipts, err := receiptsByHash(ctx, hashes)
+	if err != nil {
+		return details, err
+	}
+	details.receipts = receipts
+
+	if latest, err := GetLatestBlockFromSyncState(); err == nil {
+		details.latestBlock, _ = strconv.ParseInt(strings.TrimPrefix(latest, "0x"), 16, 64)
+	}
+	return details, nil
+}
+
+// receiptsByHash loads the stored receipts of the given transactions
+func receiptsByHash(ctx context.Context, hashes []string) (map[string]models.Receipt, error) {
+	receipts := make(map[string]models.Receipt, len(hashes))
+	if len(hashes) == 0 {
+		return receipts, nil
+	}
+	cursor, err := configs.ReceiptsCollection.Find(ctx,
+		bson.M{"_id": bson.M{"$in": hashes}},
+		options.Find().SetProjection(bson.M{"logs": 0, "logsBloom": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query receipts: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var receipt models.Receipt
+		if err := cursor.Decode(&receipt); err == nil {
+			receipts[receipt.TxHash] = receipt
+		}
+	}
+	return receipts, cursor.Err()
+}
+
+// etherscanSort sorts on the given fields in chain order or its reverse
+func etherscanSort(fields []string, descending bool) bson.D {
+	if descending {
+		return keysetSort(fields)
+	}
+	sort := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		sort = append(sort, bson.E{Key: field, Value: 1})
+	}
+	return sort
+}
+
+// etherscanAddress formats a stored address in Z-prefix lowercase form
+func etherscanAddress(address string) string {
+	if address == "" {
+		return ""
+	}
+	return normalizeAddress(address)
+}
+
+// receiptIsError is "1" for reverted transactions, as in Etherscan's isError
+func receiptIsError(receipt models.Receipt) string {
+	if receipt.Status == "0x0" {
+		return "1"
+	}
+	return "0"
+}
+
+// receiptStatus is the receipt status as "1" or "0", empty without a receipt
+func receiptStatus(receipt models.Receipt) string {
+	switch receipt.Status {
+	case "0x1":
+		return "1"
+	case "0x0":
+		return "0"
+	}
+	return ""
+}
+
+// inputOrEmpty returns the call data, "0x" when there is none
+func inputOrEmpty(data string) string {
+	if data == "" {
+		return "0x"
+	}
+	return data
+}
+
+// methodID returns the function selector of call data, "0x" for plain transfers
+func methodID(data string) string {
+	if len(data) < 10 {
+		return "0x"
+	}
+	return data[:10]
+}
+
+// qrlToWei converts a QRL amount to a decimal wei string. Amounts stored as
+// floating point QRL are not exact, so the result is rounded to whole wei.
+func qrlToWei(amount float64) string {
+	if amount == 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return "0"
+	}
+	wei := new(big.Float).Mul(big.NewFloat(amount), big.NewFloat(1e18))
+	result, _ := wei.Int(nil)
+	return result.String()
+}
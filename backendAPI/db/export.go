@@ -0,0 +1,464 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Export columns, in output order
+var (
+	AddressTransactionColumns  = []string{"txHash", "blockNumber", "timestamp", "date", "direction", "from", "to", "amount", "paidFees", "txType"}
+	InternalTransactionColumns = []string{"txHash", "timestamp", "date", "direction", "type", "callType", "from", "to", "value", "gas", "gasUsed"}
+	TokenTransferColumns       = []string{"txHash", "blockNumber", "timestamp", "date", "from", "to", "amount", "tokenSymbol", "tokenDecimals", "transferType"}
+	TokenHolderColumns         = []string{"holderAddress", "balance", "blockNumber", "updatedAt"}
+)
+
+// exportBatchSize is the number of documents fetched per cursor batch
+const exportBatchSize = 500
+
+// ExportRange limits an export to a block and/or time range; nil bounds are open
+type ExportRange struct {
+	FromBlock *uint64
+	ToBlock   *uint64
+	FromTime  *int64 // Unix seconds, inclusive
+	ToTime    *int64 // Unix seconds, inclusive
+}
+
+// containsBlock reports whether a hex block number is within the block range
+func (r ExportRange) containsBlock(hexNumber string) bool {
+	if r.FromBlock == nil && r.ToBlock == nil {
+		return true
+	}
+	number, err := strconv.ParseUint(strings.TrimPrefix(hexNumber, "0x"), 16, 64)
+	if err != nil {
+		return false
+	}
+	return (r.FromBlock == nil || number >= *r.FromBlock) && (r.ToBlock == nil || number <= *r.ToBlock)
+}
+
+// containsTime reports whether a hex timestamp is within the time range
+func (r ExportRange) containsTime(hexTimestamp string) bool {
+	if r.FromTime == nil && r.ToTime == nil {
+		return true
+	}
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(hexTimestamp, "0x"), 16, 64)
+	if err != nil {
+		return false
+	}
+	return (r.FromTime == nil || timestamp >= *r.FromTime) && (r.ToTime == nil || timestamp <= *r.ToTime)
+}
+
+// ExportAddressTransactions streams the transactions of an address, oldest
+// first, calling emit with one row of AddressTransactionColumns per transaction
+func ExportAddressTransactions(ctx context.Context, address string, r ExportRange, emit func([]string) error) error {
+	blocks, ok, err := exportBlockFilter(r)
+	if err != nil || !ok {
+		return err
+	}
+
+	normalized := normalizeAddress(address)
+	variants := bson.A{strings.ToLower(normalized), normalized}
+	filter := bson.M{"$or": bson.A{
+		bson.M{"from": bson.M{"$in": variants}},
+		bson.M{"to": bson.M{"$in": variants}},
+	}}
+
+	cursor, err := configs.TransactionByAddressCollection.Find(ctx, withBlockFilter(filter, blocks), exportFindOptions("blockNum", "txIndex"))
+	if err != nil {
+		return fmt.Errorf("failed to query transactions: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var tx models.TransactionByAddress
+		if err := cursor.Decode(&tx); err != nil || !blockFilterContains(blocks, tx.BlockNumber) {
+			continue
+		}
+		timestamp, date := exportTimestamp(tx.TimeStamp)
+		row := []string{
+			tx.TxHash,
+			hexToDecimal(tx.BlockNumber),
+			timestamp,
+			date,
+			exportDirection(normalized, tx.From, tx.To),
+			tx.From,
+			tx.To,
+			strconv.FormatFloat(tx.Amount, 'f', -1, 64),
+			strconv.FormatFloat(tx.PaidFees, 'f', -1, 64),
+			tx.TxType,
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read transactions: %v", err)
+	}
+	return nil
+}
+
+// internalTransaction is an internalTransactionByAddress document as stored by
+// the syncer. BlockNum, ValueWei and Error are missing from documents stored
+// before the syncer added them.
+type internalTransaction struct {
+	Type           string  `bson:"type"`
+	CallType       string  `bson:"callType"`
+	Hash           string  `bson:"hash"`
+	From           string  `bson:"from"`
+	To             string  `bson:"to"`
+	Value          float64 `bson:"value"`
+	ValueWei       string  `bson:"valueWei"`
+	Error          *string `bson:"error"`
+	Gas            string  `bson:"gas"`
+	GasUsed        string  `bson:"gasUsed"`
+	TraceAddress   []int   `bson:"traceAddress"`
+	BlockTimestamp string  `bson:"blockTimestamp"`
+	BlockNum       *int64  `bson:"blockNum"`
+}
+
+// ExportInternalTransactions streams the internal transactions of an address,
+// oldest first
+func ExportInternalTransactions(ctx context.Context, address string, r ExportRange, emit func([]string) error) error {
+	blocks, ok, err := exportBlockFilter(r)
+	if err != nil || !ok {
+		return err
+	}
+
+	normalized := normalizeAddress(address)
+	variants := bson.A{strings.ToLower(normalized), normalized}
+	filter := bson.M{"$or": bson.A{
+		bson.M{"from": bson.M{"$in": variants}},
+		bson.M{"to": bson.M{"$in": variants}},
+	}}
+
+	cursor, err := configs.InternalTransactionByAddressCollection.Find(ctx, withBlockFilter(filter, blocks), exportFindOptions("blockNum"))
+	if err != nil {
+		return fmt.Errorf("failed to query internal transactions: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	// Documents without a block number are checked against the time range
+	// of the blocks, which is only looked up once one is found
+	var legacyRange *ExportRange
+	for cursor.Next(ctx) {
+		var tx internalTransaction
+		if err := cursor.Decode(&tx); err != nil {
+			continue
+		}
+		if tx.BlockNum == nil && blocks != nil {
+			if legacyRange == nil {
+				if legacyRange, err = exportTimeRange(ctx, r); err != nil {
+					return err
+				}
+			}
+			if !legacyRange.containsTime(tx.BlockTimestamp) {
+				continue
+			}
+		}
+		timestamp, date := exportTimestamp(tx.BlockTimestamp)
+		row := []string{
+			tx.Hash,
+			timestamp,
+			date,
+			exportDirection(normalized, tx.From, tx.To),
+			tx.Type,
+			tx.CallType,
+			tx.From,
+			tx.To,
+			strconv.FormatFloat(tx.Value, 'f', -1, 64),
+			hexToDecimal(tx.Gas),
+			hexToDecimal(tx.GasUsed),
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read internal transactions: %v", err)
+	}
+	return nil
+}
+
+// ExportTokenTransfers streams the transfers of a token contract, oldest first
+func ExportTokenTransfers(ctx context.Context, contractAddress string, r ExportRange, emit func([]string) error) error {
+	blocks, ok, err := exportBlockFilter(r)
+	if err != nil || !ok {
+		return err
+	}
+
+	filter := withBlockFilter(bson.M{"contractAddress": normalizeAddress(contractAddress)}, blocks)
+
+	collection := configs.GetCollection(configs.DB, "tokenTransfers")
+	cursor, err := collection.Find(ctx, filter, exportFindOptions("blockNum", "txIndex", "logIndex"))
+	if err != nil {
+		return fmt.Errorf("failed to query token transfers: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var transfer models.TokenTransfer
+		if err := cursor.Decode(&transfer); err != nil || !blockFilterContains(blocks, transfer.BlockNumber) {
+			continue
+		}
+		timestamp, date := exportTimestamp(transfer.Timestamp)
+		row := []string{
+			transfer.TxHash,
+			hexToDecimal(transfer.BlockNumber),
+			timestamp,
+			date,
+			transfer.From,
+			transfer.To,
+			hexToDecimal(transfer.Amount),
+			transfer.TokenSymbol,
+			strconv.Itoa(transfer.TokenDecimals),
+			transfer.TransferType,
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read token transfers: %v", err)
+	}
+	return nil
+}
+
+// ExportTokenHolders streams the current holders of a token contract, largest
+// balance first. Balances are current, so a block range selects holders whose
+// balance last changed within it; time ranges do not apply.
+func ExportTokenHolders(ctx context.Context, contractAddress string, r ExportRange, emit func([]string) error) error {
+	collection := configs.GetCollection(configs.DB, "tokenBalances")
+	pipeline := []bson.M{
+		{"$match": bson.M{"contractAddress": normalizeAddress(contractAddress)}},
+		{"$addFields": bson.M{"balanceDecimal": bson.M{"$toDecimal": "$balance"}}},
+		{"$sort": bson.M{"balanceDecimal": -1}},
+		{"$project": bson.M{"balanceDecimal": 0}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline,
+		options.Aggregate().SetAllowDiskUse(true).SetBatchSize(exportBatchSize))
+	if err != nil {
+		return fmt.Errorf("failed to query token holders: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var holder models.TokenBalance
+		if err := cursor.Decode(&holder); err != nil {
+			continue
+		}
+		if !r.containsBlock(holder.BlockNumber) {
+			continue
+		}
+		row := []string{
+			holder.HolderAddress,
+			hexToDecimal(holder.Balance),
+			hexToDecimal(holder.BlockNumber),
+			holder.UpdatedAt,
+		}
+		if err := emit(row); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to read token holders: %v", err)
+	}
+	return nil
+}
+
+// exportFindOptions reads in ascending order of the given position fields, in
+// batches. _id breaks ties and is the only order of collections without them.
+func exportFindOptions(fields ...string) *options.FindOptions {
+	sort := bson.D{}
+	for _, field := range fields {
+		sort = append(sort, bson.E{Key: field, Value: 1})
+	}
+	sort = append(sort, bson.E{Key: "_id", Value: 1})
+	return options.Find().
+		SetSort(sort).
+		SetBatchSize(exportBatchSize).
+		SetNoCursorTimeout(false)
+}
+
+// withBlockFilter adds a blockNum condition from exportBlockFilter to filter.
+// Documents stored before the syncer backfilled blockNum are matched as well,
+// so exports stay complete while the backfill runs; readers check those with
+// blockFilterContains.
+func withBlockFilter(filter bson.M, blocks bson.M) bson.M {
+	if blocks == nil {
+		return filter
+	}
+	return bson.M{"$and": bson.A{filter, bson.M{"$or": bson.A{
+		bson.M{"blockNum": blocks},
+		bson.M{"blockNum": bson.M{"$exists": false}},
+	}}}}
+}
+
+// blockFilterContains reports whether a hex block number satisfies a blockNum
+// condition from exportBlockFilter
+func blockFilterContains(blocks bson.M, hexNumber string) bool {
+	if blocks == nil {
+		return true
+	}
+	number, err := strconv.ParseInt(strings.TrimPrefix(hexNumber, "0x"), 16, 64)
+	if err != nil {
+		return false
+	}
+	if from, ok := blocks["$gte"].(int64); ok && number < from {
+		return false
+	}
+	if to, ok := blocks["$lte"].(int64); ok && number > to {
+		return false
+	}
+	return true
+}
+
+// exportBlockFilter returns the blockNum condition of a range, with its time
+// bounds narrowed to the blocks produced within them, or nil when the range
+// is open. ok is false when no stored block falls within the range.
+func exportBlockFilter(r ExportRange) (bson.M, bool, error) {
+	filter := bson.M{}
+	if r.FromBlock != nil {
+		filter["$gte"] = int64(*r.FromBlock)
+	}
+	if r.ToBlock != nil {
+		filter["$lte"] = int64(*r.ToBlock)
+	}
+
+	if r.FromTime != nil {
+		number, err := GetBlockNumberByTime(*r.FromTime, true)
+		if err == ErrNoClosestBlock {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if r.FromBlock == nil || number > int64(*r.FromBlock) {
+			filter["$gte"] = number
+		}
+	}
+	if r.ToTime != nil {
+		number, err := GetBlockNumberByTime(*r.ToTime, false)
+		if err == ErrNoClosestBlock {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if r.ToBlock == nil || number < int64(*r.ToBlock) {
+			filter["$lte"] = number
+		}
+	}
+
+	if len(filter) == 0 {
+		return nil, true, nil
+	}
+	return filter, true, nil
+}
+
+// exportTimeRange returns a range with its block bounds replaced by the
+// timestamps of those blocks. Bounds past the newest stored block are clamped
+// to it; a range that starts past it matches no timestamp.
+func exportTimeRange(ctx context.Context, r ExportRange) (*ExportRange, error) {
+	head, err := storedChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head < 0 || (r.FromBlock != nil && int64(*r.FromBlock) > head) {
+		empty, after := int64(1), int64(0)
+		return &ExportRange{FromTime: &empty, ToTime: &after}, nil
+	}
+	if r.ToBlock != nil && int64(*r.ToBlock) > head {
+		end := uint64(head)
+		r.ToBlock = &end
+	}
+	if err := blockRangeToTime(ctx, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// blockRangeToTime replaces the block bounds of a range with the timestamps of those blocks
+func blockRangeToTime(ctx context.Context, r *ExportRange) error {
+	bound := func(number *uint64) (*int64, error) {
+		if number == nil {
+			return nil, nil
+		}
+		var block models.ZondUint64Version
+		err := configs.BlocksCollection.FindOne(ctx,
+			bson.M{"result.number": fmt.Sprintf("0x%x", *number)},
+			options.FindOne().SetProjection(bson.M{"result.timestamp": 1})).Decode(&block)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("block %d not found", *number)
+			}
+			return nil, fmt.Errorf("failed to get block %d: %v", *number, err)
+		}
+		timestamp, err := strconv.ParseInt(strings.TrimPrefix(block.Result.Timestamp, "0x"), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp of block %d", *number)
+		}
+		return &timestamp, nil
+	}
+
+	from, err := bound(r.FromBlock)
+	if err != nil {
+		return err
+	}
+	to, err := bound(r.ToBlock)
+	if err != nil {
+		return err
+	}
+	if from != nil && (r.FromTime == nil || *from > *r.FromTime) {
+		r.FromTime = from
+	}
+	if to != nil && (r.ToTime == nil || *to < *r.ToTime) {
+		r.ToTime = to
+	}
+	r.FromBlock, r.ToBlock = nil, nil
+	return nil
+}
+
+// exportDirection describes a transfer from the exported address's point of view
+func exportDirection(address, from, to string) string {
+	out := strings.EqualFold(from, address)
+	in := strings.EqualFold(to, address)
+	switch {
+	case out && in:
+		return "self"
+	case out:
+		return "out"
+	default:
+		return "in"
+	}
+}
+
+// exportTimestamp returns a hex timestamp as decimal Unix seconds and an RFC 3339 UTC date
+func exportTimestamp(hexTimestamp string) (string, string) {
+	timestamp, err := strconv.ParseInt(strings.TrimPrefix(hexTimestamp, "0x"), 16, 64)
+	if err != nil {
+		return hexTimestamp, ""
+	}
+	return strconv.FormatInt(timestamp, 10), time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
+
+// hexToDecimal converts a 0x-prefixed quantity to a decimal string; other values are returned unchanged
+func hexToDecimal(value string) string {
+	if !strings.HasPrefix(value, "0x") {
+		return value
+	}
+	number, ok := new(big.Int).SetString(value[2:], 16)
+	if !ok {
+		return value
+	}
+	return number.String()
+}
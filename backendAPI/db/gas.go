@@ -0,0 +1,296 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Percentiles used for each oracle tier; they must be among the percentiles stored by the syncer
+const (
+	slowTipPercentile     = 25
+	standardTipPercentile = 50
+	fastTipPercentile     = 90
+)
+
+// Blocks within which fast and standard transactions should be included. When
+// the pending pool holds more gas than fits in them, the tier's tip is raised
+// to the lowest tip that still fits.
+const (
+	fastPendingBlocks     = 1
+	standardPendingBlocks = 3
+	// pendingTipSample caps the pending transactions read per oracle request
+	pendingTipSample = 10000
+)
+
+// GetGasHistory returns fee market data for the most recent blocks
+func GetGasHistory(limit int) (*models.GasHistoryResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	history, err := getRecentFeeHistory(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GasHistoryResponse{History: history}, nil
+}
+
+// GetGasOracle suggests slow, standard and fast fees for the next block. Each
+// tier's tip is the median over recent non-empty blocks of a priority fee
+// percentile, raised for fast and standard when the pending pool is congested.
+// The max fee leaves room for the base fee to double.
+func GetGasOracle(blocks int) (*models.GasOracleResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	history, err := getRecentFeeHistory(ctx, blocks)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	latest := history[0]
+	nextBaseFee, ok := new(big.Int).SetString(latest.NextBaseFeePerGas, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid next base fee %q", latest.NextBaseFeePerGas)
+	}
+
+	tips := map[float64][]*big.Int{
+		slowTipPercentile:     nil,
+		standardTipPercentile: nil,
+		fastTipPercentile:     nil,
+	}
+	sampled := 0
+	for _, record := range history {
+		// Empty blocks report zero tips and would drag the suggestions down
+		if record.GasUsedRatio == 0 {
+			continue
+		}
+		sampled++
+		for i, percentile := range record.Percentiles {
+			if _, wanted := tips[percentile]; !wanted || i >= len(record.PriorityFees) {
+				continue
+			}
+			if tip, ok := new(big.Int).SetString(record.PriorityFees[i], 10); ok {
+				tips[percentile] = append(tips[percentile], tip)
+			}
+		}
+	}
+
+	pending, err := getPendingTips(ctx, nextBaseFee)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := getLatestGasLimit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suggest := func(percentile float64, pendingBlocks uint64) models.GasSuggestion {
+		tip := medianBigInt(tips[percentile])
+		if pendingBlocks > 0 && gasLimit > 0 {
+			if floor := pendingTipFloor(pending, gasLimit*pendingBlocks); floor.Cmp(tip) > 0 {
+				tip = floor
+			}
+		}
+		maxFee := new(big.Int).Mul(nextBaseFee, big.NewInt(2))
+		maxFee.Add(maxFee, tip)
+		return models.GasSuggestion{
+			MaxPriorityFeePerGas: tip.String(),
+			MaxFeePerGas:         maxFee.String(),
+		}
+	}
+
+	return &models.GasOracleResponse{
+		LastBlock:         latest.BlockNumber,
+		NextBaseFeePerGas: nextBaseFee.String(),
+		BlocksSampled:     sampled,
+		PendingSampled:    len(pending),
+		Slow:              suggest(slowTipPercentile, 0),
+		Standard:          suggest(standardTipPercentile, standardPendingBlocks),
+		Fast:              suggest(fastTipPercentile, fastPendingBlocks),
+	}, nil
+}
+
+// pendingTip is the tip a pending transaction pays at the next base fee
+type pendingTip struct {
+	tip *big.Int
+	gas uint64
+}
+
+// getPendingTips returns the pending transactions that can be included at
+// baseFee, highest tip first
+func getPendingTips(ctx context.Context, baseFee *big.Int) ([]pendingTip, error) {
+	cursor, err := configs.GetCollection(configs.DB, PENDING_COLLECTION).Find(ctx,
+		bson.M{"status": "pending"},
+		options.Find().
+			SetSort(bson.D{{Key: "lastSeen", Value: -1}}).
+			SetLimit(pendingTipSample).
+			SetProjection(bson.M{"gas": 1, "gasPrice": 1, "maxFeePerGas": 1, "maxPriorityFeePerGas": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending transactions: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var tips []pendingTip
+	for cursor.Next(ctx) {
+		var tx models.PendingTransaction
+		if err := cursor.Decode(&tx); err != nil {
+			continue
+		}
+
+		var tip *big.Int
+		if tx.MaxFeePerGas != "" {
+			tip = new(big.Int).Sub(hexToBigInt(tx.MaxFeePerGas), baseFee)
+			if priority := hexToBigInt(tx.MaxPriorityFeePerGas); priority.Cmp(tip) < 0 {
+				tip = priority
+			}
+		} else {
+			tip = new(big.Int).Sub(hexToBigInt(tx.GasPrice), baseFee)
+		}
+		if tip.Sign() < 0 {
+			continue
+		}
+		tips = append(tips, pendingTip{tip: tip, gas: hexToBigInt(tx.Gas).Uint64()})
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to decode pending transactions: %v", err)
+	}
+
+	sort.Slice(tips, func(i, j int) bool { return tips[i].tip.Cmp(tips[j].tip) > 0 })
+	return tips, nil
+}
+
+// pendingTipFloor returns the lowest tip among the pending transactions that
+// fit in capacity gas, or zero when the whole pool fits
+func pendingTipFloor(pending []pendingTip, capacity uint64) *big.Int {
+	var used uint64
+	for _, p := range pending {
+		used += p.gas
+		if used >= capacity {
+			return new(big.Int).Set(p.tip)
+		}
+	}
+	return big.NewInt(0)
+}
+
+// getLatestGasLimit returns the gas limit of the newest stored block
+func getLatestGasLimit(ctx context.Context) (uint64, error) {
+	var block models.ZondUint64Version
+	err := configs.BlocksCollection.FindOne(ctx, bson.M{},
+		options.FindOne().
+			SetSort(bson.D{{Key: "blockNum", Value: -1}}).
+			SetProjection(bson.M{"result.gasLimit": 1})).Decode(&block)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest gas limit: %v", err)
+	}
+	return hexToBigInt(block.Result.GasLimit).Uint64(), nil
+}
+
+// GetTransactionFees returns the fee breakdown stored for a mined transaction,
+// or nil if the syncer recorded none
+func GetTransactionFees(txHash string) (*models.TxFees, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var fees models.TxFees
+	err := configs.TransferCollections.FindOne(ctx,
+		bson.M{"txHash": strings.ToLower(txHash), "totalFee": bson.M{"$exists": true}},
+		options.FindOne().SetProjection(bson.M{
+			"gasUsed": 1, "effectiveGasPrice": 1, "baseFeePerGas": 1,
+			"burntFee": 1, "priorityFee": 1, "totalFee": 1,
+		})).Decode(&fees)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query transaction fees: %v", err)
+	}
+	return &fees, nil
+}
+
+// GetBlockFees computes the base fee burnt by a block from its hex header fields
+func GetBlockFees(block models.Result) models.BlockFees {
+	baseFee := hexToBigInt(block.BaseFeePerGas)
+	gasUsed := hexToBigInt(block.GasUsed)
+	return models.BlockFees{
+		BaseFeePerGas: baseFee.String(),
+		GasUsed:       gasUsed.String(),
+		BurntFee:      new(big.Int).Mul(baseFee, gasUsed).String(),
+	}
+}
+
+// GetTotalBurntFees returns the base fees burnt across the network up to the
+// last block with fee history, in wei
+func GetTotalBurntFees() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var state struct {
+		TotalBurnt string `bson:"totalBurnt"`
+	}
+	err := configs.GetCollection(configs.DB, "sync_state").FindOne(ctx, bson.M{"_id": "last_fee_history_block"}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "0", nil
+		}
+		return "0", fmt.Errorf("failed to get burnt fee total: %v", err)
+	}
+	if state.TotalBurnt == "" {
+		return "0", nil
+	}
+	return state.TotalBurnt, nil
+}
+
+// hexToBigInt parses a 0x-prefixed quantity, returning zero for empty or invalid input
+func hexToBigInt(hex string) *big.Int {
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(hex, "0x"), 16)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return n
+}
+
+func getRecentFeeHistory(ctx context.Context, limit int) ([]models.FeeHistoryRecord, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := configs.FeeHistoryCollection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fee history: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := make([]models.FeeHistoryRecord, 0)
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode fee history: %v", err)
+	}
+	return history, nil
+}
+
+// medianBigInt returns the median of values, or zero for an empty slice
+func medianBigInt(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return big.NewInt(0)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return new(big.Int).Set(values[mid])
+	}
+	sum := new(big.Int).Add(values[mid-1], values[mid])
+	return sum.Div(sum, big.NewInt(2))
+}
@@ -0,0 +1,259 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Label import file formats
+const (
+	LabelFormatJSON = "json"
+	LabelFormatCSV  = "csv"
+)
+
+// NormalizeLabel validates a label and stores its address in Z-prefix lowercase form
+func NormalizeLabel(label *models.AddressLabel) error {
+	address := strings.TrimSpace(label.Address)
+	if address == "" {
+		return fmt.Errorf("label address is required")
+	}
+	label.Address = normalizeAddress(address)
+	if len(label.Address) != 41 {
+		return fmt.Errorf("invalid label address %s", address)
+	}
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" {
+		return fmt.Errorf("label name is required for %s", label.Address)
+	}
+	label.Category = strings.ToLower(strings.TrimSpace(label.Category))
+	if !models.LabelCategories[label.Category] {
+		return fmt.Errorf("invalid label category %q for %s", label.Category, label.Address)
+	}
+	label.Source = strings.TrimSpace(label.Source)
+	return nil
+}
+
+// UpsertLabels validates and stores labels, replacing existing labels of the same addresses
+func UpsertLabels(labels []models.AddressLabel) (int64, error) {
+	if len(labels) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := time.Now().Unix()
+	operations := make([]mongo.WriteModel, 0, len(labels))
+	for i := range labels {
+		if err := NormalizeLabel(&labels[i]); err != nil {
+			return 0, err
+		}
+		labels[i].UpdatedAt = now
+		operations = append(operations, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": labels[i].Address}).
+			SetReplacement(labels[i]).
+			SetUpsert(true))
+	}
+
+	result, err := configs.AddressLabelsCollection.BulkWrite(ctx, operations)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store labels: %v", err)
+	}
+	return result.UpsertedCount + result.MatchedCount, nil
+}
+
+// DeleteLabel removes the label of an address, reporting whether one existed
+func DeleteLabel(address string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := configs.AddressLabelsCollection.DeleteOne(ctx, bson.M{"_id": normalizeAddress(address)})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete label: %v", err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// GetLabels returns a page of labels ordered by name, optionally filtered by
+// category. Private labels are only included when includePrivate is set.
+func GetLabels(page, limit int, category string, includePrivate bool) ([]models.AddressLabel, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if category != "" {
+		filter["category"] = category
+	}
+	if !includePrivate {
+		filter["public"] = true
+	}
+
+	total, err := configs.AddressLabelsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count labels: %v", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(page * limit)).
+		SetLimit(int64(limit))
+	cursor, err := configs.AddressLabelsCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query labels: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	labels := make([]models.AddressLabel, 0)
+	if err := cursor.All(ctx, &labels); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode labels: %v", err)
+	}
+	return labels, total, nil
+}
+
+// GetPublicLabels returns the public labels of the given addresses, keyed by
+// the Z-prefix lowercase address. Lookup failures yield an empty map so
+// responses never fail because of labels.
+func GetPublicLabels(addresses ...string) map[string]models.AddressLabel {
+	labels := make(map[string]models.AddressLabel)
+
+	seen := make(map[string]bool, len(addresses))
+	keys := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		key := normalizeAddress(address)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return labels
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := configs.AddressLabelsCollection.Find(ctx, bson.M{"_id": bson.M{"$in": keys}, "public": true})
+	if err != nil {
+		fmt.Printf("Error querying address labels: %v\n", err)
+		return labels
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var label models.AddressLabel
+		if err := cursor.Decode(&label); err == nil {
+			labels[label.Address] = label
+		}
+	}
+	return labels
+}
+
+// ParseLabels reads labels from a JSON array or from CSV with the header
+// address,name,category,source,public. Labels without a source get the given one.
+func ParseLabels(r io.Reader, format string, source string) ([]models.AddressLabel, error) {
+	var labels []models.AddressLabel
+
+	switch format {
+	case LabelFormatJSON:
+		if err := json.NewDecoder(r).Decode(&labels); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON labels: %v", err)
+		}
+
+	case LabelFormatCSV:
+		reader := csv.NewReader(r)
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV labels: %v", err)
+		}
+		if len(rows) == 0 {
+			return nil, nil
+		}
+
+		columns := make(map[string]int)
+		for i, name := range rows[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		for _, required := range []string{"address", "name", "category"} {
+			if _, ok := columns[required]; !ok {
+				return nil, fmt.Errorf("CSV labels are missing the %s column", required)
+			}
+		}
+		field := func(row []string, name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+
+		for line, row := range rows[1:] {
+			label := models.AddressLabel{
+				Address:  field(row, "address"),
+				Name:     field(row, "name"),
+				Category: field(row, "category"),
+				Source:   field(row, "source"),
+			}
+			if public := strings.TrimSpace(field(row, "public")); public != "" {
+				label.Public, err = strconv.ParseBool(public)
+				if err != nil {
+					return nil, fmt.Errorf("invalid public flag on CSV line %d: %v", line+2, err)
+				}
+			}
+			labels = append(labels, label)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported label format %q", format)
+	}
+
+	for i := range labels {
+		if labels[i].Source == "" {
+			labels[i].Source = source
+		}
+	}
+	return labels, nil
+}
+
+// ImportLabelsFile stores the labels of a local .json or .csv file, using the
+// file name as their default source
+func ImportLabelsFile(path string) (int64, error) {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open labels file: %v", err)
+	}
+	defer file.Close()
+
+	labels, err := ParseLabels(file, format, filepath.Base(path))
+	if err != nil {
+		return 0, err
+	}
+	return UpsertLabels(labels)
+}
+
+// GetTransactionLabels returns the public labels of the senders and recipients of txs
+func GetTransactionLabels(txs []models.TransactionByAddress) map[string]models.AddressLabel {
+	addresses := make([]string, 0, 2*len(txs))
+	for _, tx := range txs {
+		addresses = append(addresses, tx.From, tx.To)
+	}
+	return GetPublicLabels(addresses...)
+}
@@ -0,0 +1,302 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Beacon chain parameters governing the activation and exit queues
+const (
+	MinPerEpochChurnLimit = 4
+	ChurnLimitQuotient    = 65536
+	MaxSeedLookahead      = 4
+)
+
+// churnLimit returns the number of validators that can be activated or exited per epoch
+func churnLimit(activeCount int64) int64 {
+	churn := activeCount / ChurnLimitQuotient
+	if churn < MinPerEpochChurnLimit {
+		return MinPerEpochChurnLimit
+	}
+	return churn
+}
+
+// activationExitEpoch is the earliest epoch an activation or exit processed at epoch can take effect
+func activationExitEpoch(epoch int64) int64 {
+	return epoch + 1 + MaxSeedLookahead
+}
+
+type queuedValidator struct {
+	ID                         int64  `bson:"_id"`
+	PublicKeyHex               string `bson:"publicKeyHex"`
+	ActivationEligibilityEpoch string `bson:"activationEligibilityEpoch"`
+	ActivationEpoch            string `bson:"activationEpoch"`
+	ExitEpoch                  string `bson:"exitEpoch"`
+}
+
+// validatorQueueCache holds the queue built for the current head epoch. The
+// queue only changes at epoch transitions, so it is rebuilt once per epoch.
+var validatorQueueCache struct {
+	mu    sync.Mutex
+	epoch int64
+	queue *models.ValidatorQueueResponse
+}
+
+// GetValidatorQueue estimates when each pending validator will be activated
+// and when each exiting validator will leave. Validators whose epoch is
+// already fixed by the chain are reported as scheduled; the rest are placed
+// in order of eligibility and index behind them, filling each epoch up to the
+// churn limit.
+func GetValidatorQueue() (*models.ValidatorQueueResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var epochInfo models.EpochInfo
+	if err := configs.EpochInfoCollection.FindOne(ctx, bson.M{"_id": "current"}).Decode(&epochInfo); err != nil {
+		return nil, fmt.Errorf("failed to get epoch info: %v", err)
+	}
+	currentEpoch := parseEpoch(epochInfo.HeadEpoch)
+
+	validatorQueueCache.mu.Lock()
+	defer validatorQueueCache.mu.Unlock()
+	if validatorQueueCache.queue != nil && validatorQueueCache.epoch == currentEpoch {
+		return validatorQueueCache.queue, nil
+	}
+
+	queue, err := buildValidatorQueue(ctx, currentEpoch, parseEpoch(epochInfo.FinalizedEpoch))
+	if err != nil {
+		return nil, err
+	}
+	validatorQueueCache.epoch = currentEpoch
+	validatorQueueCache.queue = queue
+	return queue, nil
+}
+
+// buildValidatorQueue reads the pending and exiting validators and estimates their epochs
+func buildValidatorQueue(ctx context.Context, currentEpoch, finalizedEpoch int64) (*models.ValidatorQueueResponse, error) {
+
+	activeCount, err := configs.ValidatorsCollections.CountDocuments(ctx, bson.M{"status": "active"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active validators: %v", err)
+	}
+	churn := churnLimit(activeCount)
+
+	genesis, err := getGenesisTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	epochTime := func(epoch int64) int64 {
+		return genesis + epoch*SlotsPerEpoch*SecondsPerSlot
+	}
+
+	projection := options.Find().SetProjection(bson.M{
+		"publicKeyHex":               1,
+		"activationEligibilityEpoch": 1,
+		"activationEpoch":            1,
+		"exitEpoch":                  1,
+	})
+
+	// Activation queue
+	cursor, err := configs.ValidatorsCollections.Find(ctx, bson.M{"status": "pending"}, projection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending validators: %v", err)
+	}
+	var pending []queuedValidator
+	if err := cursor.All(ctx, &pending); err != nil {
+		return nil, fmt.Errorf("failed to decode pending validators: %v", err)
+	}
+
+	var scheduled, waiting []queuedValidator
+	for _, v := range pending {
+		if v.ActivationEpoch == FAR_FUTURE_EPOCH {
+			waiting = append(waiting, v)
+		} else if parseEpoch(v.ActivationEpoch) > currentEpoch {
+			scheduled = append(scheduled, v)
+		}
+	}
+	sort.Slice(scheduled, func(i, j int) bool {
+		ei, ej := parseEpoch(scheduled[i].ActivationEpoch), parseEpoch(scheduled[j].ActivationEpoch)
+		if ei != ej {
+			return ei < ej
+		}
+		return scheduled[i].ID < scheduled[j].ID
+	})
+	sort.Slice(waiting, func(i, j int) bool {
+		ei, ej := parseEpoch(waiting[i].ActivationEligibilityEpoch), parseEpoch(waiting[j].ActivationEligibilityEpoch)
+		if ei != ej {
+			return ei < ej
+		}
+		return waiting[i].ID < waiting[j].ID
+	})
+
+	// The next epoch transition assigns activationExitEpoch(currentEpoch).
+	// Validators already assigned to that epoch or a later one use up its churn,
+	// so waiting validators queue behind the highest assigned epoch.
+	nextEpoch := activationExitEpoch(currentEpoch)
+	var usedChurn int64
+	if len(scheduled) > 0 {
+		if last := parseEpoch(scheduled[len(scheduled)-1].ActivationEpoch); last >= nextEpoch {
+			nextEpoch = last
+			for _, v := range scheduled {
+				if parseEpoch(v.ActivationEpoch) == last {
+					usedChurn++
+				}
+			}
+		}
+	}
+
+	// A validator is only dequeued once its eligibility epoch is finalized
+	finalityLag := currentEpoch - finalizedEpoch
+	if finalityLag < 0 {
+		finalityLag = 0
+	}
+	estimateActivation := func(position int64, eligibility int64) int64 {
+		epoch := nextEpoch + (usedChurn+position)/churn
+		if eligibility != math.MaxInt64 {
+			if earliest := activationExitEpoch(eligibility + finalityLag); earliest > epoch {
+				epoch = earliest
+			}
+		}
+		return epoch
+	}
+
+	response := &models.ValidatorQueueResponse{
+		CurrentEpoch:    currentEpoch,
+		FinalizedEpoch:  finalizedEpoch,
+		ActiveCount:     activeCount,
+		ChurnLimit:      churn,
+		ActivationQueue: make([]models.QueueEntry, 0, len(scheduled)+len(waiting)),
+		ExitQueue:       make([]models.QueueEntry, 0),
+	}
+
+	for _, v := range scheduled {
+		epoch := parseEpoch(v.ActivationEpoch)
+		eligibility := parseEpoch(v.ActivationEligibilityEpoch)
+		response.ActivationQueue = append(response.ActivationQueue, models.QueueEntry{
+			ValidatorIndex:   v.ID,
+			PublicKeyHex:     v.PublicKeyHex,
+			Position:         int64(len(response.ActivationQueue) + 1),
+			EligibilityEpoch: &eligibility,
+			EstimatedEpoch:   epoch,
+			EstimatedTime:    epochTime(epoch),
+			Scheduled:        true,
+		})
+	}
+	for i, v := range waiting {
+		entry := models.QueueEntry{
+			ValidatorIndex: v.ID,
+			PublicKeyHex:   v.PublicKeyHex,
+			Position:       int64(len(response.ActivationQueue) + 1),
+		}
+		eligibility := parseEpoch(v.ActivationEligibilityEpoch)
+		if eligibility != math.MaxInt64 {
+			entry.EligibilityEpoch = &eligibility
+		}
+		entry.EstimatedEpoch = estimateActivation(int64(i), eligibility)
+		entry.EstimatedTime = epochTime(entry.EstimatedEpoch)
+		response.ActivationQueue = append(response.ActivationQueue, entry)
+	}
+	response.ActivationQueueSize = int64(len(response.ActivationQueue))
+	response.NextActivationEpoch = estimateActivation(int64(len(waiting)), math.MaxInt64)
+	response.NextActivationTime = epochTime(response.NextActivationEpoch)
+
+	// Exit queue: exit epochs are fixed when the exit is initiated
+	cursor, err = configs.ValidatorsCollections.Find(ctx, bson.M{
+		"status":    bson.M{"$in": []string{"active", "slashed"}},
+		"exitEpoch": bson.M{"$ne": FAR_FUTURE_EPOCH},
+	}, projection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exiting validators: %v", err)
+	}
+	var exiting []queuedValidator
+	if err := cursor.All(ctx, &exiting); err != nil {
+		return nil, fmt.Errorf("failed to decode exiting validators: %v", err)
+	}
+
+	exitCounts := make(map[int64]int64)
+	var queued []queuedValidator
+	for _, v := range exiting {
+		if exit := parseEpoch(v.ExitEpoch); exit > currentEpoch {
+			queued = append(queued, v)
+			exitCounts[exit]++
+		}
+	}
+	sort.Slice(queued, func(i, j int) bool {
+		ei, ej := parseEpoch(queued[i].ExitEpoch), parseEpoch(queued[j].ExitEpoch)
+		if ei != ej {
+			return ei < ej
+		}
+		return queued[i].ID < queued[j].ID
+	})
+	for i, v := range queued {
+		epoch := parseEpoch(v.ExitEpoch)
+		response.ExitQueue = append(response.ExitQueue, models.QueueEntry{
+			ValidatorIndex: v.ID,
+			PublicKeyHex:   v.PublicKeyHex,
+			Position:       int64(i + 1),
+			EstimatedEpoch: epoch,
+			EstimatedTime:  epochTime(epoch),
+			Scheduled:      true,
+		})
+	}
+	response.ExitQueueSize = int64(len(response.ExitQueue))
+
+	// A new exit joins the latest exit epoch unless it is already full
+	nextExit := activationExitEpoch(currentEpoch)
+	if len(queued) > 0 {
+		if last := parseEpoch(queued[len(queued)-1].ExitEpoch); last > nextExit {
+			nextExit = last
+		}
+	}
+	if exitCounts[nextExit] >= churn {
+		nextExit++
+	}
+	response.NextExitEpoch = nextExit
+	response.NextExitTime = epochTime(nextExit)
+
+	return response, nil
+}
+
+// getQueueEntry returns the activation or exit queue estimate of a validator, or nil if it is not queued
+func getQueueEntry(index int64) (*models.QueueEntry, error) {
+	queue, err := GetValidatorQueue()
+	if err != nil {
+		return nil, err
+	}
+	for _, entries := range [][]models.QueueEntry{queue.ActivationQueue, queue.ExitQueue} {
+		for i := range entries {
+			if entries[i].ValidatorIndex == index {
+				// The queue is shared between requests, so hand out a copy
+				entry := entries[i]
+				return &entry, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// getGenesisTime derives the beacon chain genesis time from a stored slot,
+// falling back to an estimate from the current time and head slot
+func getGenesisTime(ctx context.Context) (int64, error) {
+	var slot models.SlotRecord
+	err := configs.SlotsCollection.FindOne(ctx, bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})).Decode(&slot)
+	if err == nil && slot.Timestamp > 0 {
+		return slot.Timestamp - slot.Slot*SecondsPerSlot, nil
+	}
+
+	var epochInfo models.EpochInfo
+	if err := configs.EpochInfoCollection.FindOne(ctx, bson.M{"_id": "current"}).Decode(&epochInfo); err != nil {
+		return 0, fmt.Errorf("failed to get epoch info: %v", err)
+	}
+	return time.Now().Unix() - parseEpoch(epochInfo.HeadSlot)*SecondsPerSlot, nil
+}
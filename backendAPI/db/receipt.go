@@ -0,0 +1,52 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetReceipt returns the stored receipt of a transaction, or nil if the
+// transaction has not been indexed with its receipt
+func GetReceipt(txHash string) (*models.Receipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var receipt models.Receipt
+	err := configs.ReceiptsCollection.FindOne(ctx, bson.M{"_id": strings.ToLower(txHash)}).Decode(&receipt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query receipt: %v", err)
+	}
+	return &receipt, nil
+}
+
+// GetRevertReason returns the revert reason stored for a failed transaction,
+// or nil if the transaction succeeded or no reason could be determined
+func GetRevertReason(txHash string) (*models.RevertReason, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var doc struct {
+		RevertReason *models.RevertReason `bson:"revertReason"`
+	}
+	err := configs.TransferCollections.FindOne(ctx,
+		bson.M{"txHash": strings.ToLower(txHash), "revertReason": bson.M{"$exists": true}},
+		options.FindOne().SetProjection(bson.M{"revertReason": 1})).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query revert reason: %v", err)
+	}
+	return doc.RevertReason, nil
+}
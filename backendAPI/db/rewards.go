@@ -0,0 +1,141 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EpochsPerYear is the number of epochs in an average year, used to annualise returns
+const EpochsPerYear = 365.25 * 24 * 60 * 60 / (SlotsPerEpoch * SecondsPerSlot)
+
+// annualisedAPR converts a net reward over a number of epochs into a yearly percentage
+func annualisedAPR(netRewards int64, effectiveBalance float64, epochs int64) float64 {
+	if effectiveBalance <= 0 || epochs <= 0 {
+		return 0
+	}
+	return float64(netRewards) / effectiveBalance * (EpochsPerYear / float64(epochs)) * 100
+}
+
+// GetValidatorRewards returns the latest balance snapshots of a validator with
+// rewards, penalties and APR computed over them
+func GetValidatorRewards(id string, limit int) (*models.ValidatorRewardsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var validator models.ValidatorRecord
+	err := configs.ValidatorsCollections.FindOne(ctx, validatorFilter(id),
+		options.FindOne().SetProjection(bson.M{"index": 1})).Decode(&validator)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("validator not found")
+		}
+		return nil, fmt.Errorf("failed to get validator: %v", err)
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "epoch", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := configs.ValidatorBalancesCollection.Find(ctx, bson.M{"validatorIndex": validator.ID}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator balances: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	snapshots := make([]models.ValidatorBalanceSnapshot, 0)
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to decode validator balances: %v", err)
+	}
+
+	response := &models.ValidatorRewardsResponse{
+		Index:     validator.Index,
+		Snapshots: snapshots,
+	}
+
+	// Only snapshots with a predecessor carry a balance change
+	var effectiveSum float64
+	var counted int64
+	for _, s := range snapshots {
+		if s.PreviousEpoch == nil {
+			continue
+		}
+		if response.ToEpoch == 0 {
+			response.ToEpoch = s.Epoch
+		}
+		response.FromEpoch = *s.PreviousEpoch
+		response.Rewards += s.Reward
+		response.Penalties += s.Penalty
+		response.Withdrawn += s.Withdrawn
+		if effective, err := strconv.ParseFloat(s.EffectiveBalance, 64); err == nil {
+			effectiveSum += effective
+			counted++
+		}
+	}
+	response.NetRewards = response.Rewards - response.Penalties
+
+	if counted > 0 {
+		response.APR = annualisedAPR(response.NetRewards, effectiveSum/float64(counted), response.ToEpoch-response.FromEpoch)
+	}
+
+	return response, nil
+}
+
+// GetNetworkAPR returns network-wide rewards and APR over the latest balance snapshots
+func GetNetworkAPR(limit int) (*models.NetworkAPRResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "epoch", Value: -1}})
+	if limit > 0 {
+		findOptions.SetLimit(int64(limit))
+	}
+
+	cursor, err := configs.NetworkRewardsCollection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network rewards: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	history := make([]models.NetworkRewardsRecord, 0)
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode network rewards: %v", err)
+	}
+
+	response := &models.NetworkAPRResponse{History: history}
+
+	var effectiveSum float64
+	var counted int64
+	for i := range history {
+		r := &history[i]
+		if r.PreviousEpoch == nil {
+			continue
+		}
+		effective, _ := strconv.ParseFloat(r.TotalEffectiveBalance, 64)
+		r.APR = annualisedAPR(r.Rewards-r.Penalties, effective, r.Epoch-*r.PreviousEpoch)
+
+		if response.ToEpoch == 0 {
+			response.ToEpoch = r.Epoch
+		}
+		response.FromEpoch = *r.PreviousEpoch
+		response.Rewards += r.Rewards
+		response.Penalties += r.Penalties
+		effectiveSum += effective
+		counted++
+	}
+	response.NetRewards = response.Rewards - response.Penalties
+
+	if counted > 0 {
+		response.APR = annualisedAPR(response.NetRewards, effectiveSum/float64(counted), response.ToEpoch-response.FromEpoch)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,677 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JSON-RPC error codes returned by the /rpc proxy
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+	RPCLimitExceeded  = -32005
+)
+
+const (
+	// rpcMaxLogBlocks is the widest zond_getLogs block range, whether answered
+	// from the database or forwarded to the node
+	rpcMaxLogBlocks = 1000
+	// rpcMaxResponseBytes caps the size of a node response
+	rpcMaxResponseBytes = 32 << 20
+
+	rpcCacheTTL     = 10 * time.Minute
+	rpcCacheSize    = 10000
+	rpcHeadInterval = 5 * time.Second
+)
+
+// rpcCachePolicy decides when a forwarded response is final and can be cached
+type rpcCachePolicy int
+
+const (
+	rpcNoCache       rpcCachePolicy = iota
+	rpcCacheAlways                  // Constant for the chain, e.g. zond_chainId
+	rpcCacheAtBlock                 // State read at the block number in blockParam
+	rpcCacheByResult                // Lookups whose result carries its block number
+	rpcCacheLogRange                // zond_getLogs over a numeric block range
+)
+
+// rpcMethod is a method the /rpc proxy answers. fromDB answers it from the
+// database when the requested data is final and fully stored; otherwise the
+// call is forwarded to the node.
+type rpcMethod struct {
+	cache      rpcCachePolicy
+	blockParam int
+	// check rejects params the proxy does not pass on, given the synced head
+	check  func(params []json.RawMessage, head int64) *models.RPCError
+	fromDB func(ctx context.Context, params []json.RawMessage, finalized int64) (json.RawMessage, bool, error)
+}
+
+// rpcMethods is the whitelist of read-only methods. Stored blocks and
+// transactions do not keep every field the node returns, so they are
+// forwarded and cached once final; receipts and logs are stored in full.
+var rpcMethods = map[string]rpcMethod{
+	"zond_blockNumber":                         {},
+	"zond_syncing":                             {},
+	"zond_gasPrice":                            {},
+	"zond_maxPriorityFeePerGas":                {},
+	"zond_feeHistory":                          {},
+	"zond_estimateGas":                         {},
+	"zond_chainId":                             {cache: rpcCacheAlways},
+	"zond_getBalance":                          {cache: rpcCacheAtBlock, blockParam: 1},
+	"zond_getCode":                             {cache: rpcCacheAtBlock, blockParam: 1},
+	"zond_getTransactionCount":                 {cache: rpcCacheAtBlock, blockParam: 1},
+	"zond_getStorageAt":                        {cache: rpcCacheAtBlock, blockParam: 2},
+	"zond_call":                                {cache: rpcCacheAtBlock, blockParam: 1},
+	"zond_getBlockByNumber":                    {cache: rpcCacheByResult},
+	"zond_getBlockByHash":                      {cache: rpcCacheByResult},
+	"zond_getTransactionByHash":                {cache: rpcCacheByResult},
+	"zond_getTransactionByBlockHashAndIndex":   {cache: rpcCacheByResult},
+	"zond_getTransactionByBlockNumberAndIndex": {cache: rpcCacheByResult},
+	"zond_getTransactionReceipt":               {cache: rpcCacheByResult, fromDB: rpcReceiptFromDB},
+	"zond_getBlockTransactionCountByNumber":    {cache: rpcCacheAtBlock, blockParam: 0, fromDB: rpcTransactionCountFromDB},
+	"zond_getBlockTransactionCountByHash":      {fromDB: rpcTransactionCountFromDB},
+	"zond_getLogs":                             {cache: rpcCacheLogRange, check: rpcCheckLogRange, fromDB: rpcLogsFromDB},
+}
+
+// CallRPC answers a whitelisted read-only JSON-RPC call from the cache, the
+// database or the node. Errors meant for the caller, including node errors,
+// are returned as an RPCError; err is set when the call could not be made.
+func CallRPC(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, *models.RPCError, error) {
+	m, ok := rpcMethods[method]
+	if !ok {
+		return nil, &models.RPCError{Code: RPCMethodNotFound, Message: fmt.Sprintf("the method %s is not available", method)}, nil
+	}
+	args, err := rpcParams(params)
+	if err != nil {
+		return nil, &models.RPCError{Code: RPCInvalidParams, Message: err.Error()}, nil
+	}
+
+	head, finalized := rpcChainPosition()
+	if m.check != nil {
+		if rpcErr := m.check(args, head); rpcErr != nil {
+			return nil, rpcErr, nil
+		}
+	}
+
+	key := rpcCacheKey(method, params)
+	if result, ok := rpcResponses.get(key); ok {
+		return result, nil, nil
+	}
+
+	if m.fromDB != nil && finalized >= 0 {
+		result, ok, err := m.fromDB(ctx, args, finalized)
+		if err != nil {
+			// The node can still answer
+			log.Printf("Failed to answer %s from the database: %v", method, err)
+		} else if ok {
+			rpcResponses.put(key, result)
+			return result, nil, nil
+		}
+	}
+
+	result, rpcErr, err := forwardRPC(ctx, method, params)
+	if err != nil || rpcErr != nil {
+		return nil, rpcErr, err
+	}
+	if m.isFinal(args, result, finalized) {
+		rpcResponses.put(key, result)
+	}
+	return result, nil, nil
+}
+
+// isFinal reports whether a forwarded result can no longer change
+func (m rpcMethod) isFinal(args []json.RawMessage, result json.RawMessage, finalized int64) bool {
+	if m.cache == rpcCacheAlways {
+		return true
+	}
+	if finalized < 0 || string(result) == "null" {
+		return false
+	}
+
+	switch m.cache {
+	case rpcCacheAtBlock:
+		if m.blockParam >= len(args) {
+			return false
+		}
+		number, ok := rpcBlockNumber(args[m.blockParam])
+		return ok && number <= finalized
+	case rpcCacheByResult:
+		var position struct {
+			Number      string `json:"number"`
+			BlockNumber string `json:"blockNumber"`
+		}
+		if err := json.Unmarshal(result, &position); err != nil {
+			return false
+		}
+		if position.Number == "" {
+			position.Number = position.BlockNumber
+		}
+		number, ok := parseRPCQuantity(position.Number)
+		return ok && number <= finalized
+	case rpcCacheLogRange:
+		filter, ok := rpcParseLogFilter(args)
+		if !ok || filter.BlockHash != "" {
+			return false
+		}
+		from, fromOK := rpcBlockNumber(filter.FromBlock)
+		to, toOK := rpcBlockNumber(filter.ToBlock)
+		return fromOK && toOK && from <= to && to <= finalized
+	}
+	return false
+}
+
+// rpcReceiptFromDB answers zond_getTransactionReceipt from the stored receipt
+func rpcReceiptFromDB(ctx context.Context, params []json.RawMessage, finalized int64) (json.RawMessage, bool, error) {
+	var hash string
+	if len(params) < 1 || json.Unmarshal(params[0], &hash) != nil {
+		return nil, false, nil
+	}
+
+	var receipt models.Receipt
+	err := configs.ReceiptsCollection.FindOne(ctx, bson.M{"_id": strings.ToLower(hash)}).Decode(&receipt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get receipt: %v", err)
+	}
+	if number, ok := parseRPCQuantity(receipt.BlockNumber); !ok || number > finalized {
+		return nil, false, nil
+	}
+
+	// The stored block timestamp is not part of a node receipt
+	result, err := json.Marshal(struct {
+		models.Receipt
+		Timestamp string `json:"timestamp,omitempty"`
+	}{Receipt: receipt})
+	return result, err == nil, err
+}
+
+// rpcTransactionCountFromDB answers zond_getBlockTransactionCountByNumber and
+// zond_getBlockTransactionCountByHash from the stored block
+func rpcTransactionCountFromDB(ctx context.Context, params []json.RawMessage, finalized int64) (json.RawMessage, bool, error) {
+	if len(params) < 1 {
+		return nil, false, nil
+	}
+	filter := bson.M{}
+	if number, ok := rpcBlockNumber(params[0]); ok {
+		filter["blockNum"] = number
+	} else {
+		var hash string
+		if json.Unmarshal(params[0], &hash) != nil || !strings.HasPrefix(hash, "0x") {
+			return nil, false, nil
+		}
+		filter["result.hash"] = strings.ToLower(hash)
+	}
+
+	var block struct {
+		BlockNum int64 `bson:"blockNum"`
+		Result   struct {
+			Transactions []struct {
+				Hash string `bson:"hash"`
+			} `bson:"transactions"`
+		} `bson:"result"`
+	}
+	err := configs.BlocksCollection.FindOne(ctx, filter,
+		options.FindOne().SetProjection(bson.M{"blockNum": 1, "result.transactions.hash": 1})).Decode(&block)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get block: %v", err)
+	}
+	if block.BlockNum > finalized {
+		return nil, false, nil
+	}
+	return json.RawMessage(strconv.Quote(fmt.Sprintf("0x%x", len(block.Result.Transactions)))), true, nil
+}
+
+// rpcLogFilter is the filter object of zond_getLogs
+type rpcLogFilter struct {
+	FromBlock json.RawMessage   `json:"fromBlock"`
+	ToBlock   json.RawMessage   `json:"toBlock"`
+	BlockHash string            `json:"blockHash"`
+	Address   json.RawMessage   `json:"address"`
+	Topics    []json.RawMessage `json:"topics"`
+}
+
+// rpcLogsFromDB answers zond_getLogs from stored receipts for final block
+// ranges of up to rpcMaxLogBlocks blocks. The range is only answered when
+// every block and every receipt in it is stored.
+func rpcLogsFromDB(ctx context.Context, params []json.RawMessage, finalized int64) (json.RawMessage, bool, error) {
+	filter, ok := rpcParseLogFilter(params)
+	if !ok {
+		return nil, false, nil
+	}
+	addresses, ok := rpcStringSet(filter.Address)
+	if !ok {
+		return nil, false, nil
+	}
+	topics := make([]map[string]bool, len(filter.Topics))
+	for i, topic := range filter.Topics {
+		if topics[i], ok = rpcStringSet(topic); !ok {
+			return nil, false, nil
+		}
+	}
+
+	var from, to int64
+	if filter.BlockHash != "" {
+		var block struct {
+			BlockNum int64 `bson:"blockNum"`
+		}
+		err := configs.BlocksCollection.FindOne(ctx, bson.M{"result.hash": strings.ToLower(filter.BlockHash)},
+			options.FindOne().SetProjection(bson.M{"blockNum": 1})).Decode(&block)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, false, nil
+			}
+			return nil, false, fmt.Errorf("failed to get block: %v", err)
+		}
+		from, to = block.BlockNum, block.BlockNum
+	} else {
+		var fromOK, toOK bool
+		from, fromOK = rpcBlockNumber(filter.FromBlock)
+		to, toOK = rpcBlockNumber(filter.ToBlock)
+		if !fromOK || !toOK || from > to || to-from >= rpcMaxLogBlocks {
+			return nil, false, nil
+		}
+	}
+	if to > finalized {
+		return nil, false, nil
+	}
+
+	// Only answer ranges the syncer stored completely
+	cursor, err := configs.BlocksCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"blockNum": bson.M{"$gte": from, "$lte": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          nil,
+			"blocks":       bson.M{"$sum": 1},
+			"transactions": bson.M{"$sum": bson.M{"$size": bson.M{"$ifNull": bson.A{"$result.transactions", bson.A{}}}}},
+		}}},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to count blocks: %v", err)
+	}
+	var counts []struct {
+		Blocks       int64 `bson:"blocks"`
+		Transactions int64 `bson:"transactions"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, false, fmt.Errorf("failed to decode block counts: %v", err)
+	}
+	if len(counts) != 1 || counts[0].Blocks != to-from+1 {
+		return nil, false, nil
+	}
+
+	numbers := make(bson.A, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		numbers = append(numbers, fmt.Sprintf("0x%x", number))
+	}
+	receiptCursor, err := configs.ReceiptsCollection.Find(ctx, bson.M{"blockNumber": bson.M{"$in": numbers}},
+		options.Find().SetProjection(bson.M{"logs": 1}))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query receipts: %v", err)
+	}
+	var receipts []models.Receipt
+	if err := receiptCursor.All(ctx, &receipts); err != nil {
+		return nil, false, fmt.Errorf("failed to decode receipts: %v", err)
+	}
+	if int64(len(receipts)) != counts[0].Transactions {
+		return nil, false, nil
+	}
+
+	logs := make([]models.ReceiptLog, 0)
+	for _, receipt := range receipts {
+		for _, entry := range receipt.Logs {
+			if rpcLogMatches(entry, addresses, topics) {
+				logs = append(logs, entry)
+			}
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		bi, _ := parseRPCQuantity(logs[i].BlockNumber)
+		bj, _ := parseRPCQuantity(logs[j].BlockNumber)
+		if bi != bj {
+			return bi < bj
+		}
+		li, _ := parseRPCQuantity(logs[i].LogIndex)
+		lj, _ := parseRPCQuantity(logs[j].LogIndex)
+		return li < lj
+	})
+
+	result, err := json.Marshal(logs)
+	return result, err == nil, err
+}
+
+// rpcCheckLogRange rejects zond_getLogs calls spanning more than
+// rpcMaxLogBlocks blocks before they reach the node. Block tags and a missing
+// bound, which the node reads as "latest", are taken as the synced head.
+func rpcCheckLogRange(params []json.RawMessage, head int64) *models.RPCError {
+	filter, ok := rpcParseLogFilter(params)
+	if !ok || filter.BlockHash != "" {
+		// The node reports malformed filters; a block hash names one block
+		return nil
+	}
+	from, fromOK := rpcLogRangeBound(filter.FromBlock, head)
+	to, toOK := rpcLogRangeBound(filter.ToBlock, head)
+	if !fromOK || !toOK || from > to || to-from < rpcMaxLogBlocks {
+		return nil
+	}
+	return &models.RPCError{
+		Code:    RPCLimitExceeded,
+		Message: fmt.Sprintf("zond_getLogs is limited to a range of %d blocks", rpcMaxLogBlocks),
+	}
+}
+
+// rpcLogRangeBound reads a zond_getLogs range bound. Moving tags resolve to
+// head; they cannot be resolved before the first sync.
+func rpcLogRangeBound(raw json.RawMessage, head int64) (int64, bool) {
+	if number, ok := rpcBlockNumber(raw); ok {
+		return number, true
+	}
+	return head, head >= 0
+}
+
+// rpcLogMatches applies the address and topic filters of zond_getLogs. A nil
+// set matches anything.
+func rpcLogMatches(entry models.ReceiptLog, addresses map[string]bool, topics []map[string]bool) bool {
+	if addresses != nil && !addresses[strings.ToLower(entry.Address)] {
+		return false
+	}
+	for i, topic := range topics {
+		if topic == nil {
+			continue
+		}
+		if i >= len(entry.Topics) || !topic[strings.ToLower(entry.Topics[i])] {
+			return false
+		}
+	}
+	return true
+}
+
+// rpcParseLogFilter reads the filter object of zond_getLogs
+func rpcParseLogFilter(params []json.RawMessage) (rpcLogFilter, bool) {
+	var filter rpcLogFilter
+	if len(params) < 1 || json.Unmarshal(params[0], &filter) != nil {
+		return filter, false
+	}
+	return filter, true
+}
+
+// rpcStringSet reads a filter value that is null, a string or an array of
+// strings into a lowercase set. Null, an empty array or an array containing
+// null yields a nil set that matches anything.
+func rpcStringSet(raw json.RawMessage) (map[string]bool, bool) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, true
+	}
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return map[string]bool{strings.ToLower(single): true}, true
+	}
+	var values []*string
+	if json.Unmarshal(raw, &values) != nil {
+		return nil, false
+	}
+	if len(values) == 0 {
+		return nil, true
+	}
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		if value == nil {
+			return nil, true
+		}
+		set[strings.ToLower(*value)] = true
+	}
+	return set, true
+}
+
+// rpcBlockNumber reads a block parameter that names a fixed block: a hex
+// number, "earliest", or an object with a blockNumber. Tags such as "latest"
+// move with the chain and are not fixed.
+func rpcBlockNumber(raw json.RawMessage) (int64, bool) {
+	var tag string
+	if json.Unmarshal(raw, &tag) == nil {
+		if tag == "earliest" {
+			return 0, true
+		}
+		return parseRPCQuantity(tag)
+	}
+	var block struct {
+		BlockNumber string `json:"blockNumber"`
+	}
+	if json.Unmarshal(raw, &block) != nil {
+		return 0, false
+	}
+	return parseRPCQuantity(block.BlockNumber)
+}
+
+// parseRPCQuantity parses a hex quantity such as "0x1a"
+func parseRPCQuantity(value string) (int64, bool) {
+	if !strings.HasPrefix(value, "0x") || len(value) < 3 {
+		return 0, false
+	}
+	number, err := strconv.ParseInt(value[2:], 16, 64)
+	return number, err == nil
+}
+
+// rpcParams splits positional params. Missing params are an empty list.
+func rpcParams(params json.RawMessage) ([]json.RawMessage, error) {
+	if len(params) == 0 || string(params) == "null" {
+		return nil, nil
+	}
+	var args []json.RawMessage
+	if err := json.Unmarshal(params, &args); err != nil {
+		return nil, fmt.Errorf("params must be an array")
+	}
+	return args, nil
+}
+
+// forwardRPC sends a call to the node at NODE_URL
+func forwardRPC(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, *models.RPCError, error) {
+	if len(params) == 0 {
+		params = json.RawMessage("[]")
+	}
+	body, err := json.Marshal(models.RPCRequest{
+		Jsonrpc: "2.0",
+		ID:      json.RawMessage("1"),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	nodeURL := os.Getenv("NODE_URL")
+	if nodeURL == "" {
+		nodeURL = "http://127.0.0.1:8545" // fallback to default if not set
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, nodeURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create node request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := rpcNodeClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to call node: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response models.RPCResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, rpcMaxResponseBytes)).Decode(&response); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode node response (status %d): %v", resp.StatusCode, err)
+	}
+	if response.Error != nil {
+		return nil, response.Error, nil
+	}
+	if len(response.Result) == 0 {
+		return json.RawMessage("null"), nil, nil
+	}
+	return response.Result, nil, nil
+}
+
+var rpcNodeClient = &http.Client{Timeout: 30 * time.Second}
+
+// rpcChainPosition returns the synced head and the newest stored block the
+// beacon chain has finalized, each -1 until known. They are re-read at most
+// every rpcHeadInterval by one caller at a time, without holding the lock, so
+// other calls keep using the previous values meanwhile.
+func rpcChainPosition() (head, finalized int64) {
+	rpcHead.mu.Lock()
+	head, finalized = rpcHead.number, rpcHead.finalized
+	refresh := !rpcHead.refreshing && time.Since(rpcHead.fetched) > rpcHeadInterval
+	if refresh {
+		rpcHead.refreshing = true
+	}
+	rpcHead.mu.Unlock()
+	if !refresh {
+		return head, finalized
+	}
+
+	newHead, newFinalized, err := readChainPosition()
+
+	rpcHead.mu.Lock()
+	defer rpcHead.mu.Unlock()
+	rpcHead.refreshing = false
+	if err != nil {
+		// Try again on the next call
+		log.Printf("Failed to read the chain position: %v", err)
+		return head, finalized
+	}
+	rpcHead.number, rpcHead.finalized = newHead, newFinalized
+	rpcHead.fetched = time.Now()
+	return newHead, newFinalized
+}
+
+// readChainPosition reads the synced head and the execution block of the
+// newest proposed slot at or below the finalized slot in epoch_info. Only
+// blocks the syncer has stored count as final.
+func readChainPosition() (head, finalized int64, err error) {
+	latest, err := GetLatestBlockFromSyncState()
+	if err != nil {
+		return -1, -1, err
+	}
+	head, ok := parseRPCQuantity(latest)
+	if !ok {
+		return -1, -1, fmt.Errorf("invalid synced block number %q", latest)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var epochInfo models.EpochInfo
+	err = configs.EpochInfoCollection.FindOne(ctx, bson.M{"_id": "current"}).Decode(&epochInfo)
+	if err == mongo.ErrNoDocuments {
+		return head, -1, nil
+	}
+	if err != nil {
+		return -1, -1, fmt.Errorf("failed to get epoch info: %v", err)
+	}
+	finalizedSlot := parseEpoch(epochInfo.FinalizedSlot)
+	if epochInfo.FinalizedSlot == "" {
+		finalizedSlot = parseEpoch(epochInfo.FinalizedEpoch) * SlotsPerEpoch
+	}
+
+	var slot models.SlotRecord
+	err = configs.SlotsCollection.FindOne(ctx,
+		bson.M{"_id": bson.M{"$lte": finalizedSlot}, "status": "proposed", "executionBlockNumber": bson.M{"$exists": true}},
+		options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})).Decode(&slot)
+	if err == mongo.ErrNoDocuments {
+		return head, -1, nil
+	}
+	if err != nil {
+		return -1, -1, fmt.Errorf("failed to get finalized slot: %v", err)
+	}
+	finalized, err = strconv.ParseInt(slot.ExecutionBlockNumber, 10, 64)
+	if err != nil {
+		return -1, -1, fmt.Errorf("invalid execution block number %q in slot %d", slot.ExecutionBlockNumber, slot.Slot)
+	}
+	if finalized > head {
+		finalized = head
+	}
+	return head, finalized, nil
+}
+
+var rpcHead = struct {
+	mu         sync.Mutex
+	number     int64
+	finalized  int64
+	fetched    time.Time
+	refreshing bool
+}{number: -1, finalized: -1}
+
+// rpcCacheKey identifies a call by method and compacted params
+func rpcCacheKey(method string, params json.RawMessage) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, params); err != nil {
+		return method + ":" + string(params)
+	}
+	return method + ":" + compact.String()
+}
+
+// rpcCache holds responses about final data for rpcCacheTTL
+type rpcCache struct {
+	mu      sync.Mutex
+	entries map[string]rpcCacheEntry
+}
+
+type rpcCacheEntry struct {
+	result  json.RawMessage
+	expires time.Time
+}
+
+var rpcResponses = &rpcCache{entries: make(map[string]rpcCacheEntry)}
+
+func (c *rpcCache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (c *rpcCache) put(key string, result json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= rpcCacheSize {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		// Still full: evict arbitrary entries
+		for k := range c.entries {
+			if len(c.entries) < rpcCacheSize {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = rpcCacheEntry{result: result, expires: time.Now().Add(rpcCacheTTL)}
+}
@@ -0,0 +1,566 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// searchMinHexPrefix is the shortest hex prefix autocompleted against hashes, addresses and keys
+	searchMinHexPrefix = 6
+	// searchMinTextPrefix is the shortest text prefix autocompleted against token and label names
+	searchMinTextPrefix = 2
+	// searchMaxTime bounds every search query on the server
+	searchMaxTime = 2 * time.Second
+	// maxTimeMSExpired is the server error code of a query that ran past its time limit
+	maxTimeMSExpired = 50
+)
+
+// ErrSearchTimeout is returned when a search query exceeds its time limit
+var ErrSearchTimeout = errors.New("search query exceeded its time limit")
+
+// Result scores: exact identifiers rank above name matches, which rank above prefixes
+const (
+	searchScoreExact        = 100
+	searchScoreUnseen       = 90 // Well-formed address never seen on chain, validator index equal to a block number
+	searchScoreSymbol       = 80
+	searchScoreName         = 75
+	searchScoreLabel        = 70
+	searchScoreTokenPrefix  = 60
+	searchScoreLabelPrefix  = 55
+	searchScoreHexPrefix    = 40
+	searchScorePendingDelta = 5 // Pending transactions rank just below mined ones
+)
+
+var hexPattern = regexp.MustCompile("^[0-9a-f]+$")
+
+// caseInsensitive is the collation of the name and symbol indexes used for autocomplete
+var caseInsensitive = &options.Collation{Locale: "en", Strength: 2}
+
+// Search classifies a query as block number or hash, transaction hash, address,
+// validator index or public key, token name or symbol, or label, and returns
+// the typed matches ranked by score. Hex and text prefixes are autocompleted
+// through indexes: anchored regexes on the lowercase hash, address and key
+// fields, and case-insensitive collation ranges on token and label names.
+func Search(query string, limit int) ([]models.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := &searcher{ctx: ctx, limit: int64(limit), seen: make(map[string]int)}
+	plan := classifySearch(query)
+
+	if plan.number != nil {
+		s.blockByNumber(*plan.number)
+		s.validatorByIndex(*plan.number)
+	}
+	if plan.address != "" {
+		s.address(plan.address)
+	}
+	if plan.hash != "" {
+		s.blockByHash(plan.hash)
+		s.transaction(plan.hash)
+	}
+	if plan.validatorKey != "" {
+		s.validatorByKey(plan.validatorKey)
+	}
+	if plan.hashPrefix != "" {
+		s.hashPrefix(plan.hashPrefix)
+	}
+	if plan.addressPrefix != "" {
+		s.addressPrefix(plan.addressPrefix)
+	}
+	if plan.validatorKeyPrefix != "" {
+		s.validatorKeyPrefix(plan.validatorKeyPrefix)
+	}
+	if plan.text != "" {
+		s.tokens(plan.text)
+		s.labels(plan.text)
+	}
+
+	s.attachLabels()
+
+	sort.SliceStable(s.results, func(i, j int) bool {
+		if s.results[i].Score != s.results[j].Score {
+			return s.results[i].Score > s.results[j].Score
+		}
+		if len(s.results[i].Name) != len(s.results[j].Name) {
+			return len(s.results[i].Name) < len(s.results[j].Name)
+		}
+		return s.results[i].Value < s.results[j].Value
+	})
+	if len(s.results) > limit {
+		s.results = s.results[:limit]
+	}
+	if s.results == nil {
+		s.results = make([]models.SearchResult, 0)
+	}
+	return s.results, s.err
+}
+
+// searchPlan is the set of lookups a query is classified into. Empty fields
+// are not looked up.
+type searchPlan struct {
+	number             *uint64 // Block number and validator index
+	address            string  // Full address, lowercase hex without prefix
+	hash               string  // Full block or transaction hash, 0x-prefixed
+	validatorKey       string  // Full validator public key, lowercase hex
+	hashPrefix         string  // Block and transaction hash prefix, 0x-prefixed
+	addressPrefix      string  // Address prefix, lowercase hex without prefix
+	validatorKeyPrefix string  // Validator public key prefix, lowercase hex
+	text               string  // Token and label name prefix
+}
+
+// classifySearch decides which lookups a trimmed, non-empty query needs.
+// Decimal numbers are block numbers or validator indexes. Hex is a full
+// identifier by its length, or a prefix of one once it is long enough; a Z
+// prefix restricts it to addresses. Anything that is not a full identifier is
+// also matched against token and label names.
+func classifySearch(query string) searchPlan {
+	var plan searchPlan
+	lower := strings.ToLower(query)
+
+	if number, err := strconv.ParseUint(query, 10, 64); err == nil {
+		plan.number = &number
+	}
+
+	hex, prefix := lower, ""
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		hex, prefix = lower[2:], "0x"
+	case strings.HasPrefix(lower, "z"):
+		hex, prefix = lower[1:], "z"
+	}
+	isHex := hexPattern.MatchString(hex)
+	fullIdentifier := false
+
+	if isHex && prefix == "z" {
+		if len(hex) == 40 {
+			plan.address = hex
+			fullIdentifier = true
+		} else if len(hex) >= searchMinHexPrefix {
+			plan.addressPrefix = hex
+		}
+	} else if isHex && (prefix == "0x" || !isDecimal(query)) {
+		switch {
+		case len(hex) == 64:
+			plan.hash = "0x" + hex
+			fullIdentifier = true
+		case len(hex) == 40:
+			plan.address = hex
+			fullIdentifier = true
+		case len(hex) > 64:
+			plan.validatorKey = hex
+			fullIdentifier = true
+		case len(hex) >= searchMinHexPrefix:
+			plan.hashPrefix = "0x" + hex
+			plan.addressPrefix = hex
+			plan.validatorKeyPrefix = hex
+		}
+	}
+
+	if !fullIdentifier && len(query) >= searchMinTextPrefix {
+		plan.text = query
+	}
+	return plan
+}
+
+// searcher collects the results of the lookups run for one query
+type searcher struct {
+	ctx     context.Context
+	limit   int64
+	results []models.SearchResult
+	seen    map[string]int // Result position by type and value
+	err     error
+}
+
+// add records a result, keeping the best scoring one per entity
+func (s *searcher) add(result models.SearchResult) {
+	key := result.Type + ":" + result.Value
+	if i, ok := s.seen[key]; ok {
+		if result.Score > s.results[i].Score {
+			s.results[i] = result
+		}
+		return
+	}
+	s.seen[key] = len(s.results)
+	s.results = append(s.results, result)
+}
+
+// fail keeps the first lookup error; the remaining lookups still run
+func (s *searcher) fail(lookup string, err error) {
+	log.Printf("Search %s lookup failed: %v", lookup, err)
+	if s.err == nil {
+		if err = searchError(err); err == ErrSearchTimeout {
+			s.err = err
+		} else {
+			s.err = fmt.Errorf("failed to search %s: %v", lookup, err)
+		}
+	}
+}
+
+func (s *searcher) blockByNumber(number uint64) {
+	count, err := configs.BlocksCollection.CountDocuments(s.ctx,
+		bson.M{"result.number": fmt.Sprintf("0x%x", number)}, options.Count().SetLimit(1).SetMaxTime(searchMaxTime))
+	if err != nil {
+		s.fail("block", err)
+		return
+	}
+	if count > 0 {
+		s.add(models.SearchResult{
+			Type:  models.SearchTypeBlock,
+			Value: strconv.FormatUint(number, 10),
+			Match: models.SearchMatchExact,
+			Score: searchScoreExact,
+		})
+	}
+}
+
+func (s *searcher) validatorByIndex(index uint64) {
+	var validator models.ValidatorRecord
+	err := configs.ValidatorsCollections.FindOne(s.ctx, bson.M{"_id": int64(index)},
+		options.FindOne().SetMaxTime(searchMaxTime).SetProjection(bson.M{"publicKeyHex": 1})).Decode(&validator)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			s.fail("validator", err)
+		}
+		return
+	}
+	s.add(models.SearchResult{
+		Type:  models.SearchTypeValidator,
+		Value: strconv.FormatUint(index, 10),
+		Name:  validator.PublicKeyHex,
+		Match: models.SearchMatchExact,
+		Score: searchScoreUnseen,
+	})
+}
+
+func (s *searcher) validatorByKey(hex string) {
+	var validator models.ValidatorRecord
+	err := configs.ValidatorsCollections.FindOne(s.ctx, bson.M{"publicKeyHex": hex},
+		options.FindOne().SetMaxTime(searchMaxTime).SetProjection(bson.M{"publicKeyHex": 1})).Decode(&validator)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			s.fail("validator", err)
+		}
+		return
+	}
+	s.add(models.SearchResult{
+		Type:  models.SearchTypeValidator,
+		Value: strconv.FormatInt(validator.ID, 10),
+		Name:  validator.PublicKeyHex,
+		Match: models.SearchMatchExact,
+		Score: searchScoreExact,
+	})
+}
+
+func (s *searcher) validatorKeyPrefix(hex string) {
+	cursor, err := configs.ValidatorsCollections.Find(s.ctx,
+		bson.M{"publicKeyHex": bson.M{"$regex": "^" + hex}},
+		options.Find().SetMaxTime(searchMaxTime).SetProjection(bson.M{"publicKeyHex": 1}).SetLimit(s.limit))
+	if err != nil {
+		s.fail("validator", err)
+		return
+	}
+	var validators []models.ValidatorRecord
+	if err := cursor.All(s.ctx, &validators); err != nil {
+		s.fail("validator", err)
+		return
+	}
+	for _, validator := range validators {
+		s.add(models.SearchResult{
+			Type:  models.SearchTypeValidator,
+			Value: strconv.FormatInt(validator.ID, 10),
+			Name:  validator.PublicKeyHex,
+			Match: models.SearchMatchPrefix,
+			Score: searchScoreHexPrefix,
+		})
+	}
+}
+
+func (s *searcher) blockByHash(hash string) {
+	s.blocks(bson.M{"result.hash": hash}, models.SearchMatchExact, searchScoreExact)
+}
+
+func (s *searcher) blocks(filter bson.M, match string, score int) {
+	cursor, err := configs.BlocksCollection.Find(s.ctx, filter,
+		options.Find().SetMaxTime(searchMaxTime).SetProjection(bson.M{"result.number": 1, "result.hash": 1}).SetLimit(s.limit))
+	if err != nil {
+		s.fail("block", err)
+		return
+	}
+	var blocks []models.ZondUint64Version
+	if err := cursor.All(s.ctx, &blocks); err != nil {
+		s.fail("block", err)
+		return
+	}
+	for _, block := range blocks {
+		number, err := strconv.ParseUint(strings.TrimPrefix(block.Result.Number, "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		s.add(models.SearchResult{
+			Type:  models.SearchTypeBlock,
+			Value: strconv.FormatUint(number, 10),
+			Name:  block.Result.Hash,
+			Match: match,
+			Score: score,
+		})
+	}
+}
+
+// transaction looks a hash up among stored receipts, address transactions and the mempool
+func (s *searcher) transaction(hash string) {
+	count, err := configs.ReceiptsCollection.CountDocuments(s.ctx, bson.M{"_id": hash}, options.Count().SetLimit(1).SetMaxTime(searchMaxTime))
+	if err != nil {
+		s.fail("transaction", err)
+		return
+	}
+	if count == 0 {
+		count, err = configs.TransactionByAddressCollection.CountDocuments(s.ctx, bson.M{"txHash": hash}, options.Count().SetLimit(1).SetMaxTime(searchMaxTime))
+		if err != nil {
+			s.fail("transaction", err)
+			return
+		}
+	}
+	if count > 0 {
+		s.add(models.SearchResult{
+			Type:  models.SearchTypeTransaction,
+			Value: hash,
+			Match: models.SearchMatchExact,
+			Score: searchScoreExact,
+		})
+		return
+	}
+
+	count, err = configs.GetCollection(configs.DB, PENDING_COLLECTION).CountDocuments(s.ctx, bson.M{"_id": hash}, options.Count().SetLimit(1).SetMaxTime(searchMaxTime))
+	if err != nil {
+		s.fail("pending transaction", err)
+		return
+	}
+	if count > 0 {
+		s.add(models.SearchResult{
+			Type:  models.SearchTypePendingTransaction,
+			Value: hash,
+			Match: models.SearchMatchExact,
+			Score: searchScoreExact - searchScorePendingDelta,
+		})
+	}
+}
+
+// hashPrefix autocompletes block and transaction hashes. Mined transactions
+// are looked up in the transfer collection, which holds every transaction, and
+// in receipts; pending ones in the mempool.
+func (s *searcher) hashPrefix(prefix string) {
+	s.blocks(bson.M{"result.hash": bson.M{"$regex": "^" + prefix}}, models.SearchMatchPrefix, searchScoreHexPrefix)
+
+	for _, source := range []struct {
+		collection *mongo.Collection
+		field      string
+		resultType string
+		score      int
+	}{
+		{configs.TransferCollections, "txHash", models.SearchTypeTransaction, searchScoreHexPrefix},
+		{configs.ReceiptsCollection, "_id", models.SearchTypeTransaction, searchScoreHexPrefix},
+		{configs.GetCollection(configs.DB, PENDING_COLLECTION), "_id", models.SearchTypePendingTransaction, searchScoreHexPrefix - searchScorePendingDelta},
+	} {
+		cursor, err := source.collection.Find(s.ctx,
+			bson.M{source.field: bson.M{"$regex": "^" + prefix}},
+			options.Find().SetMaxTime(searchMaxTime).SetProjection(bson.M{source.field: 1}).SetLimit(s.limit))
+		if err != nil {
+			s.fail("transaction", err)
+			continue
+		}
+		var docs []bson.M
+		if err := cursor.All(s.ctx, &docs); err != nil {
+			s.fail("transaction", err)
+			continue
+		}
+		for _, doc := range docs {
+			hash, ok := doc[source.field].(string)
+			if !ok {
+				continue
+			}
+			s.add(models.SearchResult{
+				Type:  source.resultType,
+				Value: hash,
+				Match: models.SearchMatchPrefix,
+				Score: source.score,
+			})
+		}
+	}
+}
+
+// address reports a well-formed address, ranked lower if it was never seen on chain
+func (s *searcher) address(hex string) {
+	count, err := configs.AddressesCollections.CountDocuments(s.ctx, bson.M{"id": "z" + hex}, options.Count().SetLimit(1).SetMaxTime(searchMaxTime))
+	if err != nil {
+		s.fail("address", err)
+		return
+	}
+	score := searchScoreExact
+	if count == 0 {
+		score = searchScoreUnseen
+	}
+	s.add(models.SearchResult{
+		Type:  models.SearchTypeAddress,
+		Value: "Z" + hex,
+		Match: models.SearchMatchExact,
+		Score: score,
+	})
+}
+
+// addressPrefix autocompletes known addresses; the addresses collection stores them lowercase
+func (s *searcher) addressPrefix(hex string) {
+	cursor, err := configs.AddressesCollections.Find(s.ctx,
+		bson.M{"id": bson.M{"$regex": "^z" + hex}},
+		options.Find().SetMaxTime(searchMaxTime).SetProjection(bson.M{"id": 1}).SetLimit(s.limit))
+	if err != nil {
+		s.fail("address", err)
+		return
+	}
+	var addresses []models.Address
+	if err := cursor.All(s.ctx, &addresses); err != nil {
+		s.fail("address", err)
+		return
+	}
+	for _, address := range addresses {
+		s.add(models.SearchResult{
+			Type:  models.SearchTypeAddress,
+			Value: normalizeAddress(address.ID),
+			Match: models.SearchMatchPrefix,
+			Score: searchScoreHexPrefix,
+		})
+	}
+}
+
+// prefixRange matches strings starting with prefix under the index collation;
+// U+FFFF sorts after every character
+func prefixRange(prefix string) bson.M {
+	return bson.M{"$gte": prefix, "$lt": prefix + "\uffff"}
+}
+
+// tokens autocompletes token symbols and names, case-insensitively
+func (s *searcher) tokens(text string) {
+	for _, field := range []string{"symbol", "name"} {
+		cursor, err := configs.ContractInfoCollection.Find(s.ctx,
+			bson.M{field: prefixRange(text), "isToken": true},
+			options.Find().
+				SetMaxTime(searchMaxTime).
+				SetCollation(caseInsensitive).
+				SetProjection(bson.M{"address": 1, "name": 1, "symbol": 1}).
+				SetLimit(s.limit))
+		if err != nil {
+			s.fail("token", err)
+			continue
+		}
+		var tokens []models.ContractInfo
+		if err := cursor.All(s.ctx, &tokens); err != nil {
+			s.fail("token", err)
+			continue
+		}
+
+		for _, token := range tokens {
+			result := models.SearchResult{
+				Type:  models.SearchTypeToken,
+				Value: normalizeAddress(token.ContractAddress),
+				Name:  fmt.Sprintf("%s (%s)", token.TokenName, token.TokenSymbol),
+				Match: models.SearchMatchPrefix,
+				Score: searchScoreTokenPrefix,
+			}
+			if strings.EqualFold(token.TokenSymbol, text) {
+				result.Match, result.Score = models.SearchMatchExact, searchScoreSymbol
+			} else if strings.EqualFold(token.TokenName, text) {
+				result.Match, result.Score = models.SearchMatchExact, searchScoreName
+			}
+			s.add(result)
+		}
+	}
+}
+
+// labels autocompletes public label names, case-insensitively
+func (s *searcher) labels(text string) {
+	cursor, err := configs.AddressLabelsCollection.Find(s.ctx,
+		bson.M{"name": prefixRange(text), "public": true},
+		options.Find().SetMaxTime(searchMaxTime).SetCollation(caseInsensitive).SetLimit(s.limit))
+	if err != nil {
+		s.fail("label", err)
+		return
+	}
+	var labels []models.AddressLabel
+	if err := cursor.All(s.ctx, &labels); err != nil {
+		s.fail("label", err)
+		return
+	}
+	for _, label := range labels {
+		result := models.SearchResult{
+			Type:  models.SearchTypeLabel,
+			Value: label.Address,
+			Name:  label.Name,
+			Match: models.SearchMatchPrefix,
+			Score: searchScoreLabelPrefix,
+		}
+		if strings.EqualFold(label.Name, text) {
+			result.Match, result.Score = models.SearchMatchExact, searchScoreLabel
+		}
+		s.add(result)
+	}
+}
+
+// attachLabels names address results after their public label
+func (s *searcher) attachLabels() {
+	var addresses []string
+	for _, result := range s.results {
+		if result.Type == models.SearchTypeAddress {
+			addresses = append(addresses, result.Value)
+		}
+	}
+	labels := GetPublicLabels(addresses...)
+	for i, result := range s.results {
+		if label, ok := labels[result.Value]; ok && result.Type == models.SearchTypeAddress {
+			s.results[i].Name = label.Name
+		}
+	}
+}
+
+// searchError reports queries stopped by their time limit as ErrSearchTimeout
+func searchError(err error) error {
+	var commandErr mongo.CommandError
+	if (errors.As(err, &commandErr) && commandErr.Code == maxTimeMSExpired) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrSearchTimeout
+	}
+	return err
+}
+
+// isAddressSearch reports whether a lowercase query is a full address, with or without a Z or 0x prefix
+func isAddressSearch(query string) bool {
+	hex := strings.TrimPrefix(strings.TrimPrefix(query, "0x"), "z")
+	return len(hex) == 40 && hexPattern.MatchString(hex)
+}
+
+// isDecimal reports whether s consists of decimal digits only
+func isDecimal(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassifySearch(t *testing.T) {
+	number := func(n uint64) *uint64 { return &n }
+	address := strings.Repeat("ab", 20)
+	hash := strings.Repeat("cd", 32)
+	key := strings.Repeat("ef", 40)
+
+	tests := []struct {
+		name  string
+		query string
+		want  searchPlan
+	}{
+		{name: "block number", query: "12345", want: searchPlan{number: number(12345), text: "12345"}},
+		{name: "single digit", query: "7", want: searchPlan{number: number(7)}},
+		{name: "decimal is not a hex prefix", query: "123456", want: searchPlan{number: number(123456), text: "123456"}},
+		{name: "z address", query: "Z" + strings.ToUpper(address), want: searchPlan{address: address}},
+		{name: "z address prefix", query: "zabcdef", want: searchPlan{addressPrefix: "abcdef", text: "zabcdef"}},
+		{name: "short z prefix", query: "zab", want: searchPlan{text: "zab"}},
+		{name: "0x hash", query: "0x" + hash, want: searchPlan{hash: "0x" + hash}},
+		{name: "bare hash", query: hash, want: searchPlan{hash: "0x" + hash}},
+		{name: "0x address", query: "0x" + address, want: searchPlan{address: address}},
+		{name: "validator key", query: "0x" + key, want: searchPlan{validatorKey: key}},
+		{name: "hex prefix", query: "0xABCDEF", want: searchPlan{
+			hashPrefix: "0xabcdef", addressPrefix: "abcdef", validatorKeyPrefix: "abcdef", text: "0xABCDEF",
+		}},
+		{name: "0x decimal digits are hex", query: "0x123456", want: searchPlan{
+			hashPrefix: "0x123456", addressPrefix: "123456", validatorKeyPrefix: "123456", text: "0x123456",
+		}},
+		{name: "bare hex prefix", query: "deadbeef", want: searchPlan{
+			hashPrefix: "0xdeadbeef", addressPrefix: "deadbeef", validatorKeyPrefix: "deadbeef", text: "deadbeef",
+		}},
+		{name: "short hex", query: "0xab", want: searchPlan{text: "0xab"}},
+		{name: "token name", query: "Wrapped", want: searchPlan{text: "Wrapped"}},
+		{name: "single letter", query: "q", want: searchPlan{}},
+		{name: "non hex after z", query: "zond", want: searchPlan{text: "zond"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifySearch(tt.query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("classifySearch(%q) = %+v, wanted %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReturnSlots returns a page of slots, newest first, optionally filtered by status, epoch or proposer
+func ReturnSlots(page, limit int64, status string, epoch, proposer *int64) (*models.SlotsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 25
+	}
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	if epoch != nil {
+		filter["epoch"] = *epoch
+	}
+	if proposer != nil {
+		filter["proposerIndex"] = *proposer
+	}
+
+	total, err := configs.SlotsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count slots: %v", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
+
+	cursor, err := configs.SlotsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slots: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	slots := make([]models.SlotRecord, 0)
+	if err := cursor.All(ctx, &slots); err != nil {
+		return nil, fmt.Errorf("failed to decode slots: %v", err)
+	}
+
+	return &models.SlotsResponse{
+		Slots: slots,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}, nil
+}
+
+// GetEpochSummary returns every stored slot of an epoch with proposed/missed/orphaned counts
+func GetEpochSummary(epoch int64) (*models.EpochSummaryResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := configs.SlotsCollection.Find(ctx, bson.M{"epoch": epoch},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query epoch slots: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	slots := make([]models.SlotRecord, 0, SlotsPerEpoch)
+	if err := cursor.All(ctx, &slots); err != nil {
+		return nil, fmt.Errorf("failed to decode epoch slots: %v", err)
+	}
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("epoch %d not found", epoch)
+	}
+
+	summary := &models.EpochSummaryResponse{
+		Epoch:     epoch,
+		StartSlot: epoch * SlotsPerEpoch,
+		EndSlot:   (epoch+1)*SlotsPerEpoch - 1,
+		Finalized: len(slots) == SlotsPerEpoch,
+		Slots:     slots,
+	}
+	for _, s := range slots {
+		switch s.Status {
+		case "proposed":
+			summary.ProposedCount++
+		case "missed":
+			summary.MissedCount++
+		case "orphaned":
+			summary.OrphanedCount++
+		}
+		if !s.Finalized {
+			summary.Finalized = false
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,343 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Stream event topics
+const (
+	StreamTopicBlock         = "block"
+	StreamTopicTransaction   = "transaction"
+	StreamTopicPending       = "pending"
+	StreamTopicTokenTransfer = "tokenTransfer"
+)
+
+const (
+	// streamPollInterval is how often collections are polled when change streams are unavailable
+	streamPollInterval = 2 * time.Second
+	// streamRetryDelay is the wait before reopening a change stream that failed
+	streamRetryDelay = 5 * time.Second
+	// streamPollBatch caps the documents read from a collection per poll
+	streamPollBatch = 500
+	// changeStreamsUnsupported is the server error for change streams on a standalone server
+	changeStreamsUnsupported = 40573
+	// changeStreamFatalError and changeStreamHistoryLost are the server errors
+	// for a resume token that has left the oplog
+	changeStreamFatalError  = 280
+	changeStreamHistoryLost = 286
+)
+
+// StreamEvent is a newly stored document pushed to stream subscribers
+type StreamEvent struct {
+	Topic     string
+	Data      interface{}
+	Addresses []string // Z-prefix lowercase addresses involved
+	Token     string   // Token contract of token transfers, Z-prefix lowercase
+}
+
+// streamSource turns the documents inserted into a collection into events
+type streamSource struct {
+	name       string
+	collection *mongo.Collection
+	event      func(doc bson.Raw) (StreamEvent, bool)
+	// poll publishes the documents stored since its previous call
+	poll func(ctx context.Context, publish func(StreamEvent))
+}
+
+// WatchStream publishes new blocks, transactions, pending transactions and
+// token transfers until ctx is done. Inserts are read from change streams;
+// servers without them, such as a standalone mongod, are polled instead.
+func WatchStream(ctx context.Context, publish func(StreamEvent)) {
+	tokenTransfers := configs.GetCollection(configs.DB, "tokenTransfers")
+	pending := configs.GetCollection(configs.DB, PENDING_COLLECTION)
+
+	sources := []streamSource{
+		{
+			name:       "blocks",
+			collection: configs.BlocksCollection,
+			event:      blockEvent,
+			poll:       pollAfter(configs.BlocksCollection, "blockNum", blockEvent),
+		},
+		{
+			name:       "transfer",
+			collection: configs.TransferCollections,
+			event:      transactionEvent,
+			poll:       pollAfter(configs.TransferCollections, "_id", transactionEvent),
+		},
+		{
+			name:       "pending_transactions",
+			collection: pending,
+			event:      pendingEvent,
+			poll:       pollPending(pending),
+		},
+		{
+			name:       "tokenTransfers",
+			collection: tokenTransfers,
+			event:      tokenTransferEvent,
+			poll:       pollAfter(tokenTransfers, "_id", tokenTransferEvent),
+		},
+	}
+	for _, source := range sources {
+		go watchSource(ctx, source, publish)
+	}
+}
+
+// watchSource publishes the inserts of one collection, reopening the change
+// stream after errors and resuming where it left off. When the oplog no longer
+// holds that point, the stream restarts from now and the inserts in between
+// are not published.
+func watchSource(ctx context.Context, source streamSource, publish func(StreamEvent)) {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": "insert"}}}}
+
+	var resumeToken bson.Raw
+	for ctx.Err() == nil {
+		opts := options.ChangeStream()
+		if resumeToken != nil {
+			opts.SetResumeAfter(resumeToken)
+		}
+
+		stream, err := source.collection.Watch(ctx, pipeline, opts)
+		if err != nil {
+			var commandErr mongo.CommandError
+			if errors.As(err, &commandErr) && commandErr.Code == changeStreamsUnsupported {
+				log.Printf("Change streams unavailable for %s, polling instead", source.name)
+				pollSource(ctx, source, publish)
+				return
+			}
+			if resumeToken != nil && resumeTokenLost(err) {
+				log.Printf("Change stream on %s cannot resume, restarting from now: %v", source.name, err)
+				resumeToken = nil
+				continue
+			}
+			log.Printf("Failed to watch %s: %v", source.name, err)
+			sleepContext(ctx, streamRetryDelay)
+			continue
+		}
+
+		for stream.Next(ctx) {
+			resumeToken = stream.ResumeToken()
+			var change struct {
+				FullDocument bson.Raw `bson:"fullDocument"`
+			}
+			if err := stream.Decode(&change); err != nil {
+				continue
+			}
+			if event, ok := source.event(change.FullDocument); ok {
+				publish(event)
+			}
+		}
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			if resumeToken != nil && resumeTokenLost(err) {
+				log.Printf("Change stream on %s cannot resume, restarting from now: %v", source.name, err)
+				resumeToken = nil
+			} else {
+				log.Printf("Change stream on %s failed: %v", source.name, err)
+				sleepContext(ctx, streamRetryDelay)
+			}
+		}
+		stream.Close(context.Background())
+	}
+}
+
+// resumeTokenLost reports whether a change stream failed because its resume
+// point is no longer in the oplog, so retrying with the same token cannot work
+func resumeTokenLost(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) &&
+		(serverErr.HasErrorCode(changeStreamHistoryLost) || serverErr.HasErrorCode(changeStreamFatalError))
+}
+
+// pollSource calls the poll of a source every streamPollInterval
+func pollSource(ctx context.Context, source streamSource, publish func(StreamEvent)) {
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			source.poll(ctx, publish)
+		}
+	}
+}
+
+// pollAfter returns a poll that publishes the documents whose ascending field
+// is past the last one seen. The first poll only records the newest document.
+func pollAfter(collection *mongo.Collection, field string, event func(bson.Raw) (StreamEvent, bool)) func(context.Context, func(StreamEvent)) {
+	var last *bson.RawValue
+
+	return func(ctx context.Context, publish func(StreamEvent)) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		if last == nil {
+			var newest bson.Raw
+			err := collection.FindOne(ctx, bson.M{}, options.FindOne().
+				SetSort(bson.D{{Key: field, Value: -1}}).
+				SetProjection(bson.M{field: 1})).Decode(&newest)
+			if err != nil {
+				if err != mongo.ErrNoDocuments {
+					log.Printf("Failed to poll %s: %v", collection.Name(), err)
+				}
+				return
+			}
+			value := newest.Lookup(field)
+			last = &value
+			return
+		}
+
+		cursor, err := collection.Find(ctx, bson.M{field: bson.M{"$gt": *last}}, options.Find().
+			SetSort(bson.D{{Key: field, Value: 1}}).
+			SetLimit(streamPollBatch))
+		if err != nil {
+			log.Printf("Failed to poll %s: %v", collection.Name(), err)
+			return
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			// The cursor reuses its buffer, so keep a copy of the position
+			doc := make(bson.Raw, len(cursor.Current))
+			copy(doc, cursor.Current)
+			value := doc.Lookup(field)
+			last = &value
+			if e, ok := event(doc); ok {
+				publish(e)
+			}
+		}
+	}
+}
+
+// pollPending returns a poll that publishes the pending transactions that were
+// not pending at its previous call. Pending transactions are keyed by hash and
+// rewritten on every mempool sync, so the pending set is compared instead of
+// an insert position.
+func pollPending(collection *mongo.Collection) func(context.Context, func(StreamEvent)) {
+	var seen map[string]bool
+
+	return func(ctx context.Context, publish func(StreamEvent)) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+
+		cursor, err := collection.Find(ctx, bson.M{"status": "pending"}, options.Find().
+			SetSort(bson.D{{Key: "lastSeen", Value: -1}}).
+			SetLimit(streamPollBatch))
+		if err != nil {
+			log.Printf("Failed to poll %s: %v", collection.Name(), err)
+			return
+		}
+		defer cursor.Close(ctx)
+
+		current := make(map[string]bool)
+		for cursor.Next(ctx) {
+			hash, _ := cursor.Current.Lookup("_id").StringValueOK()
+			current[hash] = true
+			if seen == nil || seen[hash] {
+				continue
+			}
+			if e, ok := pendingEvent(cursor.Current); ok {
+				publish(e)
+			}
+		}
+		if cursor.Err() == nil {
+			seen = current
+		}
+	}
+}
+
+// blockEvent summarises a stored block
+func blockEvent(doc bson.Raw) (StreamEvent, bool) {
+	var block models.ZondUint64Version
+	if err := bson.Unmarshal(doc, &block); err != nil {
+		return StreamEvent{}, false
+	}
+	result := block.Result
+	return StreamEvent{
+		Topic: StreamTopicBlock,
+		Data: models.StreamBlock{
+			Number:           hexToDecimal(result.Number),
+			Hash:             result.Hash,
+			ParentHash:       result.ParentHash,
+			Timestamp:        hexToDecimal(result.Timestamp),
+			TransactionCount: len(result.Transactions),
+			GasUsed:          hexToDecimal(result.GasUsed),
+			GasLimit:         hexToDecimal(result.GasLimit),
+			BaseFeePerGas:    hexToDecimal(result.BaseFeePerGas),
+			Size:             hexToDecimal(result.Size),
+		},
+	}, true
+}
+
+// transactionEvent reads a stored transfer document
+func transactionEvent(doc bson.Raw) (StreamEvent, bool) {
+	var tx models.StreamTransaction
+	if err := bson.Unmarshal(doc, &tx); err != nil {
+		return StreamEvent{}, false
+	}
+	tx.BlockNumber = hexToDecimal(tx.BlockNumber)
+	tx.Timestamp = hexToDecimal(tx.Timestamp)
+	return StreamEvent{
+		Topic:     StreamTopicTransaction,
+		Data:      tx,
+		Addresses: streamAddresses(tx.From, tx.To, tx.ContractAddress),
+	}, true
+}
+
+// pendingEvent reads a stored pending transaction
+func pendingEvent(doc bson.Raw) (StreamEvent, bool) {
+	var tx models.PendingTransaction
+	if err := bson.Unmarshal(doc, &tx); err != nil {
+		return StreamEvent{}, false
+	}
+	return StreamEvent{
+		Topic:     StreamTopicPending,
+		Data:      &tx,
+		Addresses: streamAddresses(tx.From, tx.To),
+	}, true
+}
+
+// tokenTransferEvent reads a stored token transfer
+func tokenTransferEvent(doc bson.Raw) (StreamEvent, bool) {
+	var transfer models.TokenTransfer
+	if err := bson.Unmarshal(doc, &transfer); err != nil {
+		return StreamEvent{}, false
+	}
+	return StreamEvent{
+		Topic:     StreamTopicTokenTransfer,
+		Data:      transfer,
+		Addresses: streamAddresses(transfer.From, transfer.To),
+		Token:     normalizeAddress(transfer.ContractAddress),
+	}, true
+}
+
+// streamAddresses normalizes the non-empty addresses of an event
+func streamAddresses(addresses ...string) []string {
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if address != "" {
+			normalized = append(normalized, normalizeAddress(address))
+		}
+	}
+	return normalized
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
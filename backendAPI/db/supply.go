@@ -0,0 +1,28 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetSupply returns the latest supply breakdown, or nil if none was computed yet
+func GetSupply() (*models.Supply, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var supply models.Supply
+	err := configs.SupplyCollection.FindOne(ctx, bson.M{"_id": "current"}).Decode(&supply)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get supply: %v", err)
+	}
+	return &supply, nil
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TimeSeriesMetrics maps each supported metric to the value it takes from a stats bucket
+var TimeSeriesMetrics = map[string]func(models.NetworkStatsRecord) interface{}{
+	"txCount":             func(r models.NetworkStatsRecord) interface{} { return r.TxCount },
+	"activeAddresses":     func(r models.NetworkStatsRecord) interface{} { return r.ActiveAddresses },
+	"newAddresses":        func(r models.NetworkStatsRecord) interface{} { return r.NewAddresses },
+	"gasUsed":             func(r models.NetworkStatsRecord) interface{} { return r.GasUsed },
+	"avgBlockTime":        averageBlockTime,
+	"avgFee":              averageFee,
+	"burntFees":           func(r models.NetworkStatsRecord) interface{} { return r.BurntFees },
+	"contractDeployments": func(r models.NetworkStatsRecord) interface{} { return r.ContractDeployments },
+	"tokenTransfers":      func(r models.NetworkStatsRecord) interface{} { return r.TokenTransfers },
+}
+
+// TimeSeriesIntervals are the bucket sizes maintained by the syncer
+var TimeSeriesIntervals = map[string]bool{"hour": true, "day": true}
+
+// GetTimeSeries returns a metric for the most recent buckets of an interval
+func GetTimeSeries(metric string, interval string, limit int) (*models.TimeSeriesResponse, error) {
+	value, ok := TimeSeriesMetrics[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+	if !TimeSeriesIntervals[interval] {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := configs.NetworkStatsCollection.Find(ctx, bson.M{"interval": interval}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query network stats: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []models.NetworkStatsRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode network stats: %v", err)
+	}
+
+	data := make([]models.TimeSeriesPoint, len(records))
+	for i, record := range records {
+		// Buckets are read newest first and returned oldest first for charts
+		data[len(records)-1-i] = models.TimeSeriesPoint{
+			Timestamp: record.Timestamp,
+			Value:     value(record),
+		}
+	}
+
+	return &models.TimeSeriesResponse{
+		Metric:   metric,
+		Interval: interval,
+		Data:     data,
+	}, nil
+}
+
+// averageBlockTime returns the mean seconds between blocks in a bucket
+func averageBlockTime(r models.NetworkStatsRecord) interface{} {
+	if r.BlockCount == 0 {
+		return float64(0)
+	}
+	return float64(r.BlockTimeSum) / float64(r.BlockCount)
+}
+
+// averageFee returns the mean fee in wei of the transactions with a stored receipt
+func averageFee(r models.NetworkStatsRecord) interface{} {
+	total, ok := new(big.Int).SetString(r.TotalFees, 10)
+	if !ok || r.FeeTxCount == 0 {
+		return "0"
+	}
+	return total.Div(total, big.NewInt(r.FeeTxCount)).String()
+}
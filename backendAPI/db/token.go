@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -133,8 +134,16 @@ func normalizeAddress(address string) string {
 	return "Z" + strings.ToLower(address)
 }
 
-// GetTokenHolders returns all holders of a specific token contract with pagination
-func GetTokenHolders(contractAddress string, page, limit int) ([]models.TokenBalance, int, error) {
+// addressVariants matches an address stored with either prefix case
+func addressVariants(address string) bson.M {
+	normalized := normalizeAddress(address)
+	return bson.M{"$in": bson.A{normalized, strings.ToLower(normalized)}}
+}
+
+// GetTokenHolders returns a page of the holders of a token contract, largest
+// balance first, and the cursor of the next page. A cursor continues after its
+// holder; otherwise page is used.
+func GetTokenHolders(contractAddress string, page, limit int, cursor *PageCursor) ([]models.TokenBalance, int, string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -144,9 +153,12 @@ func GetTokenHolders(contractAddress string, page, limit int) ([]models.TokenBal
 	// Count total holders
 	totalCount, err := collection.CountDocuments(ctx, bson.M{"contractAddress": normalizedContract})
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
+	// Balances tie-break on the holder address so every holder has a unique position
+	positionFields := []string{"balanceDecimal", "holderAddress"}
+
 	// Aggregation pipeline to get holders sorted by balance
 	pipeline := []bson.M{
 		{
@@ -158,39 +170,57 @@ func GetTokenHolders(contractAddress string, page, limit int) ([]models.TokenBal
 				"balanceDecimal": bson.M{"$toDecimal": "$balance"},
 			},
 		},
-		// Sort by balance descending
-		{
-			"$sort": bson.M{"balanceDecimal": -1},
-		},
-		// Pagination
-		{
+	}
+	if cursor != nil {
+		balance, err := primitive.ParseDecimal128(cursor.Balance)
+		if err != nil {
+			return nil, 0, "", ErrInvalidCursor
+		}
+		pipeline = append(pipeline, bson.M{
+			"$match": keysetAfter(positionFields, []interface{}{balance, cursor.Key}),
+		})
+	}
+	// Sort by balance descending
+	pipeline = append(pipeline, bson.M{
+		"$sort": keysetSort(positionFields),
+	})
+	// Pagination
+	if cursor == nil {
+		pipeline = append(pipeline, bson.M{
 			"$skip": int64(page * limit),
-		},
-		{
-			"$limit": int64(limit),
-		},
+		})
 	}
+	pipeline = append(pipeline, bson.M{
+		"$limit": int64(limit),
+	})
 
-	cursor, err := collection.Aggregate(ctx, pipeline)
+	results, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
-	defer cursor.Close(ctx)
+	defer results.Close(ctx)
 
 	var holders []models.TokenBalance
-	if err := cursor.All(ctx, &holders); err != nil {
-		return nil, 0, err
+	if err := results.All(ctx, &holders); err != nil {
+		return nil, 0, "", err
 	}
 
 	if holders == nil {
 		holders = make([]models.TokenBalance, 0)
 	}
 
-	return holders, int(totalCount), nil
+	next := ""
+	if len(holders) == limit {
+		last := holders[len(holders)-1]
+		next = EncodeCursor(PageCursor{Balance: last.Balance, Key: last.HolderAddress})
+	}
+	return holders, int(totalCount), next, nil
 }
 
-// GetTokenTransfers returns all transfers for a specific token contract with pagination
-func GetTokenTransfers(contractAddress string, page, limit int) ([]models.TokenTransfer, int64, error) {
+// GetTokenTransfers returns a page of the transfers of a token contract, newest
+// first, and the cursor of the next page. A cursor continues after its
+// transfer; otherwise page is used.
+func GetTokenTransfers(contractAddress string, page, limit int, cursor *PageCursor) ([]models.TokenTransfer, int64, string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -200,31 +230,111 @@ func GetTokenTransfers(contractAddress string, page, limit int) ([]models.TokenT
 	// Count total transfers
 	totalCount, err := collection.CountDocuments(ctx, bson.M{"contractAddress": normalizedContract})
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
-	// Find with pagination, sorted by block number descending (most recent first)
+	// Find with pagination, sorted by chain position descending (most recent first)
+	filter := bson.M{"contractAddress": normalizedContract}
 	opts := options.Find().
-		SetSort(bson.D{{Key: "blockNumber", Value: -1}}).
-		SetSkip(int64(page * limit)).
+		SetSort(keysetSort(tokenTransferPositionFields)).
 		SetLimit(int64(limit))
+	if cursor != nil {
+		after, err := tokenTransfersAfter(cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		filter = bson.M{"$and": bson.A{filter, after}}
+	} else {
+		opts.SetSkip(int64(page * limit))
+	}
 
-	cursor, err := collection.Find(ctx, bson.M{"contractAddress": normalizedContract}, opts)
+	results, err := collection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
-	defer cursor.Close(ctx)
+	defer results.Close(ctx)
 
 	var transfers []models.TokenTransfer
-	if err := cursor.All(ctx, &transfers); err != nil {
-		return nil, 0, err
+	if err := results.All(ctx, &transfers); err != nil {
+		return nil, 0, "", err
 	}
 
 	if transfers == nil {
 		transfers = make([]models.TokenTransfer, 0)
 	}
 
-	return transfers, totalCount, nil
+	return transfers, totalCount, tokenTransfersNextCursor(transfers, limit), nil
+}
+
+// GetTokenTransfersByAddress returns a page of the token transfers sent or
+// received by an address, newest first, and the cursor of the next page
+func GetTokenTransfersByAddress(address string, limit int, cursor *PageCursor) ([]models.TokenTransfer, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	filter, err := tokenTransfersByAddressFilter(address, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	opts := options.Find().
+		SetSort(keysetSort(tokenTransferPositionFields)).
+		SetLimit(int64(limit))
+
+	results, err := configs.GetCollection(configs.DB, "tokenTransfers").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer results.Close(ctx)
+
+	transfers := make([]models.TokenTransfer, 0, limit)
+	if err := results.All(ctx, &transfers); err != nil {
+		return nil, "", err
+	}
+
+	return transfers, tokenTransfersNextCursor(transfers, limit), nil
+}
+
+// tokenTransfersByAddressFilter matches the token transfers sent or received
+// by an address, continuing after the cursor when there is one
+func tokenTransfersByAddressFilter(address string, cursor *PageCursor) (bson.M, error) {
+	normalized := normalizeAddress(address)
+	variants := bson.A{normalized, strings.ToLower(normalized)}
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"from": bson.M{"$in": variants}},
+		bson.M{"to": bson.M{"$in": variants}},
+	}}
+	if cursor != nil {
+		after, err := tokenTransfersAfter(cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter = bson.M{"$and": bson.A{filter, after}}
+	}
+	return filter, nil
+}
+
+// tokenTransferPositionFields order token transfers by chain position; the
+// document id breaks ties between transfers of the same log, such as the
+// direct transfers of a transaction
+var tokenTransferPositionFields = []string{"blockNum", "txIndex", "logIndex", "_id"}
+
+// tokenTransfersAfter matches the token transfers that follow a cursor position
+func tokenTransfersAfter(cursor *PageCursor) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(cursor.Key)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return keysetAfter(tokenTransferPositionFields, []interface{}{cursor.Block, cursor.Tx, cursor.Log, id}), nil
+}
+
+// tokenTransfersNextCursor returns the cursor after the last transfer of a full page
+func tokenTransfersNextCursor(transfers []models.TokenTransfer, limit int) string {
+	if limit == 0 || len(transfers) < limit {
+		return ""
+	}
+	last := transfers[len(transfers)-1]
+	return EncodeCursor(PageCursor{Block: last.BlockNum, Tx: last.TxIndex, Log: last.LogIndex, Key: last.ID.Hex()})
 }
 
 // GetTokenInfo returns summary information about a token
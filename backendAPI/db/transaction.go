@@ -235,7 +235,10 @@ func ReturnAllTransactionsByAddress(address string) ([]models.TransactionByAddre
 	return transactions, nil
 }
 
-func ReturnTransactionsNetwork(page int) ([]models.TransactionByAddress, error) {
+// ReturnTransactionsNetwork returns a page of the latest transactions and the
+// cursor of the next page. A cursor continues after its transaction; otherwise
+// page is used.
+func ReturnTransactionsNetwork(page int, cursor *PageCursor) ([]models.TransactionByAddress, string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	var transactions []models.TransactionByAddress
 	defer cancel()
@@ -252,33 +255,44 @@ func ReturnTransactionsNetwork(page int) ([]models.TransactionByAddress, error)
 		{Key: "amount", Value: 1},
 		{Key: "paidFees", Value: 1},
 		{Key: "blockNumber", Value: 1},
+		{Key: "blockNum", Value: 1},
+		{Key: "txIndex", Value: 1},
 	}
 
 	opts := options.Find().
 		SetProjection(projection).
-		SetSort(primitive.D{{Key: "timeStamp", Value: -1}})
+		SetSort(keysetSort(transactionPositionFields))
 
-	if page == 0 {
-		page = 1
+	filter := bson.M{}
+	if cursor != nil {
+		after, err := transactionsAfter(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter = after
+	} else {
+		if page == 0 {
+			page = 1
+		}
+		opts.SetSkip(int64((page - 1) * limit))
 	}
-	opts.SetSkip(int64((page - 1) * limit))
 	opts.SetLimit(int64(limit))
 
-	results, err := configs.GetCollection(configs.DB, "transactionByAddress").Find(ctx, primitive.D{}, opts)
+	results, err := configs.GetCollection(configs.DB, "transactionByAddress").Find(ctx, filter, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query transactions: %v", err)
+		return nil, "", fmt.Errorf("failed to query transactions: %v", err)
 	}
 
 	defer results.Close(ctx)
 	for results.Next(ctx) {
 		var singleTransaction models.TransactionByAddress
 		if err = results.Decode(&singleTransaction); err != nil {
-			return nil, fmt.Errorf("failed to decode transaction: %v", err)
+			return nil, "", fmt.Errorf("failed to decode transaction: %v", err)
 		}
 		transactions = append(transactions, singleTransaction)
 	}
 
-	return transactions, nil
+	return transactions, transactionsNextCursor(transactions, limit), nil
 }
 
 func ReturnTransactions(address string, page, limit int) ([]models.TransactionByAddress, error) {
@@ -537,64 +551,39 @@ func GetTransactionByHash(hash string) (*models.Transaction, error) {
 	}, nil
 }
 
-func ReturnNonZeroTransactions(address string, page, limit int) ([]models.TransactionByAddress, error) {
+// ReturnNonZeroTransactions returns a page of the value transfers of an
+// address, newest first, and the cursor of the next page. A cursor continues
+// after its transaction; otherwise page is used.
+func ReturnNonZeroTransactions(address string, page, limit int, cursor *PageCursor) ([]models.TransactionByAddress, string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	var transactions []models.TransactionByAddress
 	defer cancel()
 
-	projection := primitive.D{
-		{Key: "inOut", Value: 1},
-		{Key: "txType", Value: 1},
-		{Key: "address", Value: 1},
-		{Key: "txHash", Value: 1},
-		{Key: "timeStamp", Value: 1},
-		{Key: "amount", Value: 1},
-		{Key: "from", Value: 1},
-		{Key: "to", Value: 1},
-		{Key: "blockNumber", Value: 1},
-	}
-
-	// Sort by timestamp, newest first
+	// Sort by chain position, newest first
 	opts := options.Find().
-		SetProjection(projection).
-		SetSort(primitive.D{{Key: "timeStamp", Value: -1}})
-
-	// Format the address for query
-	formattedAddress := address
-	if !strings.HasPrefix(formattedAddress, "Z") {
-		formattedAddress = "Z" + formattedAddress
-	}
+		SetProjection(nonZeroTransactionProjection).
+		SetSort(keysetSort(transactionPositionFields))
 
-	// Use regex with case insensitivity for address matching
-	fromRegex := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(formattedAddress) + "$", Options: "i"}
-	toRegex := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(formattedAddress) + "$", Options: "i"}
-
-	// Create a filter for both from and to with this address and non-zero amount
-	filter := bson.M{
-		"$and": []bson.M{
-			{
-				"$or": []bson.M{
-					{"from": fromRegex},
-					{"to": toRegex},
-				},
-			},
-			{"amount": bson.M{"$gt": 0}}, // Only return transactions with amount > 0
-		},
+	filter, err := nonZeroTransactionsFilter(address, cursor)
+	if err != nil {
+		return nil, "", err
 	}
 
 	// Apply pagination
-	if limit != 0 {
+	if cursor == nil && limit != 0 {
 		if page == 0 {
 			page = 1
 		}
 		opts.SetSkip(int64((page - 1) * limit))
+	}
+	if limit != 0 {
 		opts.SetLimit(int64(limit))
 	}
 
 	// Execute the query
 	results, err := configs.TransactionByAddressCollection.Find(ctx, filter, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer results.Close(ctx)
 
@@ -602,24 +591,93 @@ func ReturnNonZeroTransactions(address string, page, limit int) ([]models.Transa
 	for results.Next(ctx) {
 		var singleTransaction models.TransactionByAddress
 		if err = results.Decode(&singleTransaction); err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
-		// Set the inOut flag based on the address's relation to the transaction
-		if strings.EqualFold(singleTransaction.From, formattedAddress) {
-			singleTransaction.InOut = 0 // Outgoing
-			singleTransaction.Address = singleTransaction.To
-		} else {
-			singleTransaction.InOut = 1 // Incoming
-			singleTransaction.Address = singleTransaction.From
-		}
+		setTransactionDirection(&singleTransaction, address)
 		transactions = append(transactions, singleTransaction)
 	}
 
 	// Check for cursor errors
 	if err = results.Err(); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return transactions, nil
+	return transactions, transactionsNextCursor(transactions, limit), nil
+}
+
+// setTransactionDirection sets the inOut flag and counterparty of a
+// transaction based on the address's relation to it
+func setTransactionDirection(tx *models.TransactionByAddress, address string) {
+	if strings.EqualFold(tx.From, normalizeAddress(address)) {
+		tx.InOut = 0 // Outgoing
+		tx.Address = tx.To
+	} else {
+		tx.InOut = 1 // Incoming
+		tx.Address = tx.From
+	}
+}
+
+// nonZeroTransactionProjection holds the fields of value transfer lists
+var nonZeroTransactionProjection = primitive.D{
+	{Key: "inOut", Value: 1},
+	{Key: "txType", Value: 1},
+	{Key: "address", Value: 1},
+	{Key: "txHash", Value: 1},
+	{Key: "timeStamp", Value: 1},
+	{Key: "amount", Value: 1},
+	{Key: "from", Value: 1},
+	{Key: "to", Value: 1},
+	{Key: "blockNumber", Value: 1},
+	{Key: "blockNum", Value: 1},
+	{Key: "txIndex", Value: 1},
+}
+
+// nonZeroTransactionsFilter matches the value transfers of an address,
+// continuing after the cursor when there is one
+func nonZeroTransactionsFilter(address string, cursor *PageCursor) (bson.M, error) {
+	// Addresses are stored lowercase; match both prefix cases so the from and to indexes apply
+	formattedAddress := normalizeAddress(address)
+	variants := bson.A{strings.ToLower(formattedAddress), formattedAddress}
+
+	// Create a filter for both from and to with this address and non-zero amount
+	conditions := []bson.M{
+		{
+			"$or": []bson.M{
+				{"from": bson.M{"$in": variants}},
+				{"to": bson.M{"$in": variants}},
+			},
+		},
+		{"amount": bson.M{"$gt": 0}}, // Only return transactions with amount > 0
+	}
+	if cursor != nil {
+		after, err := transactionsAfter(cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, after)
+	}
+	return bson.M{"$and": conditions}, nil
+}
+
+// transactionPositionFields order transactions by chain position; the document
+// id breaks ties between transactions stored without a known index
+var transactionPositionFields = []string{"blockNum", "txIndex", "_id"}
+
+// transactionsAfter matches the transactions that follow a cursor position
+func transactionsAfter(cursor *PageCursor) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(cursor.Key)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return keysetAfter(transactionPositionFields, []interface{}{cursor.Block, cursor.Tx, id}), nil
+}
+
+// transactionsNextCursor returns the cursor after the last transaction of a full page
+func transactionsNextCursor(transactions []models.TransactionByAddress, limit int) string {
+	if limit == 0 || len(transactions) < limit {
+		return ""
+	}
+	last := transactions[len(transactions)-1]
+	return EncodeCursor(PageCursor{Block: last.BlockNum, Tx: last.TxIndex, Key: last.ID.Hex()})
 }
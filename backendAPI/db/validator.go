@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -19,41 +20,63 @@ const (
 	SecondsPerSlot = 60
 )
 
-func ReturnValidators(pageToken string) (*models.ValidatorResponse, error) {
+// ReturnValidators returns a page of validators ordered by index, optionally filtered by status
+func ReturnValidators(page, limit int64, status string) (*models.ValidatorResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Get the validator document
-	var storage models.ValidatorStorage
-	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{"_id": "validators"}).Decode(&storage)
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	currentEpoch, err := getCurrentEpoch()
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			// Return empty response if no validators found
-			return &models.ValidatorResponse{
-				Validators:  make([]models.Validator, 0),
-				TotalStaked: "0",
-			}, nil
-		}
-		return nil, fmt.Errorf("failed to get validator document: %v", err)
+		return nil, err
 	}
 
-	// Get current epoch from latest block
-	latestBlock, err := GetLatestBlockFromSyncState()
+	// The syncer moves stored statuses on every epoch, so the filter can use status_idx
+	filter := bson.M{}
+	var total int64
+	if status != "" {
+		filter = bson.M{"status": status}
+		total, err = configs.ValidatorsCollections.CountDocuments(ctx, filter)
+	} else {
+		total, err = configs.ValidatorsCollections.EstimatedDocumentCount(ctx)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest block: %v", err)
+		return nil, fmt.Errorf("failed to count validators: %v", err)
 	}
 
-	// Convert hex block number to int for epoch calculation
-	currentEpoch := HexToInt(latestBlock) / 128 // Each epoch is 128 blocks
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
 
-	// Process validators
-	validators := make([]models.Validator, 0)
-	totalStaked := int64(0)
+	cursor, err := configs.ValidatorsCollections.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validators: %v", err)
+	}
+	defer cursor.Close(ctx)
 
-	for _, v := range storage.Validators {
-		// Calculate status
-		status := getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch)
-		isActive := status == "active"
+	var records []models.ValidatorRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode validators: %v", err)
+	}
+
+	// Process validators
+	validators := make([]models.Validator, 0, len(records))
+	for _, v := range records {
+		// Show the stored status so the list agrees with the filter
+		status := v.Status
+		if status == "" {
+			status = getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch)
+		}
 
 		// Calculate age in epochs
 		activationEpoch := parseEpoch(v.ActivationEpoch)
@@ -62,26 +85,27 @@ func ReturnValidators(pageToken string) (*models.ValidatorResponse, error) {
 			age = currentEpoch - activationEpoch
 		}
 
-		// Add validator to response
-		validator := models.Validator{
+		validators = append(validators, models.Validator{
 			Index:        v.Index,
 			Address:      v.PublicKeyHex,
 			Status:       status,
 			Age:          age,
 			StakedAmount: v.EffectiveBalance,
-			IsActive:     isActive,
-		}
-		validators = append(validators, validator)
+			IsActive:     status == "active",
+		})
+	}
 
-		// Add to total staked (parse as decimal since syncer stores decimal)
-		if balance, err := strconv.ParseInt(v.EffectiveBalance, 10, 64); err == nil {
-			totalStaked += balance
-		}
+	totals, err := latestValidatorTotals(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	return &models.ValidatorResponse{
-		Validators:  validators,
-		TotalStaked: fmt.Sprintf("%d", totalStaked),
+		Validators:      validators,
+		TotalStaked:     totals.TotalStaked,
+		TotalValidators: total,
+		Page:            page,
+		Limit:           limit,
 	}, nil
 }
 
@@ -90,16 +114,45 @@ func CountValidators() (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var storage models.ValidatorStorage
-	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{"_id": "validators"}).Decode(&storage)
+	count, err := configs.ValidatorsCollections.CountDocuments(ctx, bson.M{})
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return 0, nil
-		}
-		return 0, fmt.Errorf("failed to get validator document: %v", err)
+		return 0, fmt.Errorf("failed to count validators: %v", err)
+	}
+
+	return count, nil
+}
+
+// latestValidatorTotals returns the validator counts and total stake the
+// syncer recorded for the most recent epoch, or zero totals before the first
+func latestValidatorTotals(ctx context.Context) (*models.ValidatorHistoryRecord, error) {
+	var totals models.ValidatorHistoryRecord
+	err := configs.ValidatorHistoryCollection.FindOne(ctx, bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})).Decode(&totals)
+	if err == mongo.ErrNoDocuments {
+		return &models.ValidatorHistoryRecord{TotalStaked: "0"}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validator totals: %v", err)
 	}
+	return &totals, nil
+}
 
-	return int64(len(storage.Validators)), nil
+// getCurrentEpoch returns the beacon head epoch, falling back to the synced block height
+func getCurrentEpoch() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var epochInfo models.EpochInfo
+	err := configs.EpochInfoCollection.FindOne(ctx, bson.M{"_id": "current"}).Decode(&epochInfo)
+	if err == nil && epochInfo.HeadEpoch != "" {
+		return parseEpoch(epochInfo.HeadEpoch), nil
+	}
+
+	latestBlock, err := GetLatestBlockFromSyncState()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %v", err)
+	}
+	return HexToInt(latestBlock) / SlotsPerEpoch, nil
 }
 
 // Helper function to convert hex string to int64
@@ -208,55 +261,122 @@ func GetValidatorHistory(limit int) (*models.ValidatorHistoryResponse, error) {
 	}, nil
 }
 
+// validatorFilter matches a validator by decimal index or hex public key
+func validatorFilter(id string) bson.M {
+	if index, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return bson.M{"_id": index}
+	}
+	return bson.M{"publicKeyHex": strings.ToLower(strings.TrimPrefix(id, "0x"))}
+}
+
 // GetValidatorByID retrieves a validator by index or public key
 func GetValidatorByID(id string) (*models.ValidatorDetailResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var storage models.ValidatorStorage
-	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{"_id": "validators"}).Decode(&storage)
+	var v models.ValidatorRecord
+	err := configs.ValidatorsCollections.FindOne(ctx, validatorFilter(id)).Decode(&v)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("validators not found")
+			return nil, fmt.Errorf("validator not found")
 		}
-		return nil, fmt.Errorf("failed to get validators: %v", err)
+		return nil, fmt.Errorf("failed to get validator: %v", err)
 	}
 
-	// Get current epoch
-	latestBlock, err := GetLatestBlockFromSyncState()
+	currentEpoch, err := getCurrentEpoch()
+	if err != nil {
+		return nil, err
+	}
+
+	status := getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch)
+	activationEpoch := parseEpoch(v.ActivationEpoch)
+	age := int64(0)
+	if activationEpoch <= currentEpoch {
+		age = currentEpoch - activationEpoch
+	}
+
+	proposedCount, proposedBlocks, err := getProposedBlocks(ctx, v.ID, 25)
+	if err != nil {
+		return nil, err
+	}
+
+	attestations, err := getAttestationSummary(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+
+	deposits, err := getValidatorDeposits(ctx, v.PublicKeyHex)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest block: %v", err)
-	}
-	currentEpoch := HexToInt(latestBlock) / 128
-
-	// Find validator by index or public key
-	for _, v := range storage.Validators {
-		if v.Index == id || v.PublicKeyHex == id {
-			status := getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch)
-			activationEpoch := parseEpoch(v.ActivationEpoch)
-			age := int64(0)
-			if activationEpoch <= currentEpoch {
-				age = currentEpoch - activationEpoch
-			}
-
-			return &models.ValidatorDetailResponse{
-				Index:                      v.Index,
-				PublicKeyHex:               v.PublicKeyHex,
-				WithdrawalCredentialsHex:   v.WithdrawalCredentialsHex,
-				EffectiveBalance:           v.EffectiveBalance,
-				Slashed:                    v.Slashed,
-				ActivationEligibilityEpoch: v.ActivationEligibilityEpoch,
-				ActivationEpoch:            v.ActivationEpoch,
-				ExitEpoch:                  v.ExitEpoch,
-				WithdrawableEpoch:          v.WithdrawableEpoch,
-				Status:                     status,
-				Age:                        age,
-				CurrentEpoch:               fmt.Sprintf("%d", currentEpoch),
-			}, nil
+		return nil, err
+	}
+	fundedBy := ""
+	if len(deposits) > 0 {
+		fundedBy = deposits[0].Sender
+	}
+
+	var queueEstimate *models.QueueEntry
+	if status == "pending" || (parseEpoch(v.ExitEpoch) > currentEpoch && v.ExitEpoch != FAR_FUTURE_EPOCH) {
+		queueEstimate, err = getQueueEntry(v.ID)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return nil, fmt.Errorf("validator not found")
+	return &models.ValidatorDetailResponse{
+		Index:                      v.Index,
+		PublicKeyHex:               v.PublicKeyHex,
+		WithdrawalCredentialsHex:   v.WithdrawalCredentialsHex,
+		WithdrawalAddress:          v.WithdrawalAddress,
+		EffectiveBalance:           v.EffectiveBalance,
+		Slashed:                    v.Slashed,
+		ActivationEligibilityEpoch: v.ActivationEligibilityEpoch,
+		ActivationEpoch:            v.ActivationEpoch,
+		ExitEpoch:                  v.ExitEpoch,
+		WithdrawableEpoch:          v.WithdrawableEpoch,
+		Status:                     status,
+		Age:                        age,
+		CurrentEpoch:               fmt.Sprintf("%d", currentEpoch),
+		ProposedBlocksCount:        proposedCount,
+		ProposedBlocks:             proposedBlocks,
+		Attestations:               attestations,
+		FundedBy:                   fundedBy,
+		Deposits:                   deposits,
+		QueueEstimate:              queueEstimate,
+	}, nil
+}
+
+// getProposedBlocks returns the number of blocks attributed to a proposer and the most recent of them
+func getProposedBlocks(ctx context.Context, proposerIndex int64, limit int64) (int64, []models.ProposedBlock, error) {
+	filter := bson.M{"proposerIndex": proposerIndex}
+
+	count, err := configs.BlocksCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to count proposed blocks: %v", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "slot", Value: -1}}).
+		SetLimit(limit).
+		SetProjection(bson.M{
+			"number":    "$result.number",
+			"hash":      "$result.hash",
+			"timestamp": "$result.timestamp",
+			"slot":      1,
+			"graffiti":  1,
+		})
+
+	cursor, err := configs.BlocksCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get proposed blocks: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	blocks := make([]models.ProposedBlock, 0)
+	if err := cursor.All(ctx, &blocks); err != nil {
+		return 0, nil, fmt.Errorf("failed to decode proposed blocks: %v", err)
+	}
+
+	return count, blocks, nil
 }
 
 // GetValidatorStats returns aggregated validator statistics
@@ -264,50 +384,26 @@ func GetValidatorStats() (*models.ValidatorStatsResponse, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	var storage models.ValidatorStorage
-	err := configs.ValidatorsCollections.FindOne(ctx, bson.M{"_id": "validators"}).Decode(&storage)
+	// Counts and stake are aggregated by the syncer once per epoch from the
+	// same stored statuses the list filters on
+	totals, err := latestValidatorTotals(ctx)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return &models.ValidatorStatsResponse{}, nil
-		}
-		return nil, fmt.Errorf("failed to get validators: %v", err)
+		return nil, err
+	}
+	stats := &models.ValidatorStatsResponse{
+		TotalValidators: totals.ValidatorsCount,
+		ActiveCount:     totals.ActiveCount,
+		PendingCount:    totals.PendingCount,
+		ExitedCount:     totals.ExitedCount,
+		SlashedCount:    totals.SlashedCount,
+		TotalStaked:     totals.TotalStaked,
 	}
 
-	// Get current epoch
-	latestBlock, err := GetLatestBlockFromSyncState()
+	currentEpoch, err := getCurrentEpoch()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest block: %v", err)
-	}
-	currentEpoch := HexToInt(latestBlock) / 128
-
-	var activeCount, pendingCount, exitedCount, slashedCount int
-	totalStaked := int64(0)
-
-	for _, v := range storage.Validators {
-		status := getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch)
-		switch status {
-		case "active":
-			activeCount++
-		case "pending":
-			pendingCount++
-		case "exited":
-			exitedCount++
-		case "slashed":
-			slashedCount++
-		}
-
-		if balance, err := strconv.ParseInt(v.EffectiveBalance, 10, 64); err == nil {
-			totalStaked += balance
-		}
+		return nil, err
 	}
+	stats.CurrentEpoch = fmt.Sprintf("%d", currentEpoch)
 
-	return &models.ValidatorStatsResponse{
-		TotalValidators: len(storage.Validators),
-		ActiveCount:     activeCount,
-		PendingCount:    pendingCount,
-		ExitedCount:     exitedCount,
-		SlashedCount:    slashedCount,
-		TotalStaked:     fmt.Sprintf("%d", totalStaked),
-		CurrentEpoch:    fmt.Sprintf("%d", currentEpoch),
-	}, nil
+	return stats, nil
 }
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReturnValidatorEvents returns a page of validator events, newest first, optionally filtered by type
+func ReturnValidatorEvents(page, limit int64, eventType string) (*models.ValidatorEventsResponse, error) {
+	filter := bson.M{}
+	if eventType != "" {
+		filter["type"] = eventType
+	}
+	return findValidatorEvents(filter, page, limit)
+}
+
+// GetValidatorEvents returns the event timeline of a single validator, newest first
+func GetValidatorEvents(id string, page, limit int64) (*models.ValidatorEventsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var validator models.ValidatorRecord
+	err := configs.ValidatorsCollections.FindOne(ctx, validatorFilter(id),
+		options.FindOne().SetProjection(bson.M{"_id": 1})).Decode(&validator)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("validator not found")
+		}
+		return nil, fmt.Errorf("failed to get validator: %v", err)
+	}
+
+	return findValidatorEvents(bson.M{"validatorIndex": validator.ID}, page, limit)
+}
+
+func findValidatorEvents(filter bson.M, page, limit int64) (*models.ValidatorEventsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 25
+	}
+
+	total, err := configs.ValidatorEventsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count validator events: %v", err)
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "epoch", Value: -1}, {Key: "validatorIndex", Value: 1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
+
+	cursor, err := configs.ValidatorEventsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validator events: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	events := make([]models.ValidatorEvent, 0)
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode validator events: %v", err)
+	}
+
+	return &models.ValidatorEventsResponse{
+		Events: events,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	}, nil
+}
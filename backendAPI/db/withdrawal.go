@@ -0,0 +1,120 @@
+package db
+
+import (
+	"backendAPI/configs"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetValidatorsByWithdrawalAddress returns every validator whose 0x01
+// withdrawal credentials point at the address, with status and balances
+func GetValidatorsByWithdrawalAddress(address string) (*models.AddressValidatorsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	address = normalizeAddress(address)
+
+	cursor, err := configs.ValidatorsCollections.Find(ctx, bson.M{"withdrawalAddress": address},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query validators by withdrawal address: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var validators []models.ValidatorRecord
+	if err := cursor.All(ctx, &validators); err != nil {
+		return nil, fmt.Errorf("failed to decode validators: %v", err)
+	}
+
+	response := &models.AddressValidatorsResponse{
+		Address:               address,
+		Validators:            make([]models.AddressValidator, 0, len(validators)),
+		TotalEffectiveBalance: "0",
+		TotalBalance:          "0",
+	}
+	if len(validators) == 0 {
+		return response, nil
+	}
+
+	currentEpoch, err := getCurrentEpoch()
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]int64, 0, len(validators))
+	for _, v := range validators {
+		indices = append(indices, v.ID)
+	}
+	balances, balanceEpoch, err := getLatestBalances(ctx, indices)
+	if err != nil {
+		return nil, err
+	}
+
+	totalEffective := big.NewInt(0)
+	totalBalance := big.NewInt(0)
+	for _, v := range validators {
+		entry := models.AddressValidator{
+			Index:            v.Index,
+			PublicKeyHex:     v.PublicKeyHex,
+			Status:           getValidatorStatus(v.ActivationEpoch, v.ExitEpoch, v.Slashed, currentEpoch),
+			EffectiveBalance: v.EffectiveBalance,
+			ActivationEpoch:  v.ActivationEpoch,
+			ExitEpoch:        v.ExitEpoch,
+		}
+		if effective, ok := new(big.Int).SetString(v.EffectiveBalance, 10); ok {
+			totalEffective.Add(totalEffective, effective)
+		}
+		if balance, ok := balances[v.ID]; ok {
+			entry.Balance = balance
+			entry.BalanceEpoch = balanceEpoch
+			if b, ok := new(big.Int).SetString(balance, 10); ok {
+				totalBalance.Add(totalBalance, b)
+			}
+		}
+		response.Validators = append(response.Validators, entry)
+	}
+
+	response.Count = len(response.Validators)
+	response.TotalEffectiveBalance = totalEffective.String()
+	response.TotalBalance = totalBalance.String()
+	return response, nil
+}
+
+// getLatestBalances returns the balances of the given validators from the most
+// recent complete balance snapshot, keyed by validator index
+func getLatestBalances(ctx context.Context, indices []int64) (map[int64]string, int64, error) {
+	balances := make(map[int64]string, len(indices))
+
+	var latest models.NetworkRewardsRecord
+	err := configs.NetworkRewardsCollection.FindOne(ctx, bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "epoch", Value: -1}})).Decode(&latest)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return balances, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to get latest balance snapshot: %v", err)
+	}
+
+	cursor, err := configs.ValidatorBalancesCollection.Find(ctx,
+		bson.M{"epoch": latest.Epoch, "validatorIndex": bson.M{"$in": indices}})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get validator balances: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []models.ValidatorBalanceSnapshot
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode validator balances: %v", err)
+	}
+	for _, s := range snapshots {
+		balances[s.ValidatorIndex] = s.Balance
+	}
+	return balances, latest.Epoch, nil
+}
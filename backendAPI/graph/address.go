@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"backendAPI/db"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// addressResolver resolves an account or contract by Z-prefix lowercase address
+type addressResolver struct {
+	address string
+}
+
+func newAddressResolver(address string) *addressResolver {
+	return &addressResolver{address: addressKey(address)}
+}
+
+func (r *addressResolver) Address() string {
+	return r.address
+}
+
+func (r *addressResolver) Balance(ctx context.Context) (string, error) {
+	record, ok, err := loadersFrom(ctx).addresses.load(ctx, r.address)
+	if err != nil || !ok {
+		return "0", err
+	}
+	return record.BalanceWei, nil
+}
+
+func (r *addressResolver) IsContract(ctx context.Context) (bool, error) {
+	record, _, err := loadersFrom(ctx).addresses.load(ctx, r.address)
+	return record.IsContract, err
+}
+
+func (r *addressResolver) Label(ctx context.Context) (*string, error) {
+	label, ok, err := loadersFrom(ctx).labels.load(ctx, r.address)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &label.Name, nil
+}
+
+func (r *addressResolver) Token(ctx context.Context) (*tokenResolver, error) {
+	contract, ok, err := loadersFrom(ctx).contracts.load(ctx, r.address)
+	if err != nil || !ok || !contract.IsToken {
+		return nil, err
+	}
+	return &tokenResolver{contract: contract}, nil
+}
+
+func (r *addressResolver) Transactions(ctx context.Context, args pageArgs) (*transactionPageResolver, error) {
+	limit, cursor, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	page, _, err := loadersFrom(ctx).addressTransactions.load(ctx, r.page(limit, cursor))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transactions: %v", err)
+	}
+
+	items := make([]*transactionResolver, 0, len(page.Items))
+	for _, tx := range page.Items {
+		items = append(items, &transactionResolver{hash: strings.ToLower(tx.TxHash)})
+	}
+	return &transactionPageResolver{items: items, next: page.Next}, nil
+}
+
+func (r *addressResolver) TokenBalances(ctx context.Context) ([]*tokenBalanceResolver, error) {
+	balances, _, err := loadersFrom(ctx).tokenBalances.load(ctx, r.address)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*tokenBalanceResolver, 0, len(balances))
+	for _, balance := range balances {
+		resolvers = append(resolvers, &tokenBalanceResolver{balance: balance})
+	}
+	return resolvers, nil
+}
+
+func (r *addressResolver) TokenTransfers(ctx context.Context, args pageArgs) (*tokenTransferPageResolver, error) {
+	limit, cursor, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	page, _, err := loadersFrom(ctx).addressTokenTransfers.load(ctx, r.page(limit, cursor))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token transfers: %v", err)
+	}
+	return newTokenTransferPage(ctx, page.Items, page.Next), nil
+}
+
+func (r *addressResolver) Validators(ctx context.Context) ([]*validatorResolver, error) {
+	records, _, err := loadersFrom(ctx).addressValidators.load(ctx, r.address)
+	if err != nil {
+		return nil, err
+	}
+
+	validators := make([]*validatorResolver, 0, len(records))
+	for _, v := range records {
+		validators = append(validators, &validatorResolver{index: v.ID})
+	}
+	return validators, nil
+}
+
+// page is the loader key of a page of the address's activity. The cursor was
+// decoded by pageArgs, so it is encoded again to serve as a comparable key.
+func (r *addressResolver) page(limit int, cursor *db.PageCursor) db.AddressPage {
+	page := db.AddressPage{Address: r.address, Limit: limit}
+	if cursor != nil {
+		page.Cursor = db.EncodeCursor(*cursor)
+	}
+	return page
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"backendAPI/models"
+	"context"
+	"strings"
+)
+
+// blockResolver resolves a stored block by number
+type blockResolver struct {
+	number int64
+}
+
+func (r *blockResolver) load(ctx context.Context) (models.ZondUint64Version, error) {
+	block, _, err := loadersFrom(ctx).blocks.load(ctx, r.number)
+	return block, err
+}
+
+func (r *blockResolver) Number() Long {
+	return Long(r.number)
+}
+
+func (r *blockResolver) Hash(ctx context.Context) (string, error) {
+	block, err := r.load(ctx)
+	return block.Result.Hash, err
+}
+
+func (r *blockResolver) ParentHash(ctx context.Context) (string, error) {
+	block, err := r.load(ctx)
+	return block.Result.ParentHash, err
+}
+
+func (r *blockResolver) Parent(ctx context.Context) (*blockResolver, error) {
+	if r.number == 0 {
+		return nil, nil
+	}
+	if _, ok, err := loadersFrom(ctx).blocks.load(ctx, r.number-1); err != nil || !ok {
+		return nil, err
+	}
+	return &blockResolver{number: r.number - 1}, nil
+}
+
+func (r *blockResolver) Timestamp(ctx context.Context) (Long, error) {
+	block, err := r.load(ctx)
+	return Long(quantity(block.Result.Timestamp)), err
+}
+
+func (r *blockResolver) GasUsed(ctx context.Context) (string, error) {
+	block, err := r.load(ctx)
+	return decimal(block.Result.GasUsed), err
+}
+
+func (r *blockResolver) GasLimit(ctx context.Context) (string, error) {
+	block, err := r.load(ctx)
+	return decimal(block.Result.GasLimit), err
+}
+
+func (r *blockResolver) BaseFeePerGas(ctx context.Context) (*string, error) {
+	block, err := r.load(ctx)
+	if err != nil || block.Result.BaseFeePerGas == "" {
+		return nil, err
+	}
+	baseFee := decimal(block.Result.BaseFeePerGas)
+	return &baseFee, nil
+}
+
+func (r *blockResolver) Size(ctx context.Context) (Long, error) {
+	block, err := r.load(ctx)
+	return Long(quantity(block.Result.Size)), err
+}
+
+func (r *blockResolver) TransactionCount(ctx context.Context) (int32, error) {
+	block, err := r.load(ctx)
+	return int32(len(block.Result.Transactions)), err
+}
+
+func (r *blockResolver) Transactions(ctx context.Context) ([]*transactionResolver, error) {
+	block, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The block holds its transactions, so they never need a lookup of their own
+	l := loadersFrom(ctx)
+	transactions := make([]*transactionResolver, 0, len(block.Result.Transactions))
+	for _, tx := range block.Result.Transactions {
+		hash := strings.ToLower(tx.Hash)
+		l.transactions.prime(hash, tx)
+		l.wantTransaction(tx)
+		transactions = append(transactions, &transactionResolver{hash: hash})
+	}
+	return transactions, nil
+}
+
+func (r *blockResolver) Slot(ctx context.Context) (*Long, error) {
+	block, err := r.load(ctx)
+	if err != nil || block.Slot == nil {
+		return nil, err
+	}
+	slot := Long(*block.Slot)
+	return &slot, nil
+}
+
+func (r *blockResolver) Proposer(ctx context.Context) (*validatorResolver, error) {
+	block, err := r.load(ctx)
+	if err != nil || block.ProposerIndex == nil {
+		return nil, err
+	}
+	if _, ok, err := loadersFrom(ctx).validators.load(ctx, *block.ProposerIndex); err != nil || !ok {
+		return nil, err
+	}
+	return &validatorResolver{index: *block.ProposerIndex}, nil
+}
+
+// blockPageResolver resolves a page of blocks
+type blockPageResolver struct {
+	items []*blockResolver
+	next  string
+}
+
+func (r *blockPageResolver) Items() []*blockResolver {
+	return r.items
+}
+
+func (r *blockPageResolver) NextCursor() *string {
+	return nextCursor(r.next)
+}
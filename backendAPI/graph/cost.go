@@ -0,0 +1,539 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/types"
+)
+
+const (
+	// maxCost limits the estimated number of fields a query resolves
+	maxCost = 10000
+	// listCost is the estimated length of lists that are not paged
+	listCost = 25
+)
+
+// CheckCost estimates the fields an operation resolves before it runs and
+// returns an error when there are more than maxCost. Every field counts
+// once; the fields below a paged field count once per item of its limit and
+// those below other lists listCost times, so nested lists multiply. Queries
+// whose cost cannot be estimated are refused as well.
+func CheckCost(schema *graphql.Schema, query, operationName string, variables map[string]interface{}) error {
+	cost, err := queryCost(schema, query, operationName, variables)
+	if err != nil {
+		return err
+	}
+	if cost > maxCost {
+		return fmt.Errorf("query cost exceeds the limit of %d fields; request smaller pages or fewer nested lists", maxCost)
+	}
+	return nil
+}
+
+// queryCost returns the estimated cost of an operation, capped just above
+// maxCost. It fails when the query does not parse or has no such operation.
+func queryCost(schema *graphql.Schema, query, operationName string, variables map[string]interface{}) (int, error) {
+	doc, err := parseCostDocument(query)
+	if err != nil {
+		return 0, fmt.Errorf("query could not be parsed: %v", err)
+	}
+	c := &costCounter{schema: schema.ASTSchema(), doc: doc, variables: variables, visiting: make(map[string]bool)}
+	return c.operation(operationName)
+}
+
+// costCounter adds up the cost of the operations of a document
+type costCounter struct {
+	schema    *types.Schema
+	doc       *costDocument
+	variables map[string]interface{}
+	defaults  map[string]costValue
+	visiting  map[string]bool
+}
+
+// operation returns the cost of the named operation, or of the costliest
+// one when no name is given
+func (c *costCounter) operation(name string) (int, error) {
+	cost, found := 0, false
+	for _, op := range c.doc.operations {
+		if name != "" && op.name != name {
+			continue
+		}
+		root, ok := c.schema.EntryPoints[op.kind]
+		if !ok {
+			return 0, fmt.Errorf("%s operations are not supported", op.kind)
+		}
+		c.defaults = op.defaults
+		cost, found = max(cost, c.selections(op.selections, root, 0)), true
+	}
+	if !found {
+		if name != "" {
+			return 0, fmt.Errorf("no operation named %q", name)
+		}
+		return 0, errors.New("no operation found")
+	}
+	return cost, nil
+}
+
+// selections returns the cost of a selection set on a type. pageSize is the
+// limit of the paged field the set belongs to, or 0 outside pages.
+func (c *costCounter) selections(selections []costSelection, parent types.NamedType, pageSize int) int {
+	total := 0
+	for _, s := range selections {
+		switch {
+		case s.spread != "":
+			fragment, ok := c.doc.fragments[s.spread]
+			if !ok || c.visiting[s.spread] {
+				continue
+			}
+			c.visiting[s.spread] = true
+			total = addCost(total, c.selections(fragment.selections, c.namedType(fragment.on, parent), pageSize))
+			delete(c.visiting, s.spread)
+		case s.field == "":
+			total = addCost(total, c.selections(s.selections, c.namedType(s.on, parent), pageSize))
+		default:
+			total = addCost(total, c.field(s, parent, pageSize))
+		}
+	}
+	return total
+}
+
+// field returns the cost of a field and the fields selected below it
+func (c *costCounter) field(s costSelection, parent types.NamedType, pageSize int) int {
+	object, ok := parent.(*types.ObjectTypeDefinition)
+	if !ok {
+		return 1
+	}
+	def := object.Fields.Get(s.field)
+	if def == nil {
+		return 1
+	}
+
+	typ, isList := def.Type, false
+	for {
+		if list, ok := typ.(*types.List); ok {
+			typ, isList = list.OfType, true
+		} else if nonNull, ok := typ.(*types.NonNull); ok {
+			typ = nonNull.OfType
+		} else {
+			break
+		}
+	}
+	child, ok := typ.(types.NamedType)
+	if !ok {
+		return 1
+	}
+
+	multiplier, childPageSize := 1, 0
+	if limit := def.Arguments.Get("limit"); limit != nil {
+		childPageSize = c.limit(s.args["limit"], limit)
+	} else if isList {
+		multiplier = listCost
+		if pageSize > 0 {
+			multiplier = pageSize
+		}
+	}
+	return addCost(1, multiplyCost(multiplier, c.selections(s.selections, child, childPageSize)))
+}
+
+// limit returns the page size a limit argument resolves to, bounded like pageArgs
+func (c *costCounter) limit(arg *costValue, def *types.InputValueDefinition) int {
+	var value interface{}
+	if def.Default != nil {
+		value = def.Default.Deserialize(nil)
+	}
+	if arg != nil {
+		if arg.variable == "" {
+			value = arg.literal
+		} else if v, ok := c.variables[arg.variable]; ok {
+			value = v
+		} else if d, ok := c.defaults[arg.variable]; ok {
+			value = d.literal
+		}
+	}
+
+	limit := 0
+	switch v := value.(type) {
+	case int32:
+		limit = int(v)
+	case int:
+		limit = v
+	case float64:
+		limit = int(min(v, maxLimit+1))
+	case json.Number:
+		n, _ := v.Int64()
+		limit = int(min(n, maxLimit+1))
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		limit = int(min(n, maxLimit+1))
+	}
+	if limit <= 0 {
+		return defaultLimit
+	}
+	return min(limit, maxLimit)
+}
+
+// namedType returns the schema type of a type condition, or fallback without one
+func (c *costCounter) namedType(name string, fallback types.NamedType) types.NamedType {
+	if t, ok := c.schema.Types[name]; ok {
+		return t
+	}
+	return fallback
+}
+
+// addCost and multiplyCost saturate just above maxCost so deep queries cannot overflow
+func addCost(a, b int) int {
+	return min(a+b, maxCost+1)
+}
+
+func multiplyCost(a, b int) int {
+	if a > 0 && b > (maxCost+1)/a {
+		return maxCost + 1
+	}
+	return min(a*b, maxCost+1)
+}
+
+// The types below hold the parts of a query document that the cost depends on
+
+type costDocument struct {
+	operations []costOperation
+	fragments  map[string]costFragment
+}
+
+type costOperation struct {
+	kind       string
+	name       string
+	defaults   map[string]costValue
+	selections []costSelection
+}
+
+type costFragment struct {
+	on         string
+	selections []costSelection
+}
+
+// costSelection is a field when field is set, a fragment spread when spread
+// is set and an inline fragment otherwise
+type costSelection struct {
+	field      string
+	args       map[string]*costValue
+	spread     string
+	on         string
+	selections []costSelection
+}
+
+// costValue is a variable reference or the text of a literal
+type costValue struct {
+	variable string
+	literal  string
+}
+
+var errCostSyntax = errors.New("syntax error")
+
+// costParser reads query documents. It accepts every valid document but
+// not only valid ones, since the schema validates the query afterwards;
+// documents it rejects are never run.
+type costParser struct {
+	src string
+	pos int
+}
+
+func parseCostDocument(query string) (*costDocument, error) {
+	p := &costParser{src: query}
+	doc := &costDocument{fragments: make(map[string]costFragment)}
+	for p.peek() != "" {
+		switch token := p.peek(); token {
+		case "{", "query", "mutation", "subscription":
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case "fragment":
+			p.next()
+			name := p.next()
+			if p.next() != "on" {
+				return nil, errCostSyntax
+			}
+			on := p.next()
+			if err := p.directives(); err != nil {
+				return nil, err
+			}
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[name] = costFragment{on: on, selections: selections}
+		default:
+			return nil, errCostSyntax
+		}
+	}
+	return doc, nil
+}
+
+func (p *costParser) operation() (costOperation, error) {
+	op := costOperation{kind: "query", defaults: make(map[string]costValue)}
+	if p.peek() != "{" {
+		op.kind = p.next()
+		if isCostName(p.peek()) {
+			op.name = p.next()
+		}
+		if p.peek() == "(" {
+			p.next()
+			for p.peek() != ")" {
+				if p.next() != "$" {
+					return op, errCostSyntax
+				}
+				name := p.next()
+				if p.next() != ":" {
+					return op, errCostSyntax
+				}
+				if err := p.skipType(); err != nil {
+					return op, err
+				}
+				if p.peek() == "=" {
+					p.next()
+					value, err := p.value()
+					if err != nil {
+						return op, err
+					}
+					op.defaults[name] = value
+				}
+				if err := p.directives(); err != nil {
+					return op, err
+				}
+			}
+			p.next()
+		}
+		if err := p.directives(); err != nil {
+			return op, err
+		}
+	}
+	selections, err := p.selectionSet()
+	op.selections = selections
+	return op, err
+}
+
+func (p *costParser) selectionSet() ([]costSelection, error) {
+	if p.next() != "{" {
+		return nil, errCostSyntax
+	}
+	var selections []costSelection
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			return nil, errCostSyntax
+		}
+		selection, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	p.next()
+	return selections, nil
+}
+
+func (p *costParser) selection() (costSelection, error) {
+	var s costSelection
+	if p.peek() == "..." {
+		p.next()
+		switch token := p.peek(); {
+		case token == "on":
+			p.next()
+			s.on = p.next()
+		case isCostName(token):
+			s.spread = p.next()
+			return s, p.directives()
+		}
+		if err := p.directives(); err != nil {
+			return s, err
+		}
+		selections, err := p.selectionSet()
+		s.selections = selections
+		return s, err
+	}
+
+	s.field = p.next()
+	if !isCostName(s.field) {
+		return s, errCostSyntax
+	}
+	if p.peek() == ":" {
+		p.next()
+		s.field = p.next()
+	}
+	if p.peek() == "(" {
+		p.next()
+		s.args = make(map[string]*costValue)
+		for p.peek() != ")" {
+			name := p.next()
+			if p.next() != ":" {
+				return s, errCostSyntax
+			}
+			value, err := p.value()
+			if err != nil {
+				return s, err
+			}
+			s.args[name] = &value
+		}
+		p.next()
+	}
+	if err := p.directives(); err != nil {
+		return s, err
+	}
+	if p.peek() == "{" {
+		selections, err := p.selectionSet()
+		s.selections = selections
+		return s, err
+	}
+	return s, nil
+}
+
+func (p *costParser) value() (costValue, error) {
+	switch token := p.next(); token {
+	case "$":
+		return costValue{variable: p.next()}, nil
+	case "[":
+		for p.peek() != "]" {
+			if _, err := p.value(); err != nil {
+				return costValue{}, err
+			}
+		}
+		p.next()
+	case "{":
+		for p.peek() != "}" {
+			p.next()
+			if p.next() != ":" {
+				return costValue{}, errCostSyntax
+			}
+			if _, err := p.value(); err != nil {
+				return costValue{}, err
+			}
+		}
+		p.next()
+	case "", "]", "}", "(", ")", ":", "=", "@", "!", "...":
+		return costValue{}, errCostSyntax
+	default:
+		return costValue{literal: token}, nil
+	}
+	return costValue{}, nil
+}
+
+func (p *costParser) directives() error {
+	for p.peek() == "@" {
+		p.next()
+		p.next()
+		if p.peek() == "(" {
+			p.next()
+			for p.peek() != ")" {
+				p.next()
+				if p.next() != ":" {
+					return errCostSyntax
+				}
+				if _, err := p.value(); err != nil {
+					return err
+				}
+			}
+			p.next()
+		}
+	}
+	return nil
+}
+
+func (p *costParser) skipType() error {
+	if p.peek() == "[" {
+		p.next()
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if p.next() != "]" {
+			return errCostSyntax
+		}
+	} else if !isCostName(p.next()) {
+		return errCostSyntax
+	}
+	if p.peek() == "!" {
+		p.next()
+	}
+	return nil
+}
+
+// peek returns the next token without consuming it, or "" at the end
+func (p *costParser) peek() string {
+	pos := p.pos
+	token := p.next()
+	p.pos = pos
+	return token
+}
+
+// next consumes and returns the next token, or "" at the end. Strings are
+// returned with their quotes so they cannot be mistaken for names.
+func (p *costParser) next() string {
+	for p.pos < len(p.src) {
+		switch ch := p.src[p.pos]; {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' || ch == ',':
+			p.pos++
+		case ch == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' && p.src[p.pos] != '\r' {
+				p.pos++
+			}
+		case strings.HasPrefix(p.src[p.pos:], "\ufeff"):
+			p.pos += len("\ufeff")
+		default:
+			return p.token()
+		}
+	}
+	return ""
+}
+
+func (p *costParser) token() string {
+	start := p.pos
+	switch ch := p.src[p.pos]; {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		p.pos += 3
+		for p.pos < len(p.src) && !strings.HasPrefix(p.src[p.pos:], `"""`) {
+			if strings.HasPrefix(p.src[p.pos:], `\"""`) {
+				p.pos += 3
+			}
+			p.pos++
+		}
+		p.pos = min(p.pos+3, len(p.src))
+	case ch == '"':
+		p.pos++
+		for p.pos < len(p.src) && p.src[p.pos] != '"' {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		p.pos = min(p.pos+1, len(p.src))
+	case ch == '-' || ch >= '0' && ch <= '9':
+		// Numbers, with any fraction and exponent
+		p.pos++
+		for p.pos < len(p.src) && (isCostNameChar(p.src[p.pos]) || p.src[p.pos] == '.' || p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+	case isCostNameChar(ch):
+		p.pos++
+		for p.pos < len(p.src) && isCostNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+	default:
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func isCostNameChar(ch byte) bool {
+	return ch == '_' || ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
+
+// isCostName reports whether a token is a name rather than punctuation, a
+// number or a string
+func isCostName(token string) bool {
+	return token != "" && (token[0] == '_' || token[0] >= 'a' && token[0] <= 'z' || token[0] >= 'A' && token[0] <= 'Z')
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// costSchema is the schema without resolvers; the cost only depends on its types
+var costSchema = graphql.MustParseSchema(schema, nil)
+
+func TestQueryCost(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		variables     map[string]interface{}
+		want          int
+	}{
+		{name: "default limit", query: `{ blocks { items { number } } }`, want: 12},
+		{name: "literal limit", query: `{ blocks(limit: 3) { items { number hash } nextCursor } }`, want: 9},
+		{name: "limit above maximum", query: `{ blocks(limit: 1000) { items { number } } }`, want: 102},
+		{name: "negative limit", query: `{ blocks(limit: -5) { items { number } } }`, want: 12},
+		{name: "aliases count separately",
+			query: `{ a: blocks(limit: 3) { items { number } } b: blocks(limit: 5) { items { n: number } } }`,
+			want:  12},
+		{name: "variable limit",
+			query:     `query Q($n: Int) { blocks(limit: $n) { items { number } } }`,
+			variables: map[string]interface{}{"n": float64(50)},
+			want:      52},
+		{name: "variable default",
+			query: `query Q($n: Int = 20) { blocks(limit: $n) { items { number } } }`,
+			want:  22},
+		{name: "variable over default",
+			query:     `query Q($n: Int = 20) { blocks(limit: $n) { items { number } } }`,
+			variables: map[string]interface{}{"n": float64(4)},
+			want:      6},
+		{name: "unset variable uses the schema default",
+			query: `query Q($n: Int) { blocks(limit: $n) { items { number } } }`,
+			want:  12},
+		{name: "variable above maximum",
+			query:     `query Q($n: Int) { blocks(limit: $n) { items { number } } }`,
+			variables: map[string]interface{}{"n": float64(1e9)},
+			want:      102},
+		{name: "fragment spread",
+			query: `{ blocks(limit: 2) { ...page } } fragment page on BlockPage { items { number } }`,
+			want:  4},
+		{name: "inline fragment",
+			query: `{ blocks(limit: 2) { ... on BlockPage { items { number } } } }`,
+			want:  4},
+		{name: "recursive fragment counts once",
+			query: `{ block(number: 1) { ...parents } } fragment parents on Block { number parent { ...parents } }`,
+			want:  3},
+		{name: "unpaged list",
+			query: `{ block(number: 1) { transactions { hash } } }`,
+			want:  27},
+		{name: "nested paged field",
+			query: `{ blocks(limit: 10) { items { transactions { from { transactions(limit: 5) { items { hash } } } } } } }`,
+			want:  2012},
+		{name: "nested lists multiply past the cap",
+			query: `{ blocks(limit: 100) { items { transactions { logs { topics } } } } }`,
+			want:  maxCost + 1},
+		{name: "named operation",
+			query:         `query A { blocks(limit: 1) { items { number } } } query B { blocks(limit: 50) { items { number } } }`,
+			operationName: "A",
+			want:          3},
+		{name: "costliest operation without a name",
+			query: `query A { blocks(limit: 1) { items { number } } } query B { blocks(limit: 50) { items { number } } }`,
+			want:  52},
+		{name: "directives, strings and comments",
+			query: "# blocks\n{ blocks(limit: 2) @include(if: true) { items { number @skip(if: false) } nextCursor } block(hash: \"}\") { hash } }",
+			want:  7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := queryCost(costSchema, tt.query, tt.operationName, tt.variables)
+			if err != nil {
+				t.Fatalf("queryCost() failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("queryCost() = %d, wanted %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCostRejects(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		wantErr       string
+	}{
+		{name: "too costly", query: `{ blocks(limit: 100) { items { transactions { logs { topics } } } } }`, wantErr: "exceeds the limit"},
+		{name: "empty", query: ``, wantErr: "no operation"},
+		{name: "unclosed selection", query: `{ blocks { items { number } }`, wantErr: "could not be parsed"},
+		{name: "stray brace", query: `} { blocks { items { number } } }`, wantErr: "could not be parsed"},
+		{name: "missing argument value", query: `{ blocks(limit: ) { items { number } } }`, wantErr: "could not be parsed"},
+		{name: "bad variable definition", query: `query ($n Int) { blocks(limit: $n) { items { number } } }`, wantErr: "could not be parsed"},
+		{name: "fragment without type", query: `{ block(number: 1) { ...f } } fragment f { number }`, wantErr: "could not be parsed"},
+		{name: "unknown operation", query: `query A { blocks { items { number } } }`, operationName: "B", wantErr: "no operation named"},
+		{name: "mutation", query: `mutation { blocks { items { number } } }`, wantErr: "not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCost(costSchema, tt.query, tt.operationName, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckCost() = %v, wanted an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
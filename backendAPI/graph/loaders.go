@@ -0,0 +1,245 @@
+package graph
+
+import (
+	"backendAPI/db"
+	"backendAPI/models"
+	"context"
+	"strings"
+	"sync"
+)
+
+// loader batches the lookups of one request by key. Keys registered with
+// want are fetched together with the next key that is loaded, and every
+// result, found or not, is kept for the rest of the request. Keys loaded while
+// a fetch is running wait for it and are then fetched together.
+//
+// Whoever creates a list of resolvers registers the keys the items will load,
+// and a fetch registers the keys its results lead to. Nested fields of a list
+// then cost one query per loader instead of one per item. Registering keys
+// never waits for a fetch, so a fetch may register keys on any loader.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	// fetching is held while a batch is fetched, mu while the maps are used
+	fetching sync.Mutex
+	mu       sync.Mutex
+	pending  map[K]bool
+	values   map[K]V
+	done     map[K]bool
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		pending: make(map[K]bool),
+		values:  make(map[K]V),
+		done:    make(map[K]bool),
+	}
+}
+
+// want registers keys to fetch with the next batch
+func (l *loader[K, V]) want(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if !l.done[key] {
+			l.pending[key] = true
+		}
+	}
+}
+
+// prime stores a value that is already known
+func (l *loader[K, V]) prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.values[key] = value
+	l.done[key] = true
+	delete(l.pending, key)
+}
+
+// load returns the value of a key and whether it exists, fetching it with
+// all pending keys unless it was loaded before
+func (l *loader[K, V]) load(ctx context.Context, key K) (V, bool, error) {
+	l.want(key)
+
+	l.fetching.Lock()
+	defer l.fetching.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.done[key] {
+		keys := make([]K, 0, len(l.pending))
+		for k := range l.pending {
+			keys = append(keys, k)
+		}
+
+		// Other keys may be registered while the batch is fetched
+		l.mu.Unlock()
+		values, err := l.fetch(ctx, keys)
+		l.mu.Lock()
+		if err != nil {
+			var zero V
+			return zero, false, err
+		}
+		for _, k := range keys {
+			if value, ok := values[k]; ok {
+				l.values[k] = value
+			}
+			l.done[k] = true
+			delete(l.pending, k)
+		}
+	}
+
+	value, ok := l.values[key]
+	return value, ok, nil
+}
+
+// loaders holds the loaders of one request
+type loaders struct {
+	addressTransactions   *loader[db.AddressPage, db.Page[models.TransactionByAddress]]
+	addressTokenTransfers *loader[db.AddressPage, db.Page[models.TokenTransfer]]
+	addressValidators     *loader[string, []models.ValidatorRecord]
+	blocks                *loader[int64, models.ZondUint64Version]
+	transactions          *loader[string, models.Transaction]
+	receipts              *loader[string, models.Receipt]
+	tokenTransfers        *loader[string, []models.TokenTransfer]
+	tokenBalances         *loader[string, []models.TokenBalance]
+	contracts             *loader[string, models.ContractInfo]
+	validators            *loader[int64, models.ValidatorRecord]
+	addresses             *loader[string, models.AddressRecord]
+	labels                *loader[string, models.AddressLabel]
+}
+
+func newLoaders() *loaders {
+	l := &loaders{}
+
+	l.addresses = newLoader(db.GetAddressesByIDs)
+	l.labels = newLoader(func(ctx context.Context, keys []string) (map[string]models.AddressLabel, error) {
+		return db.GetPublicLabels(keys...), nil
+	})
+	l.validators = newLoader(func(ctx context.Context, keys []int64) (map[int64]models.ValidatorRecord, error) {
+		validators, err := db.GetValidatorsByIndexes(ctx, keys)
+		for _, v := range validators {
+			l.wantAddresses(v.WithdrawalAddress)
+		}
+		return validators, err
+	})
+	l.contracts = newLoader(func(ctx context.Context, keys []string) (map[string]models.ContractInfo, error) {
+		contracts, err := db.GetContractsByAddresses(ctx, keys)
+		for _, contract := range contracts {
+			l.wantAddresses(contract.ContractCreatorAddress)
+		}
+		return contracts, err
+	})
+	l.tokenBalances = newLoader(func(ctx context.Context, keys []string) (map[string][]models.TokenBalance, error) {
+		balances, err := db.GetTokenBalancesByHolders(ctx, keys)
+		for _, holderBalances := range balances {
+			for _, balance := range holderBalances {
+				l.contracts.want(addressKey(balance.ContractAddress))
+			}
+		}
+		return balances, err
+	})
+	l.tokenTransfers = newLoader(func(ctx context.Context, keys []string) (map[string][]models.TokenTransfer, error) {
+		transfers, err := db.GetTokenTransfersByTxHashes(ctx, keys)
+		for _, txTransfers := range transfers {
+			l.wantTransfers(txTransfers)
+		}
+		return transfers, err
+	})
+	l.receipts = newLoader(func(ctx context.Context, keys []string) (map[string]models.Receipt, error) {
+		receipts, err := db.GetReceiptsByHashes(ctx, keys)
+		for _, receipt := range receipts {
+			l.wantAddresses(receipt.ContractAddress)
+			for _, entry := range receipt.Logs {
+				l.wantAddresses(entry.Address)
+			}
+		}
+		return receipts, err
+	})
+	l.blocks = newLoader(func(ctx context.Context, keys []int64) (map[int64]models.ZondUint64Version, error) {
+		blocks, err := db.GetBlocksByNumbers(ctx, keys)
+		for _, block := range blocks {
+			if block.ProposerIndex != nil {
+				l.validators.want(*block.ProposerIndex)
+			}
+		}
+		return blocks, err
+	})
+	l.transactions = newLoader(func(ctx context.Context, keys []string) (map[string]models.Transaction, error) {
+		transactions, err := db.GetTransactionsByHashes(ctx, keys)
+		for _, tx := range transactions {
+			l.wantTransaction(tx)
+			l.blocks.want(quantity(tx.BlockNumber))
+		}
+		return transactions, err
+	})
+	l.addressValidators = newLoader(func(ctx context.Context, keys []string) (map[string][]models.ValidatorRecord, error) {
+		validators, err := db.GetValidatorsByWithdrawalAddresses(ctx, keys)
+		for _, addressValidators := range validators {
+			for _, v := range addressValidators {
+				l.validators.prime(v.ID, v)
+			}
+		}
+		return validators, err
+	})
+	l.addressTokenTransfers = newLoader(func(ctx context.Context, keys []db.AddressPage) (map[db.AddressPage]db.Page[models.TokenTransfer], error) {
+		pages, err := db.GetTokenTransferPages(ctx, keys)
+		for _, page := range pages {
+			l.wantTransfers(page.Items)
+		}
+		return pages, err
+	})
+	l.addressTransactions = newLoader(func(ctx context.Context, keys []db.AddressPage) (map[db.AddressPage]db.Page[models.TransactionByAddress], error) {
+		pages, err := db.GetNonZeroTransactionPages(ctx, keys)
+		for _, page := range pages {
+			for _, tx := range page.Items {
+				l.transactions.want(strings.ToLower(tx.TxHash))
+			}
+		}
+		return pages, err
+	})
+	return l
+}
+
+// wantTransaction registers the keys the fields of a known transaction load
+func (l *loaders) wantTransaction(tx models.Transaction) {
+	l.receipts.want(tx.Hash)
+	l.tokenTransfers.want(tx.Hash)
+	l.wantAddresses(tx.From, tx.To)
+}
+
+// wantTransfers registers the keys the fields of token transfers load
+func (l *loaders) wantTransfers(transfers []models.TokenTransfer) {
+	for _, transfer := range transfers {
+		l.contracts.want(addressKey(transfer.ContractAddress))
+		l.wantAddresses(transfer.From, transfer.To)
+	}
+}
+
+// wantAddresses registers addresses and their labels
+func (l *loaders) wantAddresses(addresses ...string) {
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		key := addressKey(address)
+		l.addresses.want(key)
+		l.labels.want(key)
+	}
+}
+
+type loadersKey struct{}
+
+// WithLoaders returns a context carrying fresh loaders for one request
+func WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders())
+}
+
+// loadersFrom returns the loaders of the request
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"backendAPI/db"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// queryResolver resolves the root Query type
+type queryResolver struct{}
+
+func (q *queryResolver) Block(ctx context.Context, args struct {
+	Number *Long
+	Hash   *string
+}) (*blockResolver, error) {
+	var number int64
+	switch {
+	case args.Number != nil:
+		number = int64(*args.Number)
+	case args.Hash != nil:
+		found, err := db.ReturnHashToBlockNumber(strings.ToLower(*args.Hash))
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		number = int64(found)
+	default:
+		return nil, errors.New("block requires a number or hash")
+	}
+
+	if _, ok, err := loadersFrom(ctx).blocks.load(ctx, number); err != nil || !ok {
+		return nil, err
+	}
+	return &blockResolver{number: number}, nil
+}
+
+func (q *queryResolver) Blocks(ctx context.Context, args pageArgs) (*blockPageResolver, error) {
+	limit, cursor, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	blocks, next, err := db.ReturnLatestBlocks(1, limit, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blocks: %v", err)
+	}
+
+	l := loadersFrom(ctx)
+	items := make([]*blockResolver, 0, len(blocks))
+	for _, block := range blocks {
+		number := quantity(block.Number)
+		l.blocks.want(number)
+		items = append(items, &blockResolver{number: number})
+	}
+	return &blockPageResolver{items: items, next: next}, nil
+}
+
+func (q *queryResolver) Transaction(ctx context.Context, args struct{ Hash string }) (*transactionResolver, error) {
+	hash := strings.ToLower(args.Hash)
+	if _, ok, err := loadersFrom(ctx).transactions.load(ctx, hash); err != nil || !ok {
+		return nil, err
+	}
+	return &transactionResolver{hash: hash}, nil
+}
+
+func (q *queryResolver) Address(ctx context.Context, args struct{ Address string }) (*addressResolver, error) {
+	if !isAddress(args.Address) {
+		return nil, fmt.Errorf("invalid address %q", args.Address)
+	}
+	return newAddressResolver(args.Address), nil
+}
+
+func (q *queryResolver) Token(ctx context.Context, args struct{ Address string }) (*tokenResolver, error) {
+	if !isAddress(args.Address) {
+		return nil, fmt.Errorf("invalid address %q", args.Address)
+	}
+	contract, ok, err := loadersFrom(ctx).contracts.load(ctx, addressKey(args.Address))
+	if err != nil || !ok || !contract.IsToken {
+		return nil, err
+	}
+	return &tokenResolver{contract: contract}, nil
+}
+
+func (q *queryResolver) Validator(ctx context.Context, args struct{ Index Long }) (*validatorResolver, error) {
+	index := int64(args.Index)
+	if _, ok, err := loadersFrom(ctx).validators.load(ctx, index); err != nil || !ok {
+		return nil, err
+	}
+	return &validatorResolver{index: index}, nil
+}
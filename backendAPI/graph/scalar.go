@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"backendAPI/db"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// defaultLimit and maxLimit bound the page sizes of list fields
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// Long is a 64-bit integer. GraphQL Int literals only hold 32 bits, so
+// larger inputs may be given as decimal or 0x-prefixed strings.
+type Long int64
+
+// ImplementsGraphQLType binds Long to the scalar of the same name
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL parses an input value
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch value := input.(type) {
+	case int32:
+		*l = Long(value)
+	case int64:
+		*l = Long(value)
+	case float64:
+		*l = Long(value)
+	case string:
+		number, err := strconv.ParseInt(value, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Long %q", value)
+		}
+		*l = Long(number)
+	default:
+		return fmt.Errorf("invalid Long input %T", input)
+	}
+	return nil
+}
+
+// addressKey is the Z-prefix lowercase form the loaders key addresses by
+func addressKey(address string) string {
+	if address == "" {
+		return ""
+	}
+	lower := strings.ToLower(address)
+	if strings.HasPrefix(lower, "0x") {
+		lower = lower[2:]
+	} else if strings.HasPrefix(lower, "z") {
+		lower = lower[1:]
+	}
+	return "Z" + lower
+}
+
+// isAddress reports whether a string is a Z- or 0x-prefixed 20-byte address
+func isAddress(address string) bool {
+	key := addressKey(address)
+	if len(key) != 41 {
+		return false
+	}
+	for _, c := range key[1:] {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// quantity parses a 0x-prefixed hex or decimal number, returning 0 when
+// it is empty or malformed
+func quantity(value string) int64 {
+	number, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// decimal converts a 0x-prefixed quantity to a decimal string; other values
+// are returned unchanged
+func decimal(value string) string {
+	if !strings.HasPrefix(value, "0x") {
+		return value
+	}
+	number, ok := new(big.Int).SetString(value[2:], 16)
+	if !ok {
+		return value
+	}
+	return number.String()
+}
+
+// pageArgs are the arguments of paged list fields
+type pageArgs struct {
+	Limit  int32
+	Cursor *string
+}
+
+// page returns the page size and decoded cursor of the arguments
+func (args pageArgs) page() (int, *db.PageCursor, error) {
+	limit := int(args.Limit)
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if args.Cursor == nil {
+		return limit, nil, nil
+	}
+	cursor, err := db.DecodeCursor(*args.Cursor)
+	return limit, cursor, err
+}
+
+// nextCursor returns the cursor of the next page, or nil after the last page
+func nextCursor(next string) *string {
+	if next == "" {
+		return nil
+	}
+	return &next
+}
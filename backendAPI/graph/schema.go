@@ -0,0 +1,187 @@
+// Package graph serves a GraphQL API over the indexed chain data. Resolvers
+// read through the db package and batch their lookups per request with the
+// loaders in loaders.go.
+package graph
+
+import (
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// maxDepth limits how deeply queries may nest fields
+	maxDepth = 10
+	// maxParallelism is the number of resolvers a request runs at once
+	maxParallelism = 20
+)
+
+const schema = `
+schema {
+	query: Query
+}
+
+"A 64-bit integer, for block numbers and timestamps. Values beyond 32 bits may also be given as strings."
+scalar Long
+
+type Query {
+	"A block by number or hash"
+	block(number: Long, hash: String): Block
+	"Blocks, newest first"
+	blocks(limit: Int = 10, cursor: String): BlockPage!
+	"A mined transaction by hash"
+	transaction(hash: String!): Transaction
+	"An address, with a Z or 0x prefix"
+	address(address: String!): Address
+	"A token contract"
+	token(address: String!): Token
+	"A validator by index"
+	validator(index: Long!): Validator
+}
+
+type Block {
+	number: Long!
+	hash: String!
+	parentHash: String!
+	parent: Block
+	"Unix seconds"
+	timestamp: Long!
+	gasUsed: String!
+	gasLimit: String!
+	"In wei"
+	baseFeePerGas: String
+	size: Long!
+	transactionCount: Int!
+	transactions: [Transaction!]!
+	slot: Long
+	proposer: Validator
+}
+
+type BlockPage {
+	items: [Block!]!
+	"Pass as cursor to continue after the last item; null after the last page"
+	nextCursor: String
+}
+
+type Transaction {
+	hash: String!
+	block: Block
+	blockNumber: Long!
+	transactionIndex: Int!
+	from: Address!
+	"Null for contract creations"
+	to: Address
+	"In wei"
+	value: String!
+	gas: String!
+	gasPrice: String!
+	nonce: Long!
+	input: String!
+	"1 for success, 0 for failure, null without a stored receipt"
+	status: Int
+	gasUsed: String
+	"The contract a creation deployed"
+	createdContract: Address
+	logs: [Log!]!
+	tokenTransfers: [TokenTransfer!]!
+}
+
+type TransactionPage {
+	items: [Transaction!]!
+	nextCursor: String
+}
+
+type Log {
+	address: Address!
+	topics: [String!]!
+	data: String!
+	logIndex: Int!
+	blockNumber: Long!
+	transaction: Transaction!
+}
+
+type Address {
+	"Z-prefix lowercase address"
+	address: String!
+	"In wei"
+	balance: String!
+	isContract: Boolean!
+	"Public label, if any"
+	label: String
+	"The token deployed at this address, if any"
+	token: Token
+	"Transactions that moved value, newest first"
+	transactions(limit: Int = 10, cursor: String): TransactionPage!
+	tokenBalances: [TokenBalance!]!
+	"Token transfers sent or received, newest first"
+	tokenTransfers(limit: Int = 10, cursor: String): TokenTransferPage!
+	"Validators withdrawing to this address"
+	validators: [Validator!]!
+}
+
+type Token {
+	address: String!
+	contract: Address!
+	name: String!
+	symbol: String!
+	decimals: Int!
+	"In the token's smallest unit"
+	totalSupply: String!
+	creator: Address
+	creationTransaction: Transaction
+	"Holders, largest balance first"
+	holders(limit: Int = 10, cursor: String): TokenHolderPage!
+	"Transfers, newest first"
+	transfers(limit: Int = 10, cursor: String): TokenTransferPage!
+}
+
+type TokenBalance {
+	token: Token!
+	holder: Address!
+	"In the token's smallest unit"
+	balance: String!
+}
+
+type TokenHolderPage {
+	items: [TokenBalance!]!
+	total: Int!
+	nextCursor: String
+}
+
+type TokenTransfer {
+	token: Token!
+	from: Address!
+	to: Address!
+	"In the token's smallest unit"
+	amount: String!
+	transaction: Transaction
+	blockNumber: Long!
+	"Null for transfers not emitted as a log"
+	logIndex: Int
+	timestamp: Long!
+}
+
+type TokenTransferPage {
+	items: [TokenTransfer!]!
+	nextCursor: String
+}
+
+type Validator {
+	index: Long!
+	publicKey: String!
+	"pending, active, exited or slashed"
+	status: String!
+	"In gwei"
+	effectiveBalance: String!
+	slashed: Boolean!
+	activationEpoch: String!
+	exitEpoch: String!
+	withdrawalAddress: Address
+}
+`
+
+// NewSchema parses the schema and binds it to the resolvers. Requests must
+// carry loaders from WithLoaders in their context.
+func NewSchema() *graphql.Schema {
+	return graphql.MustParseSchema(schema, &queryResolver{},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism))
+}
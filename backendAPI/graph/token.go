@@ -0,0 +1,224 @@
+package graph
+
+import (
+	"backendAPI/db"
+	"backendAPI/models"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// tokenResolver resolves a token contract
+type tokenResolver struct {
+	contract models.ContractInfo
+}
+
+// loadToken returns the resolver of a token contract. Tokens without a stored
+// contract fall back to the metadata copied onto their balances and transfers.
+func loadToken(ctx context.Context, address string, fallback models.ContractInfo) (*tokenResolver, error) {
+	contract, ok, err := loadersFrom(ctx).contracts.load(ctx, addressKey(address))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		contract = fallback
+		contract.ContractAddress = address
+	}
+	return &tokenResolver{contract: contract}, nil
+}
+
+func (r *tokenResolver) Address() string {
+	return addressKey(r.contract.ContractAddress)
+}
+
+func (r *tokenResolver) Contract() *addressResolver {
+	return newAddressResolver(r.contract.ContractAddress)
+}
+
+func (r *tokenResolver) Name() string {
+	return r.contract.TokenName
+}
+
+func (r *tokenResolver) Symbol() string {
+	return r.contract.TokenSymbol
+}
+
+func (r *tokenResolver) Decimals() int32 {
+	return int32(r.contract.TokenDecimals)
+}
+
+func (r *tokenResolver) TotalSupply() string {
+	if r.contract.TotalSupply == "" {
+		return "0"
+	}
+	return decimal(r.contract.TotalSupply)
+}
+
+func (r *tokenResolver) Creator() *addressResolver {
+	if r.contract.ContractCreatorAddress == "" {
+		return nil
+	}
+	return newAddressResolver(r.contract.ContractCreatorAddress)
+}
+
+func (r *tokenResolver) CreationTransaction(ctx context.Context) (*transactionResolver, error) {
+	if r.contract.CreationTransaction == "" {
+		return nil, nil
+	}
+	hash := strings.ToLower(r.contract.CreationTransaction)
+	if _, ok, err := loadersFrom(ctx).transactions.load(ctx, hash); err != nil || !ok {
+		return nil, err
+	}
+	return &transactionResolver{hash: hash}, nil
+}
+
+func (r *tokenResolver) Holders(ctx context.Context, args pageArgs) (*tokenHolderPageResolver, error) {
+	limit, cursor, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	holders, total, next, err := db.GetTokenHolders(r.contract.ContractAddress, 0, limit, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token holders: %v", err)
+	}
+
+	l := loadersFrom(ctx)
+	items := make([]*tokenBalanceResolver, 0, len(holders))
+	for _, holder := range holders {
+		l.wantAddresses(holder.HolderAddress)
+		items = append(items, &tokenBalanceResolver{balance: holder})
+	}
+	return &tokenHolderPageResolver{items: items, total: total, next: next}, nil
+}
+
+func (r *tokenResolver) Transfers(ctx context.Context, args pageArgs) (*tokenTransferPageResolver, error) {
+	limit, cursor, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	transfers, _, next, err := db.GetTokenTransfers(r.contract.ContractAddress, 0, limit, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token transfers: %v", err)
+	}
+	return newTokenTransferPage(ctx, transfers, next), nil
+}
+
+// tokenBalanceResolver resolves the balance of a holder in a token
+type tokenBalanceResolver struct {
+	balance models.TokenBalance
+}
+
+func (r *tokenBalanceResolver) Token(ctx context.Context) (*tokenResolver, error) {
+	return loadToken(ctx, r.balance.ContractAddress, models.ContractInfo{
+		IsToken:       true,
+		TokenName:     r.balance.Name,
+		TokenSymbol:   r.balance.Symbol,
+		TokenDecimals: uint8(r.balance.Decimals),
+	})
+}
+
+func (r *tokenBalanceResolver) Holder() *addressResolver {
+	return newAddressResolver(r.balance.HolderAddress)
+}
+
+func (r *tokenBalanceResolver) Balance() string {
+	return r.balance.Balance
+}
+
+// tokenHolderPageResolver resolves a page of token holders
+type tokenHolderPageResolver struct {
+	items []*tokenBalanceResolver
+	total int
+	next  string
+}
+
+func (r *tokenHolderPageResolver) Items() []*tokenBalanceResolver {
+	return r.items
+}
+
+func (r *tokenHolderPageResolver) Total() int32 {
+	return int32(r.total)
+}
+
+func (r *tokenHolderPageResolver) NextCursor() *string {
+	return nextCursor(r.next)
+}
+
+// tokenTransferResolver resolves a token transfer
+type tokenTransferResolver struct {
+	transfer models.TokenTransfer
+}
+
+func (r *tokenTransferResolver) Token(ctx context.Context) (*tokenResolver, error) {
+	return loadToken(ctx, r.transfer.ContractAddress, models.ContractInfo{
+		IsToken:       true,
+		TokenName:     r.transfer.TokenName,
+		TokenSymbol:   r.transfer.TokenSymbol,
+		TokenDecimals: uint8(r.transfer.TokenDecimals),
+	})
+}
+
+func (r *tokenTransferResolver) From() *addressResolver {
+	return newAddressResolver(r.transfer.From)
+}
+
+func (r *tokenTransferResolver) To() *addressResolver {
+	return newAddressResolver(r.transfer.To)
+}
+
+func (r *tokenTransferResolver) Amount() string {
+	return r.transfer.Amount
+}
+
+func (r *tokenTransferResolver) Transaction(ctx context.Context) (*transactionResolver, error) {
+	hash := strings.ToLower(r.transfer.TxHash)
+	if _, ok, err := loadersFrom(ctx).transactions.load(ctx, hash); err != nil || !ok {
+		return nil, err
+	}
+	return &transactionResolver{hash: hash}, nil
+}
+
+func (r *tokenTransferResolver) BlockNumber() Long {
+	return Long(quantity(r.transfer.BlockNumber))
+}
+
+func (r *tokenTransferResolver) LogIndex() *int32 {
+	// Transfers found outside the logs are stored with index -1
+	if r.transfer.LogIndex < 0 {
+		return nil
+	}
+	logIndex := int32(r.transfer.LogIndex)
+	return &logIndex
+}
+
+func (r *tokenTransferResolver) Timestamp() Long {
+	return Long(quantity(r.transfer.Timestamp))
+}
+
+// tokenTransferPageResolver resolves a page of token transfers
+type tokenTransferPageResolver struct {
+	items []*tokenTransferResolver
+	next  string
+}
+
+// newTokenTransferPage wraps a page of transfers, registering the keys their
+// fields load
+func newTokenTransferPage(ctx context.Context, transfers []models.TokenTransfer, next string) *tokenTransferPageResolver {
+	l := loadersFrom(ctx)
+	l.wantTransfers(transfers)
+
+	items := make([]*tokenTransferResolver, 0, len(transfers))
+	for _, transfer := range transfers {
+		l.transactions.want(strings.ToLower(transfer.TxHash))
+		items = append(items, &tokenTransferResolver{transfer: transfer})
+	}
+	return &tokenTransferPageResolver{items: items, next: next}
+}
+
+func (r *tokenTransferPageResolver) Items() []*tokenTransferResolver {
+	return r.items
+}
+
+func (r *tokenTransferPageResolver) NextCursor() *string {
+	return nextCursor(r.next)
+}
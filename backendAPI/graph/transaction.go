@@ -0,0 +1,184 @@
+package graph
+
+import (
+	"backendAPI/models"
+	"context"
+	"strings"
+)
+
+// transactionResolver resolves a mined transaction by lowercase hash
+type transactionResolver struct {
+	hash string
+}
+
+func (r *transactionResolver) load(ctx context.Context) (models.Transaction, error) {
+	tx, _, err := loadersFrom(ctx).transactions.load(ctx, r.hash)
+	return tx, err
+}
+
+// receipt returns the stored receipt of the transaction and whether there is one
+func (r *transactionResolver) receipt(ctx context.Context) (models.Receipt, bool, error) {
+	return loadersFrom(ctx).receipts.load(ctx, r.hash)
+}
+
+func (r *transactionResolver) Hash() string {
+	return r.hash
+}
+
+func (r *transactionResolver) Block(ctx context.Context) (*blockResolver, error) {
+	tx, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &blockResolver{number: quantity(tx.BlockNumber)}, nil
+}
+
+func (r *transactionResolver) BlockNumber(ctx context.Context) (Long, error) {
+	tx, err := r.load(ctx)
+	return Long(quantity(tx.BlockNumber)), err
+}
+
+func (r *transactionResolver) TransactionIndex(ctx context.Context) (int32, error) {
+	tx, err := r.load(ctx)
+	return int32(quantity(tx.TransactionIndex)), err
+}
+
+func (r *transactionResolver) From(ctx context.Context) (*addressResolver, error) {
+	tx, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newAddressResolver(tx.From), nil
+}
+
+func (r *transactionResolver) To(ctx context.Context) (*addressResolver, error) {
+	tx, err := r.load(ctx)
+	if err != nil || tx.To == "" {
+		return nil, err
+	}
+	return newAddressResolver(tx.To), nil
+}
+
+func (r *transactionResolver) Value(ctx context.Context) (string, error) {
+	tx, err := r.load(ctx)
+	return decimal(tx.Value), err
+}
+
+func (r *transactionResolver) Gas(ctx context.Context) (string, error) {
+	tx, err := r.load(ctx)
+	return decimal(tx.Gas), err
+}
+
+func (r *transactionResolver) GasPrice(ctx context.Context) (string, error) {
+	tx, err := r.load(ctx)
+	return decimal(tx.GasPrice), err
+}
+
+func (r *transactionResolver) Nonce(ctx context.Context) (Long, error) {
+	tx, err := r.load(ctx)
+	return Long(quantity(tx.Nonce)), err
+}
+
+func (r *transactionResolver) Input(ctx context.Context) (string, error) {
+	tx, err := r.load(ctx)
+	if tx.Data == "" {
+		return "0x", err
+	}
+	return tx.Data, err
+}
+
+func (r *transactionResolver) Status(ctx context.Context) (*int32, error) {
+	receipt, ok, err := r.receipt(ctx)
+	if err != nil || !ok {
+		return nil, err
+	}
+	status := int32(quantity(receipt.Status))
+	return &status, nil
+}
+
+func (r *transactionResolver) GasUsed(ctx context.Context) (*string, error) {
+	receipt, ok, err := r.receipt(ctx)
+	if err != nil || !ok {
+		return nil, err
+	}
+	gasUsed := decimal(receipt.GasUsed)
+	return &gasUsed, nil
+}
+
+func (r *transactionResolver) CreatedContract(ctx context.Context) (*addressResolver, error) {
+	receipt, ok, err := r.receipt(ctx)
+	if err != nil || !ok || receipt.ContractAddress == "" {
+		return nil, err
+	}
+	return newAddressResolver(receipt.ContractAddress), nil
+}
+
+func (r *transactionResolver) Logs(ctx context.Context) ([]*logResolver, error) {
+	receipt, _, err := r.receipt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*logResolver, 0, len(receipt.Logs))
+	for _, entry := range receipt.Logs {
+		logs = append(logs, &logResolver{log: entry})
+	}
+	return logs, nil
+}
+
+func (r *transactionResolver) TokenTransfers(ctx context.Context) ([]*tokenTransferResolver, error) {
+	transfers, _, err := loadersFrom(ctx).tokenTransfers.load(ctx, r.hash)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*tokenTransferResolver, 0, len(transfers))
+	for _, transfer := range transfers {
+		resolvers = append(resolvers, &tokenTransferResolver{transfer: transfer})
+	}
+	return resolvers, nil
+}
+
+// transactionPageResolver resolves a page of transactions
+type transactionPageResolver struct {
+	items []*transactionResolver
+	next  string
+}
+
+func (r *transactionPageResolver) Items() []*transactionResolver {
+	return r.items
+}
+
+func (r *transactionPageResolver) NextCursor() *string {
+	return nextCursor(r.next)
+}
+
+// logResolver resolves a log entry of a receipt
+type logResolver struct {
+	log models.ReceiptLog
+}
+
+func (r *logResolver) Address() *addressResolver {
+	return newAddressResolver(r.log.Address)
+}
+
+func (r *logResolver) Topics() []string {
+	if r.log.Topics == nil {
+		return []string{}
+	}
+	return r.log.Topics
+}
+
+func (r *logResolver) Data() string {
+	return r.log.Data
+}
+
+func (r *logResolver) LogIndex() int32 {
+	return int32(quantity(r.log.LogIndex))
+}
+
+func (r *logResolver) BlockNumber() Long {
+	return Long(quantity(r.log.BlockNumber))
+}
+
+func (r *logResolver) Transaction() *transactionResolver {
+	return &transactionResolver{hash: strings.ToLower(r.log.TransactionHash)}
+}
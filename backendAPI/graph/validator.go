@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"backendAPI/models"
+	"context"
+)
+
+// validatorResolver resolves a validator by index
+type validatorResolver struct {
+	index int64
+}
+
+func (r *validatorResolver) load(ctx context.Context) (models.ValidatorRecord, error) {
+	validator, _, err := loadersFrom(ctx).validators.load(ctx, r.index)
+	return validator, err
+}
+
+func (r *validatorResolver) Index() Long {
+	return Long(r.index)
+}
+
+func (r *validatorResolver) PublicKey(ctx context.Context) (string, error) {
+	validator, err := r.load(ctx)
+	return validator.PublicKeyHex, err
+}
+
+func (r *validatorResolver) Status(ctx context.Context) (string, error) {
+	validator, err := r.load(ctx)
+	return validator.Status, err
+}
+
+func (r *validatorResolver) EffectiveBalance(ctx context.Context) (string, error) {
+	validator, err := r.load(ctx)
+	return validator.EffectiveBalance, err
+}
+
+func (r *validatorResolver) Slashed(ctx context.Context) (bool, error) {
+	validator, err := r.load(ctx)
+	return validator.Slashed, err
+}
+
+func (r *validatorResolver) ActivationEpoch(ctx context.Context) (string, error) {
+	validator, err := r.load(ctx)
+	return validator.ActivationEpoch, err
+}
+
+func (r *validatorResolver) ExitEpoch(ctx context.Context) (string, error) {
+	validator, err := r.load(ctx)
+	return validator.ExitEpoch, err
+}
+
+func (r *validatorResolver) WithdrawalAddress(ctx context.Context) (*addressResolver, error) {
+	validator, err := r.load(ctx)
+	if err != nil || validator.WithdrawalAddress == "" {
+		return nil, err
+	}
+	return newAddressResolver(validator.WithdrawalAddress), nil
+}
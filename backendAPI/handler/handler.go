@@ -2,10 +2,12 @@ package handler
 
 import (
 	"backendAPI/configs"
+	"backendAPI/db"
 	"backendAPI/routes"
 	"log"
 	"os"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -43,6 +45,18 @@ func monitorMiddleware() gin.HandlerFunc {
 	}
 }
 
+// trustedProxies returns the comma-separated IPs and CIDR ranges of
+// TRUSTED_PROXIES, or nil to trust none
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 func RequestHandler() {
 	log.Println("Initializing API server...")
 
@@ -51,6 +65,12 @@ func RequestHandler() {
 
 	router := gin.New() // Use New() instead of Default() for custom middleware
 
+	// Forwarding headers can be sent by anyone, so client IPs are only read
+	// from them behind the proxies listed in TRUSTED_PROXIES
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Add custom middlewares
 	router.Use(gin.Logger())         // Standard logger
 	router.Use(recoveryMiddleware()) // Custom recovery middleware
@@ -58,9 +78,9 @@ func RequestHandler() {
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -74,9 +94,25 @@ func RequestHandler() {
 	}
 	log.Println("MongoDB connection successful")
 
+	// Import address labels from a local JSON or CSV file if configured
+	if labelsFile := os.Getenv("ADDRESS_LABELS_FILE"); labelsFile != "" {
+		count, err := db.ImportLabelsFile(labelsFile)
+		if err != nil {
+			log.Printf("Warning: Failed to import address labels from %s: %v", labelsFile, err)
+		} else {
+			log.Printf("Imported %d address labels from %s", count, labelsFile)
+		}
+	}
+
 	// Configure routes
 	log.Println("Configuring API routes...")
 	routes.UserRoute(router)
+	routes.AdminRoute(router)
+	routes.ExportRoute(router)
+	routes.EtherscanRoute(router)
+	routes.RPCRoute(router)
+	routes.GraphQLRoute(router)
+	routes.StreamRoute(router)
 	log.Println("API routes initialized successfully")
 
 	env := os.Getenv("APP_ENV")
@@ -8,3 +8,13 @@ type Address struct {
 	Balance  float64            `json:"balance"`
 	Nonce    uint64             `json:"nonce"`
 }
+
+// AddressRecord is a stored address with the exact balance and contract flag
+// kept by the syncer
+type AddressRecord struct {
+	ID         string  `bson:"id"`
+	Balance    float64 `bson:"balance"`
+	BalanceWei string  `bson:"balanceWei"`
+	Nonce      uint64  `bson:"nonce"`
+	IsContract bool    `bson:"isContract"`
+}
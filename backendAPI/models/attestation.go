@@ -0,0 +1,57 @@
+package models
+
+// AttestationPerformance holds a validator's rolling attestation statistics as stored by the syncer
+type AttestationPerformance struct {
+	Effectiveness          float64 `bson:"effectiveness" json:"effectiveness"`
+	Epochs                 int64   `bson:"epochs" json:"epochs"`
+	Included               int64   `bson:"included" json:"included"`
+	CorrectSource          int64   `bson:"correctSource" json:"correctSource"`
+	CorrectTarget          int64   `bson:"correctTarget" json:"correctTarget"`
+	CorrectHead            int64   `bson:"correctHead" json:"correctHead"`
+	TotalInclusionDistance int64   `bson:"totalInclusionDistance" json:"totalInclusionDistance"`
+	LastEpoch              int64   `bson:"lastEpoch" json:"lastEpoch"`
+}
+
+// AttestationSummary is the API view of a validator's attestation performance
+type AttestationSummary struct {
+	Effectiveness            float64                      `json:"effectiveness"` // Rolling score, 0-1
+	EpochsTracked            int64                        `json:"epochsTracked"`
+	InclusionRate            float64                      `json:"inclusionRate"`
+	CorrectSourceRate        float64                      `json:"correctSourceRate"`
+	CorrectTargetRate        float64                      `json:"correctTargetRate"`
+	CorrectHeadRate          float64                      `json:"correctHeadRate"`
+	AverageInclusionDistance float64                      `json:"averageInclusionDistance"`
+	LastEpoch                int64                        `json:"lastEpoch"`
+	Recent                   []ValidatorAttestationRecord `json:"recent"` // Most recent epochs first
+}
+
+// ValidatorAttestationRecord is one validator's attestation result for one epoch
+type ValidatorAttestationRecord struct {
+	Epoch             int64   `bson:"epoch" json:"epoch"`
+	Included          bool    `bson:"included" json:"included"`
+	InclusionDistance int64   `bson:"inclusionDistance" json:"inclusionDistance"`
+	CorrectSource     bool    `bson:"correctSource" json:"correctSource"`
+	CorrectTarget     bool    `bson:"correctTarget" json:"correctTarget"`
+	CorrectHead       bool    `bson:"correctHead" json:"correctHead"`
+	Score             float64 `bson:"score" json:"score"`
+}
+
+// NetworkParticipationRecord holds network-wide attestation participation for one epoch
+type NetworkParticipationRecord struct {
+	Epoch                int64   `bson:"epoch" json:"epoch"`
+	ActiveGwei           string  `bson:"activeGwei" json:"activeGwei"`
+	AttestingGwei        string  `bson:"attestingGwei" json:"attestingGwei"`
+	TargetAttestingGwei  string  `bson:"targetAttestingGwei" json:"targetAttestingGwei"`
+	HeadAttestingGwei    string  `bson:"headAttestingGwei" json:"headAttestingGwei"`
+	ParticipationRate    float64 `bson:"participationRate" json:"participationRate"`
+	TargetParticipation  float64 `bson:"targetParticipation" json:"targetParticipation"`
+	HeadParticipation    float64 `bson:"headParticipation" json:"headParticipation"`
+	AverageEffectiveness float64 `bson:"averageEffectiveness" json:"averageEffectiveness"`
+	ValidatorsTracked    int     `bson:"validatorsTracked" json:"validatorsTracked"`
+	Timestamp            int64   `bson:"timestamp" json:"timestamp"`
+}
+
+// NetworkParticipationResponse represents the participation chart data
+type NetworkParticipationResponse struct {
+	History []NetworkParticipationRecord `json:"history"` // Most recent epochs first
+}
@@ -1,16 +1,34 @@
 package models
 
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
 type ContractInfo struct {
-	ContractCreatorAddress string `json:"creatorAddress" bson:"creatorAddress"`
-	ContractAddress        string `json:"address" bson:"address"`
-	ContractCode           string `json:"contractCode" bson:"contractCode"`
-	CreationTransaction    string `json:"creationTransaction" bson:"creationTransaction"`
-	CreationBlockNumber    string `json:"creationBlockNumber" bson:"creationBlockNumber"`
-	IsToken                bool   `json:"isToken" bson:"isToken"`
-	Status                 string `json:"status" bson:"status"`
-	TokenDecimals          uint8  `json:"decimals" bson:"decimals"`
-	TokenName              string `json:"name" bson:"name"`
-	TokenSymbol            string `json:"symbol" bson:"symbol"`
-	TotalSupply            string `json:"totalSupply" bson:"totalSupply"`
-	UpdatedAt              string `json:"updatedAt" bson:"updatedAt"`
+	ID                     primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	ContractCreatorAddress string             `json:"creatorAddress" bson:"creatorAddress"`
+	ContractAddress        string             `json:"address" bson:"address"`
+	ContractCode           string             `json:"contractCode" bson:"contractCode"`
+	CreationTransaction    string             `json:"creationTransaction" bson:"creationTransaction"`
+	CreationBlockNumber    string             `json:"creationBlockNumber" bson:"creationBlockNumber"`
+	IsToken                bool               `json:"isToken" bson:"isToken"`
+	Status                 string             `json:"status" bson:"status"`
+	TokenDecimals          uint8              `json:"decimals" bson:"decimals"`
+	TokenName              string             `json:"name" bson:"name"`
+	TokenSymbol            string             `json:"symbol" bson:"symbol"`
+	TotalSupply            string             `json:"totalSupply" bson:"totalSupply"`
+	UpdatedAt              string             `json:"updatedAt" bson:"updatedAt"`
+}
+
+// VerifiedContract is the verified source of a contract, stored by address in
+// lowercase Z form. Only the address and ABI are required.
+type VerifiedContract struct {
+	Address              string `bson:"address"`
+	ABI                  string `bson:"abi"`
+	SourceCode           string `bson:"sourceCode"`
+	ContractName         string `bson:"contractName"`
+	CompilerVersion      string `bson:"compilerVersion"`
+	OptimizationUsed     string `bson:"optimizationUsed"`
+	Runs                 string `bson:"runs"`
+	ConstructorArguments string `bson:"constructorArguments"`
+	EVMVersion           string `bson:"evmVersion"`
+	LicenseType          string `bson:"licenseType"`
 }
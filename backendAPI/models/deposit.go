@@ -0,0 +1,22 @@
+package models
+
+// Deposit represents a staking deposit contract event indexed by the syncer
+type Deposit struct {
+	Index                    int64  `bson:"index" json:"index"`
+	PublicKeyHex             string `bson:"publicKeyHex" json:"publicKeyHex"`
+	WithdrawalCredentialsHex string `bson:"withdrawalCredentialsHex" json:"withdrawalCredentialsHex"`
+	Amount                   string `bson:"amount" json:"amount"` // Decimal string, same unit as effectiveBalance
+	Sender                   string `bson:"sender" json:"sender"`
+	TxHash                   string `bson:"txHash" json:"txHash"`
+	BlockNumber              string `bson:"blockNumber" json:"blockNumber"`
+	BlockTimestamp           string `bson:"blockTimestamp" json:"blockTimestamp"`
+	ValidatorIndex           *int64 `bson:"validatorIndex" json:"validatorIndex"` // Nil until the validator appears on the beacon chain
+}
+
+// DepositsResponse represents a page of deposits
+type DepositsResponse struct {
+	Deposits []Deposit `json:"deposits"`
+	Total    int64     `json:"total"`
+	Page     int64     `json:"page"`
+	Limit    int64     `json:"limit"`
+}
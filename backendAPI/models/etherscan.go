@@ -0,0 +1,99 @@
+package models
+
+// EtherscanResponse is the envelope of every Etherscan-compatible API response.
+// Status is "1" on success and "0" on errors or empty results.
+type EtherscanResponse struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Result  interface{} `json:"result"`
+}
+
+// EtherscanTransaction is an entry of account txlist. Numbers are decimal strings.
+type EtherscanTransaction struct {
+	BlockNumber       string `json:"blockNumber"`
+	TimeStamp         string `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             string `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	TransactionIndex  string `json:"transactionIndex"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	Value             string `json:"value"`
+	Gas               string `json:"gas"`
+	GasPrice          string `json:"gasPrice"`
+	IsError           string `json:"isError"`
+	TxReceiptStatus   string `json:"txreceipt_status"`
+	Input             string `json:"input"`
+	ContractAddress   string `json:"contractAddress"`
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	GasUsed           string `json:"gasUsed"`
+	Confirmations     string `json:"confirmations"`
+	MethodID          string `json:"methodId"`
+	FunctionName      string `json:"functionName"`
+}
+
+// EtherscanInternalTransaction is an entry of account txlistinternal
+type EtherscanInternalTransaction struct {
+	BlockNumber     string `json:"blockNumber"`
+	TimeStamp       string `json:"timeStamp"`
+	Hash            string `json:"hash"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	ContractAddress string `json:"contractAddress"`
+	Input           string `json:"input"`
+	Type            string `json:"type"`
+	Gas             string `json:"gas"`
+	GasUsed         string `json:"gasUsed"`
+	TraceID         string `json:"traceId"`
+	IsError         string `json:"isError"`
+	ErrCode         string `json:"errCode"`
+}
+
+// EtherscanTokenTransfer is an entry of account tokentx
+type EtherscanTokenTransfer struct {
+	BlockNumber       string `json:"blockNumber"`
+	TimeStamp         string `json:"timeStamp"`
+	Hash              string `json:"hash"`
+	Nonce             string `json:"nonce"`
+	BlockHash         string `json:"blockHash"`
+	From              string `json:"from"`
+	ContractAddress   string `json:"contractAddress"`
+	To                string `json:"to"`
+	Value             string `json:"value"`
+	TokenName         string `json:"tokenName"`
+	TokenSymbol       string `json:"tokenSymbol"`
+	TokenDecimal      string `json:"tokenDecimal"`
+	TransactionIndex  string `json:"transactionIndex"`
+	Gas               string `json:"gas"`
+	GasPrice          string `json:"gasPrice"`
+	GasUsed           string `json:"gasUsed"`
+	CumulativeGasUsed string `json:"cumulativeGasUsed"`
+	Input             string `json:"input"`
+	Confirmations     string `json:"confirmations"`
+}
+
+// EtherscanSourceCode is the entry returned by contract getsourcecode
+type EtherscanSourceCode struct {
+	SourceCode           string `json:"SourceCode"`
+	ABI                  string `json:"ABI"`
+	ContractName         string `json:"ContractName"`
+	CompilerVersion      string `json:"CompilerVersion"`
+	OptimizationUsed     string `json:"OptimizationUsed"`
+	Runs                 string `json:"Runs"`
+	ConstructorArguments string `json:"ConstructorArguments"`
+	EVMVersion           string `json:"EVMVersion"`
+	Library              string `json:"Library"`
+	LicenseType          string `json:"LicenseType"`
+	Proxy                string `json:"Proxy"`
+	Implementation       string `json:"Implementation"`
+	SwarmSource          string `json:"SwarmSource"`
+}
+
+// EtherscanPrice is the result of stats ethprice
+type EtherscanPrice struct {
+	EthBTC          string `json:"ethbtc"`
+	EthBTCTimestamp string `json:"ethbtc_timestamp"`
+	EthUSD          string `json:"ethusd"`
+	EthUSDTimestamp string `json:"ethusd_timestamp"`
+}
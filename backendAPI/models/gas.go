@@ -0,0 +1,54 @@
+package models
+
+// FeeHistoryRecord is one block's fee market data. Fee values are decimal strings in wei.
+type FeeHistoryRecord struct {
+	BlockNumber       string    `bson:"blockNumber" json:"blockNumber"`
+	BaseFeePerGas     string    `bson:"baseFeePerGas" json:"baseFeePerGas"`
+	NextBaseFeePerGas string    `bson:"nextBaseFeePerGas" json:"nextBaseFeePerGas"`
+	GasUsedRatio      float64   `bson:"gasUsedRatio" json:"gasUsedRatio"`
+	GasUsed           string    `bson:"gasUsed" json:"gasUsed"`
+	BurntFee          string    `bson:"burntFee" json:"burntFee"` // baseFeePerGas * gasUsed
+	Percentiles       []float64 `bson:"percentiles" json:"percentiles"`
+	PriorityFees      []string  `bson:"priorityFees" json:"priorityFees"` // Effective priority fee at each percentile
+	Timestamp         int64     `bson:"timestamp" json:"timestamp"`
+}
+
+// GasHistoryResponse represents recent per-block fee market data
+type GasHistoryResponse struct {
+	History []FeeHistoryRecord `json:"history"` // Most recent block first
+}
+
+// GasSuggestion is a fee suggestion for one speed tier, in wei
+type GasSuggestion struct {
+	MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         string `json:"maxFeePerGas"`
+}
+
+// GasOracleResponse represents slow/standard/fast fee suggestions for the next block
+type GasOracleResponse struct {
+	LastBlock         string        `json:"lastBlock"`
+	NextBaseFeePerGas string        `json:"nextBaseFeePerGas"`
+	BlocksSampled     int           `json:"blocksSampled"`  // Non-empty blocks the tips were taken from
+	PendingSampled    int           `json:"pendingSampled"` // Pending transactions checked for congestion
+	Slow              GasSuggestion `json:"slow"`
+	Standard          GasSuggestion `json:"standard"`
+	Fast              GasSuggestion `json:"fast"`
+}
+
+// TxFees is the EIP-1559 fee breakdown of a mined transaction, in wei.
+// BurntFee + PriorityFee == TotalFee.
+type TxFees struct {
+	GasUsed           string `bson:"gasUsed" json:"gasUsed"`
+	EffectiveGasPrice string `bson:"effectiveGasPrice" json:"effectiveGasPrice"`
+	BaseFeePerGas     string `bson:"baseFeePerGas" json:"baseFeePerGas"`
+	BurntFee          string `bson:"burntFee" json:"burntFee"`
+	PriorityFee       string `bson:"priorityFee" json:"priorityFee"` // Tip paid to the block proposer
+	TotalFee          string `bson:"totalFee" json:"totalFee"`
+}
+
+// BlockFees is the base fee burnt by one block, in wei
+type BlockFees struct {
+	BaseFeePerGas string `json:"baseFeePerGas"`
+	GasUsed       string `json:"gasUsed"`
+	BurntFee      string `json:"burntFee"`
+}
@@ -17,6 +17,12 @@ type ZondUint64Version struct {
 	Jsonrpc string `json:"jsonrpc"`
 	ID      int    `json:"id"`
 	Result  Result `json:"result"`
+
+	// Beacon proposer attribution, set by the syncer once the slot is known
+	Slot            *int64 `bson:"slot,omitempty" json:"slot,omitempty"`
+	ProposerIndex   *int64 `bson:"proposerIndex,omitempty" json:"proposerIndex,omitempty"`
+	Graffiti        string `bson:"graffiti,omitempty" json:"graffiti,omitempty"`
+	BeaconBlockRoot string `bson:"beaconBlockRoot,omitempty" json:"beaconBlockRoot,omitempty"`
 }
 
 
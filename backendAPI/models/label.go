@@ -0,0 +1,30 @@
+package models
+
+// Address label categories
+const (
+	LabelCategoryExchange          = "exchange"
+	LabelCategoryBridge            = "bridge"
+	LabelCategoryTeam              = "team"
+	LabelCategoryContract          = "contract"
+	LabelCategoryValidatorOperator = "validator_operator"
+)
+
+// LabelCategories lists the accepted label categories
+var LabelCategories = map[string]bool{
+	LabelCategoryExchange:          true,
+	LabelCategoryBridge:            true,
+	LabelCategoryTeam:              true,
+	LabelCategoryContract:          true,
+	LabelCategoryValidatorOperator: true,
+}
+
+// AddressLabel names an address. Private labels are only returned by the
+// admin endpoints.
+type AddressLabel struct {
+	Address   string `bson:"_id" json:"address"` // Lowercase address
+	Name      string `bson:"name" json:"name"`
+	Category  string `bson:"category" json:"category"`
+	Source    string `bson:"source" json:"source"` // Where the label came from, e.g. an import file or "admin"
+	Public    bool   `bson:"public" json:"public"`
+	UpdatedAt int64  `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// QueueEntry is a validator waiting in the activation or exit queue
+type QueueEntry struct {
+	ValidatorIndex   int64  `json:"validatorIndex"`
+	PublicKeyHex     string `json:"publicKeyHex"`
+	Position         int64  `json:"position"`                   // 1-based position in the queue
+	EligibilityEpoch *int64 `json:"eligibilityEpoch,omitempty"` // Activation queue only; nil until the deposit is processed
+	EstimatedEpoch   int64  `json:"estimatedEpoch"`
+	EstimatedTime    int64  `json:"estimatedTime"` // Unix seconds at the start of EstimatedEpoch
+	Scheduled        bool   `json:"scheduled"`     // True once the chain has fixed the epoch
+}
+
+// ValidatorQueueResponse represents the activation and exit queues with per-validator estimates
+type ValidatorQueueResponse struct {
+	CurrentEpoch        int64        `json:"currentEpoch"`
+	FinalizedEpoch      int64        `json:"finalizedEpoch"`
+	ActiveCount         int64        `json:"activeCount"`
+	ChurnLimit          int64        `json:"churnLimit"` // Validators activated or exited per epoch
+	ActivationQueueSize int64        `json:"activationQueueSize"`
+	ExitQueueSize       int64        `json:"exitQueueSize"`
+	NextActivationEpoch int64        `json:"nextActivationEpoch"` // Estimate for a validator joining the queue now
+	NextActivationTime  int64        `json:"nextActivationTime"`
+	NextExitEpoch       int64        `json:"nextExitEpoch"` // Estimate for an exit initiated now
+	NextExitTime        int64        `json:"nextExitTime"`
+	ActivationQueue     []QueueEntry `json:"activationQueue"`
+	ExitQueue           []QueueEntry `json:"exitQueue"`
+}
@@ -0,0 +1,41 @@
+package models
+
+// Receipt is a stored transaction receipt. Quantities are hex strings as returned by the node.
+type Receipt struct {
+	TxHash            string       `bson:"_id" json:"transactionHash"`
+	BlockHash         string       `bson:"blockHash" json:"blockHash"`
+	BlockNumber       string       `bson:"blockNumber" json:"blockNumber"`
+	TransactionIndex  string       `bson:"transactionIndex" json:"transactionIndex"`
+	From              string       `bson:"from" json:"from"`
+	To                string       `bson:"to,omitempty" json:"to,omitempty"`
+	ContractAddress   string       `bson:"contractAddress,omitempty" json:"contractAddress,omitempty"`
+	Status            string       `bson:"status" json:"status"` // 0x1 success, 0x0 failure
+	Type              string       `bson:"type" json:"type"`
+	GasUsed           string       `bson:"gasUsed" json:"gasUsed"`
+	CumulativeGasUsed string       `bson:"cumulativeGasUsed" json:"cumulativeGasUsed"`
+	EffectiveGasPrice string       `bson:"effectiveGasPrice" json:"effectiveGasPrice"`
+	LogsBloom         string       `bson:"logsBloom" json:"logsBloom"`
+	Logs              []ReceiptLog `bson:"logs" json:"logs"`
+	Timestamp         string       `bson:"timestamp" json:"timestamp"`
+}
+
+// ReceiptLog is a log entry emitted by a transaction
+type ReceiptLog struct {
+	Address          string   `bson:"address" json:"address"`
+	Topics           []string `bson:"topics" json:"topics"`
+	Data             string   `bson:"data" json:"data"`
+	BlockNumber      string   `bson:"blockNumber" json:"blockNumber"`
+	TransactionHash  string   `bson:"transactionHash" json:"transactionHash"`
+	TransactionIndex string   `bson:"transactionIndex" json:"transactionIndex"`
+	BlockHash        string   `bson:"blockHash" json:"blockHash"`
+	LogIndex         string   `bson:"logIndex" json:"logIndex"`
+	Removed          bool     `bson:"removed" json:"removed"`
+}
+
+// RevertReason explains why a failed transaction reverted
+type RevertReason struct {
+	Type   string `bson:"type" json:"type"` // error, panic, custom, vm or unknown
+	Reason string `bson:"reason" json:"reason"`
+	Data   string `bson:"data,omitempty" json:"data,omitempty"` // Raw revert data
+	Source string `bson:"source" json:"source"`                 // trace or call (replay at the parent block)
+}
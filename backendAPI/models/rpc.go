@@ -0,0 +1,27 @@
+package models
+
+import "encoding/json"
+
+// RPCRequest is a JSON-RPC 2.0 request received by the /rpc proxy. Params and
+// ID are kept raw so they can be forwarded to the node unchanged.
+type RPCRequest struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// RPCResponse is a JSON-RPC 2.0 response. Exactly one of Result and Error is set.
+type RPCResponse struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC error object
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
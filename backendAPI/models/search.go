@@ -0,0 +1,34 @@
+package models
+
+// Search result types
+const (
+	SearchTypeBlock              = "block"
+	SearchTypeTransaction        = "transaction"
+	SearchTypePendingTransaction = "pendingTransaction"
+	SearchTypeAddress            = "address"
+	SearchTypeValidator          = "validator"
+	SearchTypeToken              = "token"
+	SearchTypeLabel              = "label"
+)
+
+// Search match kinds
+const (
+	SearchMatchExact  = "exact"
+	SearchMatchPrefix = "prefix"
+)
+
+// SearchResult is one typed match of a search query. Value identifies the
+// entity for navigation: block number, hash, address or validator index.
+type SearchResult struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Name  string `json:"name,omitempty"` // Token name and symbol, label name or validator public key
+	Match string `json:"match"`
+	Score int    `json:"score"`
+}
+
+// SearchResponse is the API response of /search
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// SlotRecord represents a beacon slot stored by the syncer
+type SlotRecord struct {
+	Slot                 int64  `bson:"slot" json:"slot"`
+	Epoch                int64  `bson:"epoch" json:"epoch"`
+	ProposerIndex        *int64 `bson:"proposerIndex" json:"proposerIndex"`
+	Status               string `bson:"status" json:"status"` // proposed, missed or orphaned
+	ExecutionBlockHash   string `bson:"executionBlockHash,omitempty" json:"executionBlockHash,omitempty"`
+	ExecutionBlockNumber string `bson:"executionBlockNumber,omitempty" json:"executionBlockNumber,omitempty"`
+	BlockRoot            string `bson:"blockRoot,omitempty" json:"blockRoot,omitempty"`
+	Graffiti             string `bson:"graffiti,omitempty" json:"graffiti,omitempty"`
+	Timestamp            int64  `bson:"timestamp" json:"timestamp"`
+	Finalized            bool   `bson:"finalized" json:"finalized"`
+}
+
+// SlotsResponse represents a page of slots
+type SlotsResponse struct {
+	Slots []SlotRecord `json:"slots"`
+	Total int64        `json:"total"`
+	Page  int64        `json:"page"`
+	Limit int64        `json:"limit"`
+}
+
+// EpochSummaryResponse represents the slots of a single epoch with per-status counts
+type EpochSummaryResponse struct {
+	Epoch         int64        `json:"epoch"`
+	StartSlot     int64        `json:"startSlot"`
+	EndSlot       int64        `json:"endSlot"`
+	ProposedCount int          `json:"proposedCount"`
+	MissedCount   int          `json:"missedCount"`
+	OrphanedCount int          `json:"orphanedCount"`
+	Finalized     bool         `json:"finalized"`
+	Slots         []SlotRecord `json:"slots"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// StreamBlock is a new block pushed to stream subscribers. Quantities are decimal strings.
+type StreamBlock struct {
+	Number           string `json:"number"`
+	Hash             string `json:"hash"`
+	ParentHash       string `json:"parentHash"`
+	Timestamp        string `json:"timestamp"`
+	TransactionCount int    `json:"transactionCount"`
+	GasUsed          string `json:"gasUsed"`
+	GasLimit         string `json:"gasLimit"`
+	BaseFeePerGas    string `json:"baseFeePerGas,omitempty"`
+	Size             string `json:"size"`
+}
+
+// StreamTransaction is a new mined transaction pushed to stream subscribers,
+// read from the transfer collection
+type StreamTransaction struct {
+	Hash            string  `bson:"txHash" json:"hash"`
+	BlockNumber     string  `bson:"blockNumber" json:"blockNumber"`
+	Timestamp       string  `bson:"blockTimestamp" json:"timestamp"`
+	From            string  `bson:"from" json:"from"`
+	To              string  `bson:"to,omitempty" json:"to,omitempty"`
+	ContractAddress string  `bson:"contractAddress,omitempty" json:"contractAddress,omitempty"` // Set for contract creations
+	Value           float64 `bson:"value" json:"value"`                                         // QRL
+	Status          string  `bson:"status" json:"status"`
+	Fee             string  `bson:"totalFee,omitempty" json:"fee,omitempty"` // wei
+}
@@ -0,0 +1,19 @@
+package models
+
+// Supply is the supply breakdown computed by the syncer. Amounts are decimal
+// strings in wei; WithdrawalPending is included in Staked.
+type Supply struct {
+	TotalIssued       string `bson:"totalIssued" json:"totalIssued"`
+	CurrentSupply     string `bson:"currentSupply" json:"currentSupply"`
+	Circulating       string `bson:"circulating" json:"circulating"`
+	ExecutionBalances string `bson:"executionBalances" json:"executionBalances"`
+	LockedInContracts string `bson:"lockedInContracts" json:"lockedInContracts"`
+	Staked            string `bson:"staked" json:"staked"`
+	WithdrawalPending string `bson:"withdrawalPending" json:"withdrawalPending"`
+	BurntFees         string `bson:"burntFees" json:"burntFees"`
+	AddressCount      int64  `bson:"addressCount" json:"addressCount"`
+	MissingBalances   int64  `bson:"missingBalances" json:"missingBalances"`
+	ValidatorEpoch    int64  `bson:"validatorEpoch" json:"validatorEpoch"`
+	BurntFeesBlock    int64  `bson:"burntFeesBlock" json:"burntFeesBlock"`
+	UpdatedAt         int64  `bson:"updatedAt" json:"updatedAt"`
+}
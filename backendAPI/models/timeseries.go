@@ -0,0 +1,33 @@
+package models
+
+// NetworkStatsRecord is one hourly or daily bucket of network statistics as
+// stored by the syncer. Wei amounts are decimal strings.
+type NetworkStatsRecord struct {
+	Interval            string `bson:"interval"`
+	Timestamp           int64  `bson:"timestamp"`
+	BlockCount          int64  `bson:"blockCount"`
+	BlockTimeSum        int64  `bson:"blockTimeSum"`
+	TxCount             int64  `bson:"txCount"`
+	ActiveAddresses     int64  `bson:"activeAddresses"`
+	NewAddresses        int64  `bson:"newAddresses"`
+	GasUsed             int64  `bson:"gasUsed"`
+	TotalFees           string `bson:"totalFees"`
+	FeeTxCount          int64  `bson:"feeTxCount"`
+	BurntFees           string `bson:"burntFees"`
+	ContractDeployments int64  `bson:"contractDeployments"`
+	TokenTransfers      int64  `bson:"tokenTransfers"`
+}
+
+// TimeSeriesPoint is the value of a metric for one bucket. Counts, gas and
+// block times are numbers; wei amounts are decimal strings.
+type TimeSeriesPoint struct {
+	Timestamp int64       `json:"timestamp"` // Bucket start, Unix seconds UTC
+	Value     interface{} `json:"value"`
+}
+
+// TimeSeriesResponse represents a metric over hourly or daily buckets
+type TimeSeriesResponse struct {
+	Metric   string            `json:"metric"`
+	Interval string            `json:"interval"`
+	Data     []TimeSeriesPoint `json:"data"` // Oldest bucket first
+}
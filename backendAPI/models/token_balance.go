@@ -1,5 +1,7 @@
 package models
 
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
 // TokenBalance represents a token holding for a specific address
 type TokenBalance struct {
 	ContractAddress string `json:"contractAddress" bson:"contractAddress"`
@@ -33,6 +35,11 @@ type TokenTransfer struct {
 	TokenDecimals   int    `json:"tokenDecimals" bson:"tokenDecimals"`
 	TokenName       string `json:"tokenName" bson:"tokenName"`
 	TransferType    string `json:"transferType" bson:"transferType"`
+	// Chain position used for cursor pagination
+	ID       primitive.ObjectID `json:"-" bson:"_id,omitempty"`
+	BlockNum int64              `json:"-" bson:"blockNum"`
+	TxIndex  int64              `json:"-" bson:"txIndex"`
+	LogIndex int64              `json:"-" bson:"logIndex"`
 }
 
 // TokenHoldersResponse is the API response for token holders
@@ -42,6 +49,10 @@ type TokenHoldersResponse struct {
 	TotalHolders    int            `json:"totalHolders"`
 	Page            int            `json:"page"`
 	Limit           int            `json:"limit"`
+	// Cursor of the next page, empty on the last page
+	NextCursor string `json:"nextCursor"`
+	// Public labels of the holders, keyed by Z-prefix lowercase address
+	Labels map[string]AddressLabel `json:"labels"`
 }
 
 // TokenTransfersResponse is the API response for token transfers
@@ -51,6 +62,10 @@ type TokenTransfersResponse struct {
 	TotalTransfers  int64           `json:"totalTransfers"`
 	Page            int             `json:"page"`
 	Limit           int             `json:"limit"`
+	// Cursor of the next page, empty on the last page
+	NextCursor string `json:"nextCursor"`
+	// Public labels of the senders and recipients, keyed by Z-prefix lowercase address
+	Labels map[string]AddressLabel `json:"labels"`
 }
 
 // TokenInfo contains summary information about a token
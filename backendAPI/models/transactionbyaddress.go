@@ -21,6 +21,9 @@ type TransactionByAddress struct {
 	Amount      float64            `bson:"amount" json:"-"`
 	PaidFees    float64            `bson:"paidFees" json:"-"`
 	BlockNumber string             `bson:"blockNumber" json:"BlockNumber"`
+	// Chain position used for cursor pagination
+	BlockNum int64 `bson:"blockNum" json:"-"`
+	TxIndex  int64 `bson:"txIndex" json:"-"`
 }
 
 func formatFloat(f float64) string {
@@ -1,32 +1,32 @@
 package models
 
-// ValidatorStorage represents the validator document in MongoDB
-type ValidatorStorage struct {
-	ID         string            `bson:"_id" json:"_id"`
-	Epoch      string            `bson:"epoch" json:"epoch"`           // Stored as hex
-	Validators []ValidatorRecord `bson:"validators" json:"validators"` // All validator details
-	UpdatedAt  string            `bson:"updatedAt" json:"updatedAt"`   // Timestamp in hex
-}
-
-// ValidatorRecord represents a single validator's data
+// ValidatorRecord represents a single validator document in MongoDB, keyed by index
 type ValidatorRecord struct {
-	Index                      string `bson:"index" json:"index"`                                       // Stored as hex
-	PublicKeyHex               string `bson:"publicKeyHex" json:"publicKeyHex"`                         // Converted from base64 to hex
-	WithdrawalCredentialsHex   string `bson:"withdrawalCredentialsHex" json:"withdrawalCredentialsHex"` // Converted from base64 to hex
-	EffectiveBalance           string `bson:"effectiveBalance" json:"effectiveBalance"`                 // Stored as hex
+	ID                         int64  `bson:"_id" json:"-"`                                                   // Numeric validator index
+	Index                      string `bson:"index" json:"index"`                                             // Decimal string
+	PublicKeyHex               string `bson:"publicKeyHex" json:"publicKeyHex"`                               // Converted from base64 to hex
+	WithdrawalCredentialsHex   string `bson:"withdrawalCredentialsHex" json:"withdrawalCredentialsHex"`       // Converted from base64 to hex
+	WithdrawalAddress          string `bson:"withdrawalAddress,omitempty" json:"withdrawalAddress,omitempty"` // Z-address of 0x01 credentials
+	EffectiveBalance           string `bson:"effectiveBalance" json:"effectiveBalance"`                       // Decimal string
 	Slashed                    bool   `bson:"slashed" json:"slashed"`
-	ActivationEligibilityEpoch string `bson:"activationEligibilityEpoch" json:"activationEligibilityEpoch"` // Stored as hex
-	ActivationEpoch            string `bson:"activationEpoch" json:"activationEpoch"`                       // Stored as hex
-	ExitEpoch                  string `bson:"exitEpoch" json:"exitEpoch"`                                   // Stored as hex
-	WithdrawableEpoch          string `bson:"withdrawableEpoch" json:"withdrawableEpoch"`                   // Stored as hex
-	SlotNumber                 string `bson:"slotNumber" json:"slotNumber"`                                 // Stored as hex
-	IsLeader                   bool   `bson:"isLeader" json:"isLeader"`
+	ActivationEligibilityEpoch string `bson:"activationEligibilityEpoch" json:"activationEligibilityEpoch"` // Decimal string
+	ActivationEpoch            string `bson:"activationEpoch" json:"activationEpoch"`                       // Decimal string
+	ExitEpoch                  string `bson:"exitEpoch" json:"exitEpoch"`                                   // Decimal string
+	WithdrawableEpoch          string `bson:"withdrawableEpoch" json:"withdrawableEpoch"`                   // Decimal string
+	Status                     string `bson:"status" json:"status"`                                         // Status as of the last refresh
+	Epoch                      string `bson:"epoch" json:"epoch"`                                           // Epoch of the last refresh
+	UpdatedAt                  int64  `bson:"updatedAt" json:"updatedAt"`                                   // Unix timestamp
+
+	AttestationPerformance *AttestationPerformance `bson:"attestationPerformance,omitempty" json:"attestationPerformance,omitempty"`
 }
 
 // ValidatorResponse represents the API response format
 type ValidatorResponse struct {
-	Validators  []Validator `json:"validators"`
-	TotalStaked string      `json:"totalStaked"` // Total amount staked in hex
+	Validators      []Validator `json:"validators"`
+	TotalStaked     string      `json:"totalStaked"` // Total effective balance staked, decimal
+	TotalValidators int64       `json:"totalValidators"`
+	Page            int64       `json:"page"`
+	Limit           int64       `json:"limit"`
 }
 
 // Validator represents a single validator in the API response
@@ -84,18 +84,34 @@ type ValidatorHistoryResponse struct {
 
 // ValidatorDetailResponse represents detailed information about a single validator
 type ValidatorDetailResponse struct {
-	Index                      string `json:"index"`
-	PublicKeyHex               string `json:"publicKeyHex"`
-	WithdrawalCredentialsHex   string `json:"withdrawalCredentialsHex"`
-	EffectiveBalance           string `json:"effectiveBalance"`
-	Slashed                    bool   `json:"slashed"`
-	ActivationEligibilityEpoch string `json:"activationEligibilityEpoch"`
-	ActivationEpoch            string `json:"activationEpoch"`
-	ExitEpoch                  string `json:"exitEpoch"`
-	WithdrawableEpoch          string `json:"withdrawableEpoch"`
-	Status                     string `json:"status"`
-	Age                        int64  `json:"age"`
-	CurrentEpoch               string `json:"currentEpoch"`
+	Index                      string              `json:"index"`
+	PublicKeyHex               string              `json:"publicKeyHex"`
+	WithdrawalCredentialsHex   string              `json:"withdrawalCredentialsHex"`
+	WithdrawalAddress          string              `json:"withdrawalAddress,omitempty"`
+	EffectiveBalance           string              `json:"effectiveBalance"`
+	Slashed                    bool                `json:"slashed"`
+	ActivationEligibilityEpoch string              `json:"activationEligibilityEpoch"`
+	ActivationEpoch            string              `json:"activationEpoch"`
+	ExitEpoch                  string              `json:"exitEpoch"`
+	WithdrawableEpoch          string              `json:"withdrawableEpoch"`
+	Status                     string              `json:"status"`
+	Age                        int64               `json:"age"`
+	CurrentEpoch               string              `json:"currentEpoch"`
+	ProposedBlocksCount        int64               `json:"proposedBlocksCount"`
+	ProposedBlocks             []ProposedBlock     `json:"proposedBlocks"` // Most recent first
+	Attestations               *AttestationSummary `json:"attestations,omitempty"`
+	FundedBy                   string              `json:"fundedBy,omitempty"`      // Sender of the first deposit
+	Deposits                   []Deposit           `json:"deposits"`                // Oldest first
+	QueueEstimate              *QueueEntry         `json:"queueEstimate,omitempty"` // Set while waiting for activation or exit
+}
+
+// ProposedBlock is an execution block attributed to a validator as beacon proposer
+type ProposedBlock struct {
+	Number    string `bson:"number" json:"number"`
+	Hash      string `bson:"hash" json:"hash"`
+	Timestamp string `bson:"timestamp" json:"timestamp"`
+	Slot      int64  `bson:"slot" json:"slot"`
+	Graffiti  string `bson:"graffiti" json:"graffiti"`
 }
 
 // ValidatorStatsResponse represents aggregated validator statistics
@@ -108,3 +124,75 @@ type ValidatorStatsResponse struct {
 	TotalStaked     string `json:"totalStaked"`
 	CurrentEpoch    string `json:"currentEpoch"`
 }
+
+// ValidatorBalanceSnapshot is a per-validator balance snapshot for one epoch (gwei)
+type ValidatorBalanceSnapshot struct {
+	ValidatorIndex   int64  `bson:"validatorIndex" json:"validatorIndex"`
+	Epoch            int64  `bson:"epoch" json:"epoch"`
+	PreviousEpoch    *int64 `bson:"previousEpoch,omitempty" json:"previousEpoch"` // Nil for a validator's first snapshot
+	Balance          string `bson:"balance" json:"balance"`
+	EffectiveBalance string `bson:"effectiveBalance" json:"effectiveBalance"`
+	Reward           int64  `bson:"reward" json:"reward"`
+	Penalty          int64  `bson:"penalty" json:"penalty"`
+	Withdrawn        int64  `bson:"withdrawn" json:"withdrawn"`
+	Timestamp        int64  `bson:"timestamp" json:"timestamp"`
+}
+
+// ValidatorRewardsResponse summarises rewards for a single validator over the returned snapshots
+type ValidatorRewardsResponse struct {
+	Index      string                     `json:"index"`
+	FromEpoch  int64                      `json:"fromEpoch"`
+	ToEpoch    int64                      `json:"toEpoch"`
+	Rewards    int64                      `json:"rewards"`    // gwei
+	Penalties  int64                      `json:"penalties"`  // gwei
+	NetRewards int64                      `json:"netRewards"` // gwei
+	Withdrawn  int64                      `json:"withdrawn"`  // gwei
+	APR        float64                    `json:"apr"`        // Annualised, percent
+	Snapshots  []ValidatorBalanceSnapshot `json:"snapshots"`
+}
+
+// NetworkRewardsRecord holds network-wide balance totals for one snapshot epoch
+type NetworkRewardsRecord struct {
+	Epoch                 int64   `bson:"epoch" json:"epoch"`
+	PreviousEpoch         *int64  `bson:"previousEpoch,omitempty" json:"previousEpoch"` // Nil for the first snapshot
+	ValidatorsCount       int     `bson:"validatorsCount" json:"validatorsCount"`
+	TotalBalance          string  `bson:"totalBalance" json:"totalBalance"`
+	TotalEffectiveBalance string  `bson:"totalEffectiveBalance" json:"totalEffectiveBalance"`
+	Rewards               int64   `bson:"rewards" json:"rewards"`
+	Penalties             int64   `bson:"penalties" json:"penalties"`
+	Withdrawn             int64   `bson:"withdrawn" json:"withdrawn"`
+	Timestamp             int64   `bson:"timestamp" json:"timestamp"`
+	APR                   float64 `bson:"-" json:"apr"` // Annualised for this snapshot interval, percent
+}
+
+// NetworkAPRResponse represents network-wide staking returns over the returned snapshots
+type NetworkAPRResponse struct {
+	FromEpoch  int64                  `json:"fromEpoch"`
+	ToEpoch    int64                  `json:"toEpoch"`
+	Rewards    int64                  `json:"rewards"`    // gwei
+	Penalties  int64                  `json:"penalties"`  // gwei
+	NetRewards int64                  `json:"netRewards"` // gwei
+	APR        float64                `json:"apr"`        // Annualised, percent
+	History    []NetworkRewardsRecord `json:"history"`
+}
+
+// AddressValidator is a validator withdrawing to an address
+type AddressValidator struct {
+	Index            string `json:"index"`
+	PublicKeyHex     string `json:"publicKeyHex"`
+	Status           string `json:"status"`
+	EffectiveBalance string `json:"effectiveBalance"`
+	Balance          string `json:"balance,omitempty"`      // From the latest balance snapshot
+	BalanceEpoch     int64  `json:"balanceEpoch,omitempty"` // Epoch of Balance
+	ActivationEpoch  string `json:"activationEpoch"`
+	ExitEpoch        string `json:"exitEpoch"`
+}
+
+// AddressValidatorsResponse lists the validators withdrawing to an address
+type AddressValidatorsResponse struct {
+	Address               string             `json:"address"`
+	Validators            []AddressValidator `json:"validators"`
+	Count                 int                `json:"count"`
+	TotalEffectiveBalance string             `json:"totalEffectiveBalance"`
+	TotalBalance          string             `json:"totalBalance"`
+}
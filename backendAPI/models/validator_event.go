@@ -0,0 +1,20 @@
+package models
+
+// ValidatorEvent represents a validator state transition recorded by the syncer
+type ValidatorEvent struct {
+	ValidatorIndex int64  `bson:"validatorIndex" json:"validatorIndex"`
+	PublicKeyHex   string `bson:"publicKeyHex" json:"publicKeyHex"`
+	Type           string `bson:"type" json:"type"`                         // deposited, activated, exit, slashed or withdrawable
+	Epoch          int64  `bson:"epoch" json:"epoch"`                       // Epoch the transition takes effect
+	Reason         string `bson:"reason,omitempty" json:"reason,omitempty"` // voluntary_exit or slashed for exits, proposer_slashing or attester_slashing for slashings
+	DetectedEpoch  int64  `bson:"detectedEpoch" json:"detectedEpoch"`
+	Timestamp      int64  `bson:"timestamp" json:"timestamp"`
+}
+
+// ValidatorEventsResponse represents a page of validator events
+type ValidatorEventsResponse struct {
+	Events []ValidatorEvent `json:"events"`
+	Total  int64            `json:"total"`
+	Page   int64            `json:"page"`
+	Limit  int64            `json:"limit"`
+}
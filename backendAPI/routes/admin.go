@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"backendAPI/db"
+	"backendAPI/models"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminAuth only lets requests through that carry the ADMIN_API_KEY as a
+// bearer token. The admin API is disabled while the key is unset.
+func adminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := os.Getenv("ADMIN_API_KEY")
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Admin API is disabled, set ADMIN_API_KEY to enable it",
+			})
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or missing admin credentials",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminRoute registers the authenticated endpoints used to manage address labels
+func AdminRoute(router *gin.Engine) {
+	admin := router.Group("/admin", adminAuth())
+
+	// List all labels, private ones included
+	admin.GET("/labels", func(c *gin.Context) {
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "0"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if page < 0 {
+			page = 0
+		}
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		labels, total, err := db.GetLabels(page, limit, c.Query("category"), true)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch labels: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"labels": labels,
+			"total":  total,
+			"page":   page,
+			"limit":  limit,
+		})
+	})
+
+	// Create or replace the label of an address
+	admin.PUT("/labels/:address", func(c *gin.Context) {
+		var label models.AddressLabel
+		if err := c.ShouldBindJSON(&label); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid label: %v", err),
+			})
+			return
+		}
+		label.Address = c.Param("address")
+		if label.Source == "" {
+			label.Source = "admin"
+		}
+
+		labels := []models.AddressLabel{label}
+		if err := db.NormalizeLabel(&labels[0]); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if _, err := db.UpsertLabels(labels); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to store label: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"label": labels[0]})
+	})
+
+	// Remove the label of an address
+	admin.DELETE("/labels/:address", func(c *gin.Context) {
+		deleted, err := db.DeleteLabel(c.Param("address"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to delete label: %v", err),
+			})
+			return
+		}
+		if !deleted {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"deleted": true})
+	})
+
+	// Import labels from a JSON array or CSV request body
+	admin.POST("/labels/import", func(c *gin.Context) {
+		format := c.DefaultQuery("format", db.LabelFormatJSON)
+		labels, err := db.ParseLabels(c.Request.Body, format, c.DefaultQuery("source", "admin-import"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for i := range labels {
+			if err := db.NormalizeLabel(&labels[i]); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		count, err := db.UpsertLabels(labels)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to import labels: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"imported": count})
+	})
+}
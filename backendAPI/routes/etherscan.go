@@ -0,0 +1,385 @@
+package routes
+
+import (
+	"backendAPI/db"
+	"backendAPI/models"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etherscanAction answers one module/action pair of the Etherscan-compatible API
+type etherscanAction func(c *gin.Context)
+
+// EtherscanRoute registers /api, which answers the common Etherscan API
+// modules and actions in Etherscan's response envelope so existing tooling
+// and wallets can use the explorer directly. The apikey parameter is accepted
+// and ignored.
+func EtherscanRoute(router *gin.Engine) {
+	actions := map[string]map[string]etherscanAction{
+		"account": {
+			"balance":        etherscanBalance,
+			"txlist":         etherscanTxList,
+			"txlistinternal": etherscanTxListInternal,
+			"tokentx":        etherscanTokenTx,
+			"tokenbalance":   etherscanTokenBalance,
+		},
+		"block": {
+			"getblocknobytime": etherscanBlockNoByTime,
+		},
+		"contract": {
+			"getabi":        etherscanGetABI,
+			"getsourcecode": etherscanGetSourceCode,
+		},
+		"stats": {
+			"ethsupply": etherscanSupply,
+			"ethprice":  etherscanPrice,
+		},
+	}
+
+	router.GET("/api", func(c *gin.Context) {
+		module, ok := actions[c.Query("module")]
+		if !ok {
+			etherscanError(c, "Error! Missing Or invalid Module name")
+			return
+		}
+		action, ok := module[c.Query("action")]
+		if !ok {
+			etherscanError(c, "Error! Missing Or invalid Action name")
+			return
+		}
+		action(c)
+	})
+}
+
+// etherscanOK answers with a successful result
+func etherscanOK(c *gin.Context, result interface{}) {
+	c.JSON(http.StatusOK, models.EtherscanResponse{Status: "1", Message: "OK", Result: result})
+}
+
+// etherscanError answers with an error message as the result. Etherscan
+// reports errors with status 0 rather than an HTTP error code.
+func etherscanError(c *gin.Context, message string) {
+	c.JSON(http.StatusOK, models.EtherscanResponse{Status: "0", Message: "NOTOK", Result: message})
+}
+
+// etherscanList answers with a list, reporting an empty one the way Etherscan does
+func etherscanList(c *gin.Context, length int, result interface{}) {
+	if length == 0 {
+		c.JSON(http.StatusOK, models.EtherscanResponse{Status: "0", Message: "No transactions found", Result: []interface{}{}})
+		return
+	}
+	etherscanOK(c, result)
+}
+
+// etherscanFailed logs an internal error and answers with a generic message
+func etherscanFailed(c *gin.Context, action string, err error) {
+	log.Printf("Etherscan API %s failed: %v", action, err)
+	etherscanError(c, "Error! Failed to process the request")
+}
+
+// etherscanAddressParam reads an address parameter in Z or 0x form
+func etherscanAddressParam(c *gin.Context, key string) (string, bool) {
+	return parseAddress(strings.TrimSpace(c.Query(key)))
+}
+
+// parseAddress returns a Z- or 0x-prefixed address in Z-prefix lowercase form
+func parseAddress(address string) (string, bool) {
+	lower := strings.ToLower(address)
+	if strings.HasPrefix(lower, "0x") {
+		lower = "z" + lower[2:]
+	}
+	if len(lower) != 41 || lower[0] != 'z' {
+		return "", false
+	}
+	if _, err := hex.DecodeString(lower[1:]); err != nil {
+		return "", false
+	}
+	return "Z" + lower[1:], true
+}
+
+// etherscanListQuery reads the block range, pagination and sort order shared
+// by the account list actions
+func etherscanListQuery(c *gin.Context) (db.EtherscanQuery, string) {
+	q := db.EtherscanQuery{Page: 1, Offset: db.EtherscanMaxResults}
+
+	if value := c.Query("startblock"); value != "" {
+		start, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || start < 0 {
+			return q, "Error! Invalid startblock"
+		}
+		q.StartBlock = start
+	}
+	if value := c.Query("endblock"); value != "" {
+		end, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || end < 0 {
+			return q, "Error! Invalid endblock"
+		}
+		q.EndBlock = end
+	}
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 0 {
+			return q, "Error! Invalid page"
+		}
+		if page > 0 {
+			q.Page = page
+		}
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return q, "Error! Invalid offset"
+		}
+		if offset > 0 {
+			q.Offset = offset
+		}
+	}
+	if q.Page*q.Offset > db.EtherscanMaxResults {
+		return q, fmt.Sprintf("Result window is too large, PageNo x Offset size must be less than or equal to %d", db.EtherscanMaxResults)
+	}
+
+	switch strings.ToLower(c.DefaultQuery("sort", "asc")) {
+	case "asc":
+	case "desc":
+		q.Descending = true
+	default:
+		return q, "Error! Invalid sort order"
+	}
+	return q, ""
+}
+
+func etherscanBalance(c *gin.Context) {
+	address, ok := etherscanAddressParam(c, "address")
+	if !ok {
+		etherscanError(c, "Error! Invalid address format")
+		return
+	}
+	balance, err := db.GetEtherscanBalance(address)
+	if err != nil {
+		etherscanFailed(c, "balance", err)
+		return
+	}
+	etherscanOK(c, balance)
+}
+
+func etherscanTxList(c *gin.Context) {
+	address, ok := etherscanAddressParam(c, "address")
+	if !ok {
+		etherscanError(c, "Error! Invalid address format")
+		return
+	}
+	q, message := etherscanListQuery(c)
+	if message != "" {
+		etherscanError(c, message)
+		return
+	}
+	q.Address = address
+
+	txs, err := db.GetEtherscanTransactions(q)
+	if err != nil {
+		etherscanFailed(c, "txlist", err)
+		return
+	}
+	etherscanList(c, len(txs), txs)
+}
+
+func etherscanTxListInternal(c *gin.Context) {
+	q, message := etherscanListQuery(c)
+	if message != "" {
+		etherscanError(c, message)
+		return
+	}
+	if txHash := strings.ToLower(strings.TrimSpace(c.Query("txhash"))); txHash != "" {
+		if len(txHash) != 66 || !strings.HasPrefix(txHash, "0x") {
+			etherscanError(c, "Error! Invalid transaction hash")
+			return
+		}
+		q.TxHash = txHash
+	} else {
+		address, ok := etherscanAddressParam(c, "address")
+		if !ok {
+			etherscanError(c, "Error! Invalid address format")
+			return
+		}
+		q.Address = address
+	}
+
+	txs, err := db.GetEtherscanInternalTransactions(q)
+	if err != nil {
+		etherscanFailed(c, "txlistinternal", err)
+		return
+	}
+	etherscanList(c, len(txs), txs)
+}
+
+func etherscanTokenTx(c *gin.Context) {
+	q, message := etherscanListQuery(c)
+	if message != "" {
+		etherscanError(c, message)
+		return
+	}
+	if c.Query("address") != "" {
+		address, ok := etherscanAddressParam(c, "address")
+		if !ok {
+			etherscanError(c, "Error! Invalid address format")
+			return
+		}
+		q.Address = address
+	}
+	if c.Query("contractaddress") != "" {
+		contract, ok := etherscanAddressParam(c, "contractaddress")
+		if !ok {
+			etherscanError(c, "Error! Invalid contract address format")
+			return
+		}
+		q.ContractAddress = contract
+	}
+	if q.Address == "" && q.ContractAddress == "" {
+		etherscanError(c, "Error! Missing address or contractaddress")
+		return
+	}
+
+	transfers, err := db.GetEtherscanTokenTransfers(q)
+	if err != nil {
+		etherscanFailed(c, "tokentx", err)
+		return
+	}
+	etherscanList(c, len(transfers), transfers)
+}
+
+func etherscanTokenBalance(c *gin.Context) {
+	contract, ok := etherscanAddressParam(c, "contractaddress")
+	if !ok {
+		etherscanError(c, "Error! Invalid contract address format")
+		return
+	}
+	address, ok := etherscanAddressParam(c, "address")
+	if !ok {
+		etherscanError(c, "Error! Invalid address format")
+		return
+	}
+	balance, err := db.GetEtherscanTokenBalance(contract, address)
+	if err != nil {
+		etherscanFailed(c, "tokenbalance", err)
+		return
+	}
+	etherscanOK(c, balance)
+}
+
+func etherscanBlockNoByTime(c *gin.Context) {
+	timestamp, err := strconv.ParseInt(c.Query("timestamp"), 10, 64)
+	if err != nil || timestamp < 0 {
+		etherscanError(c, "Error! Invalid timestamp")
+		return
+	}
+	var after bool
+	switch c.Query("closest") {
+	case "before":
+	case "after":
+		after = true
+	default:
+		etherscanError(c, "Error! Invalid closest value, use before or after")
+		return
+	}
+
+	number, err := db.GetBlockNumberByTime(timestamp, after)
+	if err == db.ErrNoClosestBlock {
+		etherscanError(c, "Error! No closest block found")
+		return
+	}
+	if err != nil {
+		etherscanFailed(c, "getblocknobytime", err)
+		return
+	}
+	etherscanOK(c, strconv.FormatInt(number, 10))
+}
+
+// etherscanNotVerified is Etherscan's answer for contracts without a verified source
+const etherscanNotVerified = "Contract source code not verified"
+
+func etherscanGetABI(c *gin.Context) {
+	address, ok := etherscanAddressParam(c, "address")
+	if !ok {
+		etherscanError(c, "Error! Invalid address format")
+		return
+	}
+	contract, err := db.GetVerifiedContract(address)
+	if err != nil {
+		etherscanFailed(c, "getabi", err)
+		return
+	}
+	if contract == nil || contract.ABI == "" {
+		etherscanError(c, etherscanNotVerified)
+		return
+	}
+	etherscanOK(c, contract.ABI)
+}
+
+// etherscanGetSourceCode answers with the verified source of a contract. Like
+// Etherscan, addresses without one get an empty entry whose ABI says so.
+func etherscanGetSourceCode(c *gin.Context) {
+	address, ok := etherscanAddressParam(c, "address")
+	if !ok {
+		etherscanError(c, "Error! Invalid address format")
+		return
+	}
+	contract, err := db.GetVerifiedContract(address)
+	if err != nil {
+		etherscanFailed(c, "getsourcecode", err)
+		return
+	}
+	entry := models.EtherscanSourceCode{
+		ABI:         etherscanNotVerified,
+		EVMVersion:  "Default",
+		LicenseType: "Unknown",
+		Proxy:       "0",
+	}
+	if contract != nil && contract.ABI != "" {
+		entry.SourceCode = contract.SourceCode
+		entry.ABI = contract.ABI
+		entry.ContractName = contract.ContractName
+		entry.CompilerVersion = contract.CompilerVersion
+		entry.OptimizationUsed = contract.OptimizationUsed
+		entry.Runs = contract.Runs
+		entry.ConstructorArguments = contract.ConstructorArguments
+		if contract.EVMVersion != "" {
+			entry.EVMVersion = contract.EVMVersion
+		}
+		if contract.LicenseType != "" {
+			entry.LicenseType = contract.LicenseType
+		}
+	}
+	etherscanOK(c, []models.EtherscanSourceCode{entry})
+}
+
+func etherscanSupply(c *gin.Context) {
+	supply, err := db.GetSupply()
+	if err != nil {
+		etherscanFailed(c, "ethsupply", err)
+		return
+	}
+	if supply == nil {
+		etherscanError(c, "Error! Supply has not been computed yet")
+		return
+	}
+	etherscanOK(c, supply.CurrentSupply)
+}
+
+func etherscanPrice(c *gin.Context) {
+	price, err := db.GetEtherscanPrice()
+	if err != nil {
+		etherscanFailed(c, "ethprice", err)
+		return
+	}
+	if price == nil {
+		etherscanError(c, "Error! Price is not available yet")
+		return
+	}
+	etherscanOK(c, price)
+}
@@ -0,0 +1,285 @@
+package routes
+
+import (
+	"backendAPI/db"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Export output formats
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+const (
+	// exportTimeout caps how long a single export may stream
+	exportTimeout = 10 * time.Minute
+	// exportFlushRows is the number of rows written between flushes to the client
+	exportFlushRows = 200
+	// exportMaxConcurrent caps the exports streaming at once
+	exportMaxConcurrent = 8
+)
+
+// exportSlots holds one token per running export
+var exportSlots = make(chan struct{}, exportMaxConcurrent)
+
+// exportFunc streams rows of an export through emit
+type exportFunc func(ctx context.Context, address string, r db.ExportRange, emit func([]string) error) error
+
+// ExportRoute registers the endpoints that stream address and token activity as CSV or NDJSON
+func ExportRoute(router *gin.Engine) {
+	export := router.Group("/export")
+
+	export.GET("/address/:address/transactions",
+		exportHandler("transactions", db.AddressTransactionColumns, db.ExportAddressTransactions))
+	export.GET("/address/:address/internal-transactions",
+		exportHandler("internal-transactions", db.InternalTransactionColumns, db.ExportInternalTransactions))
+	export.GET("/token/:address/transfers",
+		exportHandler("token-transfers", db.TokenTransferColumns, db.ExportTokenTransfers))
+	export.GET("/token/:address/holders",
+		exportHandler("token-holders", db.TokenHolderColumns, db.ExportTokenHolders))
+}
+
+// exportHandler validates the request and streams the rows of export to the
+// client. Headers are only sent with the first row, so failures before any
+// output still produce a JSON error.
+func exportHandler(name string, columns []string, export exportFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address := strings.TrimSpace(c.Param("address"))
+		if len(address) != 41 || !strings.HasPrefix(strings.ToLower(address), "z") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address format"})
+			return
+		}
+
+		format := strings.ToLower(c.DefaultQuery("format", exportFormatCSV))
+		if format != exportFormatCSV && format != exportFormatNDJSON {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, use csv or ndjson"})
+			return
+		}
+
+		r, err := parseExportRange(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Exports hold a cursor for up to exportTimeout, so the server refuses
+		// new ones rather than queueing them once every slot is taken
+		select {
+		case exportSlots <- struct{}{}:
+			defer func() { <-exportSlots }()
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "too many exports running, try again later"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), exportTimeout)
+		defer cancel()
+
+		filename := fmt.Sprintf("%s-%s.%s", strings.ToLower(address), name, format)
+		writer := newExportWriter(c, format, columns, filename)
+		err = export(ctx, address, r, writer.write)
+		if err == nil {
+			err = writer.close()
+		}
+		if err != nil {
+			if !writer.started {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": fmt.Sprintf("Failed to export %s: %v", name, err),
+				})
+				return
+			}
+			// The response is already streaming, so the export is cut short
+			// after the rows written so far
+			writer.flush()
+			log.Printf("Export of %s for %s stopped: %v", name, address, err)
+		}
+	}
+}
+
+// parseExportRange reads the fromBlock, toBlock, fromDate and toDate query
+// parameters. Dates are YYYY-MM-DD in UTC or Unix seconds; a toDate day is inclusive.
+func parseExportRange(c *gin.Context) (db.ExportRange, error) {
+	var r db.ExportRange
+
+	parseBlock := func(key string) (*uint64, error) {
+		value := c.Query(key)
+		if value == "" {
+			return nil, nil
+		}
+		number, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", key, value)
+		}
+		return &number, nil
+	}
+	parseDate := func(key string, endOfDay bool) (*int64, error) {
+		value := c.Query(key)
+		if value == "" {
+			return nil, nil
+		}
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return &seconds, nil
+		}
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q, use YYYY-MM-DD or Unix seconds", key, value)
+		}
+		if endOfDay {
+			day = day.Add(24*time.Hour - time.Second)
+		}
+		seconds := day.Unix()
+		return &seconds, nil
+	}
+
+	var err error
+	if r.FromBlock, err = parseBlock("fromBlock"); err != nil {
+		return r, err
+	}
+	if r.ToBlock, err = parseBlock("toBlock"); err != nil {
+		return r, err
+	}
+	if r.FromTime, err = parseDate("fromDate", false); err != nil {
+		return r, err
+	}
+	if r.ToTime, err = parseDate("toDate", true); err != nil {
+		return r, err
+	}
+	if r.FromBlock != nil && r.ToBlock != nil && *r.FromBlock > *r.ToBlock {
+		return r, fmt.Errorf("fromBlock must not be greater than toBlock")
+	}
+	if r.FromTime != nil && r.ToTime != nil && *r.FromTime > *r.ToTime {
+		return r, fmt.Errorf("fromDate must not be after toDate")
+	}
+	return r, nil
+}
+
+// exportWriter writes export rows as CSV or NDJSON, flushing as it goes
+type exportWriter struct {
+	c        *gin.Context
+	format   string
+	columns  []string
+	filename string
+	csv      *csv.Writer
+	rows     int
+	started  bool
+}
+
+func newExportWriter(c *gin.Context, format string, columns []string, filename string) *exportWriter {
+	return &exportWriter{c: c, format: format, columns: columns, filename: filename}
+}
+
+// start sends the response headers and, for CSV, the header row
+func (w *exportWriter) start() error {
+	w.started = true
+	contentType := "application/x-ndjson"
+	if w.format == exportFormatCSV {
+		contentType = "text/csv; charset=utf-8"
+	}
+	w.c.Header("Content-Type", contentType)
+	w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.filename))
+	w.c.Status(http.StatusOK)
+
+	if w.format == exportFormatCSV {
+		w.csv = csv.NewWriter(w.c.Writer)
+		return w.csv.Write(w.columns)
+	}
+	return nil
+}
+
+func (w *exportWriter) write(row []string) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	if w.format == exportFormatCSV {
+		if err := w.csv.Write(csvSafeRow(row)); err != nil {
+			return err
+		}
+	} else {
+		line, err := ndjsonLine(w.columns, row)
+		if err != nil {
+			return err
+		}
+		if _, err := w.c.Writer.Write(line); err != nil {
+			return err
+		}
+	}
+
+	w.rows++
+	if w.rows%exportFlushRows == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+// close starts empty exports so they still carry headers, then flushes the rest
+func (w *exportWriter) close() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+func (w *exportWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// csvSafeRow prefixes cells that spreadsheets would evaluate as formulas with
+// a quote, since token symbols and other stored strings are user-controlled
+func csvSafeRow(row []string) []string {
+	safe := make([]string, len(row))
+	for i, cell := range row {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cell = "'" + cell
+		}
+		safe[i] = cell
+	}
+	return safe
+}
+
+// ndjsonLine encodes a row as a JSON object keyed by column, keeping column order
+func ndjsonLine(columns []string, row []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(row[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,171 @@
+package routes
+
+import (
+	"backendAPI/db"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCsvSafeRow(t *testing.T) {
+	tests := []struct {
+		name string
+		cell string
+		want string
+	}{
+		{name: "formula", cell: "=HYPERLINK(\"http://x\")", want: "'=HYPERLINK(\"http://x\")"},
+		{name: "plus", cell: "+1", want: "'+1"},
+		{name: "minus", cell: "-1", want: "'-1"},
+		{name: "at", cell: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", cell: "\tx", want: "'\tx"},
+		{name: "carriage return", cell: "\rx", want: "'\rx"},
+		{name: "plain", cell: "QRL", want: "QRL"},
+		{name: "number", cell: "1000", want: "1000"},
+		{name: "inner equals", cell: "a=b", want: "a=b"},
+		{name: "empty", cell: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := csvSafeRow([]string{tt.cell})
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("csvSafeRow(%q) = %q, wanted %q", tt.cell, got, tt.want)
+			}
+		})
+	}
+}
+
+const testExportAddress = "z2b3a0a4f0e0f7b8d5c2e1a9f6d4c3b2a1e0f9d8c"
+
+// serveExport runs one export request against a handler streaming rows from export
+func serveExport(t *testing.T, query string, export exportFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/export/:address", exportHandler("test", []string{"txHash", "symbol"}, export))
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/export/"+testExportAddress+query, nil)
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// emitRows returns an export that emits n rows and then fails with err
+func emitRows(n int, err error) exportFunc {
+	return func(ctx context.Context, address string, r db.ExportRange, emit func([]string) error) error {
+		for i := 0; i < n; i++ {
+			if err := emit([]string{"0x" + strconv.Itoa(i), "=cmd"}); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func TestExportStreamsCSV(t *testing.T) {
+	rows := exportFlushRows*2 + 5
+	recorder := serveExport(t, "", emitRows(rows, nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d, wanted %d", recorder.Code, http.StatusOK)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("got content type %q", got)
+	}
+	if got := recorder.Header().Get("Content-Disposition"); !strings.Contains(got, testExportAddress+"-test.csv") {
+		t.Errorf("got content disposition %q", got)
+	}
+	if !recorder.Flushed {
+		t.Error("export was not flushed")
+	}
+
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(records) != rows+1 {
+		t.Fatalf("got %d records, wanted header and %d rows", len(records), rows)
+	}
+	if !reflect.DeepEqual(records[0], []string{"txHash", "symbol"}) {
+		t.Errorf("got header %q", records[0])
+	}
+	if !reflect.DeepEqual(records[rows], []string{"0x" + strconv.Itoa(rows-1), "'=cmd"}) {
+		t.Errorf("got last row %q", records[rows])
+	}
+}
+
+func TestExportStreamsNDJSON(t *testing.T) {
+	recorder := serveExport(t, "?format=ndjson", emitRows(3, nil))
+
+	lines := strings.Split(strings.TrimSuffix(recorder.Body.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, wanted 3", len(lines))
+	}
+	var row map[string]string
+	if err := json.Unmarshal([]byte(lines[2]), &row); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", lines[2], err)
+	}
+	if row["txHash"] != "0x2" || row["symbol"] != "=cmd" {
+		t.Errorf("got row %v", row)
+	}
+}
+
+func TestExportEmptyHasHeader(t *testing.T) {
+	recorder := serveExport(t, "", emitRows(0, nil))
+
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "txHash,symbol\n" {
+		t.Errorf("got status %d and body %q", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestExportFailures(t *testing.T) {
+	failed := errors.New("cursor failed")
+
+	// Nothing was sent yet, so the client gets a JSON error
+	recorder := serveExport(t, "", emitRows(0, failed))
+	if recorder.Code != http.StatusInternalServerError || !strings.Contains(recorder.Body.String(), "cursor failed") {
+		t.Errorf("got status %d and body %q before output", recorder.Code, recorder.Body.String())
+	}
+
+	// The CSV is already streaming, so it is cut short
+	recorder = serveExport(t, "", emitRows(2, failed))
+	if recorder.Code != http.StatusOK || strings.Count(recorder.Body.String(), "\n") != 3 {
+		t.Errorf("got status %d and body %q after output", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestExportRejectsInvalidRequests(t *testing.T) {
+	for _, query := range []string{"?format=xml", "?fromBlock=x", "?fromBlock=10&toBlock=5", "?fromDate=2024-13-01"} {
+		recorder := serveExport(t, query, emitRows(1, nil))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, wanted %d", query, recorder.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestExportConcurrencyCap(t *testing.T) {
+	for i := 0; i < exportMaxConcurrent; i++ {
+		exportSlots <- struct{}{}
+	}
+	recorder := serveExport(t, "", emitRows(1, nil))
+	for i := 0; i < exportMaxConcurrent; i++ {
+		<-exportSlots
+	}
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("got status %d with every slot taken, wanted %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+
+	recorder = serveExport(t, "", emitRows(1, nil))
+	if recorder.Code != http.StatusOK || len(exportSlots) != 0 {
+		t.Errorf("got status %d and %d slots held after the export", recorder.Code, len(exportSlots))
+	}
+}
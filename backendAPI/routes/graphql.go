@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"backendAPI/graph"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/errors"
+)
+
+// graphqlTimeout bounds the time spent answering one query
+const graphqlTimeout = 30 * time.Second
+
+// graphqlRequest is the body of a GraphQL request
+type graphqlRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLRoute registers /graphql, a read-only GraphQL API over blocks,
+// transactions, addresses, tokens and validators. Each request gets its own
+// loaders so nested lists are resolved with batched queries.
+func GraphQLRoute(router *gin.Engine) {
+	schema := graph.NewSchema()
+
+	router.POST("/graphql", func(c *gin.Context) {
+		var request graphqlRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "request body must be JSON with a query"})
+			return
+		}
+
+		// Costly queries are refused before any resolver runs
+		if err := graph.CheckCost(schema, request.Query, request.OperationName, request.Variables); err != nil {
+			c.JSON(http.StatusOK, &graphql.Response{Errors: []*errors.QueryError{errors.Errorf("%v", err)}})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(graph.WithLoaders(c.Request.Context()), graphqlTimeout)
+		defer cancel()
+
+		response := schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+		c.JSON(http.StatusOK, response)
+	})
+}
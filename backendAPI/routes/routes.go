@@ -113,10 +113,10 @@ func UserRoute(router *gin.Engine) {
 		// Get wallet count with default value
 		walletCount := db.GetWalletCount()
 
-		// Get circulating supply with default value
+		// Get circulating supply, "0" until the syncer has computed it
 		circulating := db.ReturnTotalCirculatingSupply()
 		if circulating == "" {
-			circulating = "65000000" // Default value when no data is available
+			circulating = "0"
 		}
 
 		// Get daily transaction volume with default value
@@ -137,17 +137,33 @@ func UserRoute(router *gin.Engine) {
 		// Get 24h trading volume
 		tradingVolume := db.GetCurrentVolume()
 
+		// Get base fees burnt network-wide and by the latest block
+		burntFees, err := db.GetTotalBurntFees()
+		if err != nil {
+			log.Printf("Error fetching burnt fee total: %v", err)
+		}
+		latestBlockBurnt := "0"
+		if latest, err := db.GetLatestBlockFromSyncState(); err == nil {
+			if blockNum, err := strconv.ParseUint(strings.TrimPrefix(latest, "0x"), 16, 64); err == nil {
+				if block, err := db.ReturnSingleBlock(blockNum); err == nil {
+					latestBlockBurnt = db.GetBlockFees(block.Result).BurntFee
+				}
+			}
+		}
+
 		// Return response with default values if data isn't available
 
 		c.JSON(http.StatusOK, gin.H{
-			"marketcap":      marketCap,      // Returns 0 if not available
-			"currentPrice":   currentPrice,   // Returns 0 if not available
-			"countwallets":   walletCount,    // Returns 0 if not available
-			"circulating":    circulating,    // Returns "0" if not available
-			"volume":         volume,         // Returns 0 if not available
-			"tradingVolume":  tradingVolume,  // 24h trading volume from CoinGecko
-			"validatorCount": validatorCount, // Returns 0 if not available
-			"contractCount":  contractCount,  // Returns 0 if not available
+			"marketcap":            marketCap,        // Returns 0 if not available
+			"currentPrice":         currentPrice,     // Returns 0 if not available
+			"countwallets":         walletCount,      // Returns 0 if not available
+			"circulating":          circulating,      // Returns "0" if not available
+			"volume":               volume,           // Returns 0 if not available
+			"tradingVolume":        tradingVolume,    // 24h trading volume from CoinGecko
+			"validatorCount":       validatorCount,   // Returns 0 if not available
+			"contractCount":        contractCount,    // Returns 0 if not available
+			"burntFees":            burntFees,        // Total base fees burnt in wei
+			"latestBlockBurntFees": latestBlockBurnt, // Base fees burnt by the latest block in wei
 			"status": gin.H{
 				"syncing":         true, // Indicate that data is still being synced
 				"dataInitialized": marketCap > 0 || currentPrice > 0 || walletCount > 0 || circulating != "0" || volume > 0,
@@ -210,17 +226,28 @@ func UserRoute(router *gin.Engine) {
 	})
 
 	router.GET("/txs", func(c *gin.Context) {
-		pageStr := c.Query("page")
-
-		page, err := strconv.Atoi(pageStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("Invalid page number: %v", err),
-			})
+		cursor, ok := pageCursor(c)
+		if !ok {
 			return
 		}
 
-		txs, err := db.ReturnTransactionsNetwork(page)
+		page := 0
+		if cursor == nil {
+			var err error
+			page, err = strconv.Atoi(c.Query("page"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": fmt.Sprintf("Invalid page number: %v", err),
+				})
+				return
+			}
+		}
+
+		txs, nextCursor, err := db.ReturnTransactionsNetwork(page, cursor)
+		if err == db.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("Failed to fetch transactions: %v", err),
@@ -265,6 +292,8 @@ func UserRoute(router *gin.Engine) {
 			"txs":         txs,
 			"total":       countTransactions,
 			"latestBlock": latestBlockNum,
+			"nextCursor":  nextCursor,
+			"labels":      db.GetTransactionLabels(txs),
 		})
 	})
 
@@ -341,6 +370,18 @@ func UserRoute(router *gin.Engine) {
 			}
 		}
 
+		// Validators withdrawing to the address
+		withdrawalValidators, err := db.GetValidatorsByWithdrawalAddress(param)
+		if err != nil {
+			fmt.Printf("Error getting validators by withdrawal address: %v\n", err)
+		}
+
+		// Public labels of the address and its counterparties
+		labels := db.GetTransactionLabels(transactionsByAddress)
+		for address, label := range db.GetPublicLabels(param) {
+			labels[address] = label
+		}
+
 		// Response aggregation
 		c.JSON(http.StatusOK, gin.H{
 			"address":                          addressData,
@@ -349,7 +390,9 @@ func UserRoute(router *gin.Engine) {
 			"transactions_by_address":          transactionsByAddress,
 			"internal_transactions_by_address": internalTransactionsByAddress,
 			"contract_code":                    contractCodeData,
+			"validators":                       withdrawalValidators,
 			"latestBlock":                      latestBlockNum,
+			"labels":                           labels,
 		})
 	})
 
@@ -391,11 +434,41 @@ func UserRoute(router *gin.Engine) {
 			log.Printf("Error checking for token transfer tx %s: %v", value, err)
 		}
 
+		// EIP-1559 fee breakdown recorded by the syncer from the receipt
+		fees, err := db.GetTransactionFees(value)
+		if err != nil {
+			log.Printf("Error fetching fees for tx %s: %v", value, err)
+		}
+
+		// Full receipt stored by the syncer (status, gas, logs)
+		receipt, err := db.GetReceipt(value)
+		if err != nil {
+			log.Printf("Error fetching receipt for tx %s: %v", value, err)
+		}
+
+		// Why the transaction failed, when its receipt status is 0x0
+		revertReason, err := db.GetRevertReason(value)
+		if err != nil {
+			log.Printf("Error fetching revert reason for tx %s: %v", value, err)
+		}
+
 		response := gin.H{
 			"response":    query,
 			"latestBlock": latestBlockNum,
 		}
 
+		if fees != nil {
+			response["fees"] = fees
+		}
+
+		if receipt != nil {
+			response["receipt"] = receipt
+		}
+
+		if revertReason != nil {
+			response["revertReason"] = revertReason
+		}
+
 		if contractCreated != nil {
 			response["contractCreated"] = gin.H{
 				"address":  contractCreated.ContractAddress,
@@ -459,7 +532,84 @@ func UserRoute(router *gin.Engine) {
 	})
 
 	router.GET("/richlist", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"richlist": db.ReturnRichlist()})
+		richlist := db.ReturnRichlist()
+		addresses := make([]string, 0, len(richlist))
+		for _, address := range richlist {
+			addresses = append(addresses, address.ID)
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"richlist": richlist,
+			"labels":   db.GetPublicLabels(addresses...),
+		})
+	})
+
+	// Classify a query and return ranked, typed matches with prefix autocomplete
+	router.GET("/search", func(c *gin.Context) {
+		query := strings.TrimSpace(c.Query("q"))
+		if query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing search query q"})
+			return
+		}
+		// Public keys are the longest identifiers
+		if len(query) > 6000 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is too long"})
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit <= 0 || limit > 50 {
+			limit = 10
+		}
+
+		results, err := db.Search(query, limit)
+		if err == db.ErrSearchTimeout && len(results) == 0 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Search took too long, please refine the query",
+			})
+			return
+		}
+		if err != nil && len(results) == 0 {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to search: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, models.SearchResponse{Query: query, Results: results})
+	})
+
+	// List public address labels, optionally by category
+	router.GET("/labels", func(c *gin.Context) {
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "0"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if page < 0 {
+			page = 0
+		}
+		if limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		labels, total, err := db.GetLabels(page, limit, c.Query("category"), false)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch labels: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"labels": labels,
+			"total":  total,
+			"page":   page,
+			"limit":  limit,
+		})
+	})
+
+	// Get the public label of an address
+	router.GET("/label/:address", func(c *gin.Context) {
+		labels := db.GetPublicLabels(c.Param("address"))
+		for _, label := range labels {
+			c.JSON(http.StatusOK, gin.H{"label": label})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
 	})
 
 	router.GET("/blocks", func(c *gin.Context) {
@@ -476,7 +626,12 @@ func UserRoute(router *gin.Engine) {
 			limit = 5 // Default to 5 blocks per page
 		}
 
-		blocks, err := db.ReturnLatestBlocks(page, limit)
+		cursor, ok := pageCursor(c)
+		if !ok {
+			return
+		}
+
+		blocks, nextCursor, err := db.ReturnLatestBlocks(page, limit, cursor)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blocks"})
 			return
@@ -496,8 +651,9 @@ func UserRoute(router *gin.Engine) {
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"blocks": blocks,
-			"total":  countBlocks,
+			"blocks":     blocks,
+			"total":      countBlocks,
+			"nextCursor": nextCursor,
 		})
 	})
 
@@ -510,8 +666,21 @@ func UserRoute(router *gin.Engine) {
 	})
 
 	router.GET("/validators", func(c *gin.Context) {
-		pageToken := c.Query("page_token")
-		validatorResponse, err := db.ReturnValidators(pageToken)
+		// page_token is accepted as an alias for page for older clients
+		pageStr := c.Query("page")
+		if pageStr == "" {
+			pageStr = c.DefaultQuery("page_token", "1")
+		}
+		page, err := strconv.ParseInt(pageStr, 10, 64)
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "100"), 10, 64)
+		if err != nil || limit <= 0 {
+			limit = 100
+		}
+
+		validatorResponse, err := db.ReturnValidators(page, limit, c.Query("status"))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("Failed to fetch validators: %v", err),
@@ -534,6 +703,55 @@ func UserRoute(router *gin.Engine) {
 		c.JSON(http.StatusOK, epochInfo)
 	})
 
+	// Get paginated beacon slots, newest first
+	router.GET("/slots", func(c *gin.Context) {
+		page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "25"), 10, 64)
+		if err != nil || limit <= 0 {
+			limit = 25
+		}
+
+		var epoch, proposer *int64
+		if e, err := strconv.ParseInt(c.Query("epoch"), 10, 64); err == nil {
+			epoch = &e
+		}
+		if p, err := strconv.ParseInt(c.Query("proposer"), 10, 64); err == nil {
+			proposer = &p
+		}
+
+		slots, err := db.ReturnSlots(page, limit, c.Query("status"), epoch, proposer)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch slots: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, slots)
+	})
+
+	// Get the slots of a single epoch with proposed/missed/orphaned counts
+	router.GET("/epoch/:n", func(c *gin.Context) {
+		epoch, err := strconv.ParseInt(c.Param("n"), 10, 64)
+		if err != nil || epoch < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid epoch number",
+			})
+			return
+		}
+
+		summary, err := db.GetEpochSummary(epoch)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": fmt.Sprintf("Failed to fetch epoch: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, summary)
+	})
+
 	// Get validator history for charts
 	router.GET("/validators/history", func(c *gin.Context) {
 		limitStr := c.DefaultQuery("limit", "100")
@@ -564,6 +782,230 @@ func UserRoute(router *gin.Engine) {
 		c.JSON(http.StatusOK, stats)
 	})
 
+	// Suggest slow/standard/fast fees for the next block
+	router.GET("/gas/oracle", func(c *gin.Context) {
+		blocks, err := strconv.Atoi(c.DefaultQuery("blocks", "20"))
+		if err != nil || blocks <= 0 || blocks > 1024 {
+			blocks = 20
+		}
+
+		oracle, err := db.GetGasOracle(blocks)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Fee history not available yet"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to compute gas oracle: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, oracle)
+	})
+
+	// Get per-block base fees and priority fee percentiles
+	router.GET("/gas/history", func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit <= 0 || limit > 1024 {
+			limit = 100
+		}
+
+		history, err := db.GetGasHistory(limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch gas history: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, history)
+	})
+
+	// Get hourly or daily network statistics for charts
+	router.GET("/stats/timeseries", func(c *gin.Context) {
+		metric := c.Query("metric")
+		if _, ok := db.TimeSeriesMetrics[metric]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid metric. Use txCount, activeAddresses, newAddresses, gasUsed, avgBlockTime, avgFee, burntFees, contractDeployments or tokenTransfers",
+			})
+			return
+		}
+		interval := c.DefaultQuery("interval", "day")
+		if !db.TimeSeriesIntervals[interval] {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid interval. Use hour or day",
+			})
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "30"))
+		if err != nil || limit <= 0 || limit > 1000 {
+			limit = 30
+		}
+
+		series, err := db.GetTimeSeries(metric, interval, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch time series: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, series)
+	})
+
+	// Get the supply breakdown in wei
+	router.GET("/supply", func(c *gin.Context) {
+		supply, err := db.GetSupply()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch supply: %v", err),
+			})
+			return
+		}
+		if supply == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Supply has not been computed yet",
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"supply": supply,
+			"unit":   "wei",
+		})
+	})
+
+	// Get activation and exit queues with per-validator estimates
+	router.GET("/validators/queue", func(c *gin.Context) {
+		queue, err := db.GetValidatorQueue()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch validator queue: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, queue)
+	})
+
+	// Get network-wide staking rewards and APR
+	router.GET("/validators/apr", func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		apr, err := db.GetNetworkAPR(limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch network APR: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, apr)
+	})
+
+	// Get network attestation participation for charts
+	router.GET("/validators/participation", func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit <= 0 {
+			limit = 100
+		}
+
+		participation, err := db.GetNetworkParticipation(limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch network participation: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, participation)
+	})
+
+	// Get validator state transitions (deposit, activation, exit, slashing, withdrawable)
+	router.GET("/validators/events", func(c *gin.Context) {
+		page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "25"), 10, 64)
+		if err != nil || limit <= 0 {
+			limit = 25
+		}
+
+		events, err := db.ReturnValidatorEvents(page, limit, c.Query("type"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch validator events: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, events)
+	})
+
+	// Get the event timeline of a single validator
+	router.GET("/validator/:id/events", func(c *gin.Context) {
+		page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "25"), 10, 64)
+		if err != nil || limit <= 0 {
+			limit = 25
+		}
+
+		events, err := db.GetValidatorEvents(c.Param("id"), page, limit)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err.Error() == "validator not found" {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{
+				"error": fmt.Sprintf("Failed to fetch validator events: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, events)
+	})
+
+	// Get staking deposit contract events
+	router.GET("/deposits", func(c *gin.Context) {
+		page, err := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+		if err != nil || page < 1 {
+			page = 1
+		}
+		limit, err := strconv.ParseInt(c.DefaultQuery("limit", "25"), 10, 64)
+		if err != nil || limit <= 0 {
+			limit = 25
+		}
+
+		deposits, err := db.ReturnDeposits(page, limit, c.Query("address"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch deposits: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, deposits)
+	})
+
+	// Get rewards, penalties and APR for a single validator
+	router.GET("/validator/:id/rewards", func(c *gin.Context) {
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		if err != nil || limit <= 0 || limit > 1000 {
+			limit = 100
+		}
+
+		rewards, err := db.GetValidatorRewards(c.Param("id"), limit)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if err.Error() == "validator not found" {
+				status = http.StatusNotFound
+			}
+			c.JSON(status, gin.H{
+				"error": fmt.Sprintf("Failed to fetch validator rewards: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, rewards)
+	})
+
 	// Get individual validator details
 	router.GET("/validator/:id", func(c *gin.Context) {
 		id := c.Param("id")
@@ -582,14 +1024,27 @@ func UserRoute(router *gin.Engine) {
 		if err != nil {
 			fmt.Println(err)
 		}
-		c.JSON(http.StatusOK, gin.H{"response": query})
+		c.JSON(http.StatusOK, gin.H{
+			"response": query,
+			"labels":   db.GetTransactionLabels(query),
+		})
 	})
 
 	router.GET("/contracts", func(c *gin.Context) {
 		// Parse pagination parameters
 		page, _ := strconv.ParseInt(c.DefaultQuery("page", "0"), 10, 64)
 		limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "10"), 10, 64)
+		if page < 0 {
+			page = 0
+		}
+		if limit <= 0 || limit > 100 {
+			limit = 10
+		}
 		search := c.Query("search")
+		if len(search) > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Search must be at most 100 characters"})
+			return
+		}
 
 		// Parse isToken filter (optional)
 		var isTokenFilter *bool
@@ -598,7 +1053,22 @@ func UserRoute(router *gin.Engine) {
 			isTokenFilter = &isToken
 		}
 
-		query, total, err := db.ReturnContracts(page, limit, search, isTokenFilter)
+		cursor, ok := pageCursor(c)
+		if !ok {
+			return
+		}
+
+		query, total, nextCursor, err := db.ReturnContracts(page, limit, search, isTokenFilter, cursor)
+		if err == db.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		if err == db.ErrSearchTimeout {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Search took too long, please refine the query",
+			})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"error": fmt.Sprintf("Failed to fetch contracts: %v", err),
@@ -606,8 +1076,9 @@ func UserRoute(router *gin.Engine) {
 			return
 		}
 		c.JSON(http.StatusOK, gin.H{
-			"response": query,
-			"total":    total,
+			"response":   query,
+			"total":      total,
+			"nextCursor": nextCursor,
 		})
 	})
 
@@ -676,8 +1147,16 @@ func UserRoute(router *gin.Engine) {
 			return
 		}
 
+		// Public labels of the fee recipient and the transaction parties
+		addresses := []string{block.Result.Miner}
+		for _, tx := range block.Result.Transactions {
+			addresses = append(addresses, tx.From, tx.To)
+		}
+
 		c.JSON(http.StatusOK, gin.H{
-			"block": block,
+			"block":  block,
+			"fees":   db.GetBlockFees(block.Result),
+			"labels": db.GetPublicLabels(addresses...),
 		})
 	})
 
@@ -687,7 +1166,16 @@ func UserRoute(router *gin.Engine) {
 		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5")) // Default to 5
 
-		transactions, err := db.ReturnNonZeroTransactions(address, page, limit)
+		cursor, ok := pageCursor(c)
+		if !ok {
+			return
+		}
+
+		transactions, nextCursor, err := db.ReturnNonZeroTransactions(address, page, limit, cursor)
+		if err == db.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 		if err != nil {
 			log.Printf("Error fetching non-zero transactions: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -713,9 +1201,23 @@ func UserRoute(router *gin.Engine) {
 			"total":        total,
 			"page":         page,
 			"limit":        limit,
+			"nextCursor":   nextCursor,
+			"labels":       db.GetTransactionLabels(transactions),
 		})
 	})
 
+	// Get the validators withdrawing to an address
+	router.GET("/address/:address/validators", func(c *gin.Context) {
+		validators, err := db.GetValidatorsByWithdrawalAddress(c.Param("address"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Failed to fetch validators: %v", err),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, validators)
+	})
+
 	// Get all token balances for a wallet address
 	// This endpoint is designed for wallet integration (e.g., qrlwallet)
 	// to auto-discover tokens held by an address on import
@@ -764,7 +1266,16 @@ func UserRoute(router *gin.Engine) {
 			limit = 100
 		}
 
-		holders, totalCount, err := db.GetTokenHolders(address, page, limit)
+		cursor, ok := pageCursor(c)
+		if !ok {
+			return
+		}
+
+		holders, totalCount, nextCursor, err := db.GetTokenHolders(address, page, limit, cursor)
+		if err == db.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 		if err != nil {
 			log.Printf("Error fetching token holders for %s: %v", address, err)
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -773,12 +1284,19 @@ func UserRoute(router *gin.Engine) {
 			return
 		}
 
+		holderAddresses := make([]string, 0, len(holders))
+		for _, holder := range holders {
+			holderAddresses = append(holderAddresses, holder.HolderAddress)
+		}
+
 		c.JSON(http.StatusOK, models.TokenHoldersResponse{
 			ContractAddress: address,
 			Holders:         holders,
 			TotalHolders:    totalCount,
 			Page:            page,
 			Limit:           limit,
+			NextCursor:      nextCursor,
+			Labels:          db.GetPublicLabels(holderAddresses...),
 		})
 	})
 
@@ -792,7 +1310,16 @@ func UserRoute(router *gin.Engine) {
 			limit = 100
 		}
 
-		transfers, totalCount, err := db.GetTokenTransfers(address, page, limit)
+		cursor, ok := pageCursor(c)
+		if !ok {
+			return
+		}
+
+		transfers, totalCount, nextCursor, err := db.GetTokenTransfers(address, page, limit, cursor)
+		if err == db.ErrInvalidCursor {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
 		if err != nil {
 			log.Printf("Error fetching token transfers for %s: %v", address, err)
 			c.JSON(http.StatusInternalServerError, gin.H{
@@ -801,12 +1328,30 @@ func UserRoute(router *gin.Engine) {
 			return
 		}
 
+		transferAddresses := make([]string, 0, 2*len(transfers))
+		for _, transfer := range transfers {
+			transferAddresses = append(transferAddresses, transfer.From, transfer.To)
+		}
+
 		c.JSON(http.StatusOK, models.TokenTransfersResponse{
 			ContractAddress: address,
 			Transfers:       transfers,
 			TotalTransfers:  totalCount,
 			Page:            page,
 			Limit:           limit,
+			NextCursor:      nextCursor,
+			Labels:          db.GetPublicLabels(transferAddresses...),
 		})
 	})
 }
+
+// pageCursor reads the optional cursor query parameter of list endpoints,
+// answering 400 and returning false if it is not a valid cursor
+func pageCursor(c *gin.Context) (*db.PageCursor, bool) {
+	cursor, err := db.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return nil, false
+	}
+	return cursor, true
+}
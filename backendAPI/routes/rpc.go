@@ -0,0 +1,170 @@
+package routes
+
+import (
+	"backendAPI/db"
+	"backendAPI/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// rpcMaxBatch is the largest number of calls in one batch request
+	rpcMaxBatch = 20
+	// rpcMaxBodyBytes caps the size of a request body
+	rpcMaxBodyBytes = 1 << 20
+	// rpcDefaultRateLimit is the number of calls a client may make per minute
+	// unless RPC_RATE_LIMIT is set
+	rpcDefaultRateLimit = 300
+	// rpcCallTimeout bounds the time spent answering one request
+	rpcCallTimeout = 30 * time.Second
+)
+
+// RPCRoute registers /rpc, a public read-only JSON-RPC endpoint. Whitelisted
+// zond_* methods are answered from the database where the data is final and
+// stored in full, otherwise forwarded to the node; NODE_URL itself is never
+// exposed. Calls are rate limited per client IP.
+func RPCRoute(router *gin.Engine) {
+	limit := rpcDefaultRateLimit
+	if value := os.Getenv("RPC_RATE_LIMIT"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	limiter := newRPCLimiter(limit, time.Minute)
+
+	router.POST("/rpc", func(c *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, rpcMaxBodyBytes+1))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, rpcError(nil, db.RPCParseError, "failed to read request body"))
+			return
+		}
+		if len(body) > rpcMaxBodyBytes {
+			c.JSON(http.StatusRequestEntityTooLarge, rpcError(nil, db.RPCInvalidRequest, "request body too large"))
+			return
+		}
+
+		body = bytes.TrimSpace(body)
+		batch := len(body) > 0 && body[0] == '['
+		var requests []models.RPCRequest
+		if batch {
+			err = json.Unmarshal(body, &requests)
+		} else {
+			var request models.RPCRequest
+			err = json.Unmarshal(body, &request)
+			requests = append(requests, request)
+		}
+		if err != nil {
+			c.JSON(http.StatusOK, rpcError(nil, db.RPCParseError, "parse error"))
+			return
+		}
+		if len(requests) == 0 {
+			c.JSON(http.StatusOK, rpcError(nil, db.RPCInvalidRequest, "empty batch"))
+			return
+		}
+		if len(requests) > rpcMaxBatch {
+			c.JSON(http.StatusOK, rpcError(nil, db.RPCInvalidRequest, "batch is limited to "+strconv.Itoa(rpcMaxBatch)+" calls"))
+			return
+		}
+
+		// Every call of a batch counts towards the limit
+		if ok, retry := limiter.allow(c.ClientIP(), len(requests)); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(retry.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, rpcError(nil, db.RPCLimitExceeded, "rate limit exceeded"))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), rpcCallTimeout)
+		defer cancel()
+
+		responses := make([]models.RPCResponse, 0, len(requests))
+		for _, request := range requests {
+			responses = append(responses, rpcAnswer(ctx, request))
+		}
+		if batch {
+			c.JSON(http.StatusOK, responses)
+		} else {
+			c.JSON(http.StatusOK, responses[0])
+		}
+	})
+}
+
+// rpcAnswer answers a single call of a request
+func rpcAnswer(ctx context.Context, request models.RPCRequest) models.RPCResponse {
+	if request.Jsonrpc != "2.0" || request.Method == "" {
+		return rpcError(request.ID, db.RPCInvalidRequest, "invalid request")
+	}
+
+	result, rpcErr, err := db.CallRPC(ctx, request.Method, request.Params)
+	if err != nil {
+		log.Printf("RPC %s failed: %v", request.Method, err)
+		return rpcError(request.ID, db.RPCInternalError, "failed to process the request")
+	}
+	if rpcErr != nil {
+		return models.RPCResponse{Jsonrpc: "2.0", ID: request.ID, Error: rpcErr}
+	}
+	return models.RPCResponse{Jsonrpc: "2.0", ID: request.ID, Result: result}
+}
+
+// rpcError builds an error response
+func rpcError(id json.RawMessage, code int, message string) models.RPCResponse {
+	return models.RPCResponse{
+		Jsonrpc: "2.0",
+		ID:      id,
+		Error:   &models.RPCError{Code: code, Message: message},
+	}
+}
+
+// rpcLimiter counts the calls of each client in fixed windows
+type rpcLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	clients map[string]*rpcWindow
+}
+
+type rpcWindow struct {
+	start time.Time
+	count int
+}
+
+func newRPCLimiter(limit int, window time.Duration) *rpcLimiter {
+	return &rpcLimiter{limit: limit, window: window, clients: make(map[string]*rpcWindow)}
+}
+
+// allow records n calls of a client, reporting whether they fit in the
+// client's current window and otherwise how long until it resets
+func (l *rpcLimiter) allow(client string, n int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		// Drop the windows of clients that went quiet before tracking a new one
+		if !ok && len(l.clients) >= 10000 {
+			for key, other := range l.clients {
+				if now.Sub(other.start) >= l.window {
+					delete(l.clients, key)
+				}
+			}
+		}
+		w = &rpcWindow{start: now}
+		l.clients[client] = w
+	}
+
+	if w.count+n > l.limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count += n
+	return true, 0
+}
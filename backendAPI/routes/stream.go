@@ -0,0 +1,269 @@
+package routes
+
+import (
+	"backendAPI/db"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// streamBuffer is the number of events queued for a client before it is
+	// considered too slow and disconnected
+	streamBuffer = 256
+	// streamHeartbeat is the interval of keep-alive comments on idle connections
+	streamHeartbeat = 15 * time.Second
+	// streamMaxFilters caps the addresses and tokens one client may follow
+	streamMaxFilters = 100
+	// streamMaxClients caps the open streams of the server
+	streamMaxClients = 1000
+	// streamMaxClientsPerIP caps the open streams of one client IP
+	streamMaxClientsPerIP = 5
+)
+
+// streamTopics maps the topics clients may request to the topics of events
+var streamTopics = map[string]string{
+	"blocks":         db.StreamTopicBlock,
+	"transactions":   db.StreamTopicTransaction,
+	"pending":        db.StreamTopicPending,
+	"tokenTransfers": db.StreamTopicTokenTransfer,
+}
+
+var (
+	errStreamFull      = errors.New("too many open streams, try again later")
+	errStreamClientCap = errors.New("too many open streams from this client")
+)
+
+// StreamRoute registers /stream, a Server-Sent Events feed of new blocks,
+// transactions, pending transactions and token transfers. Clients pick whole
+// topics and the addresses and tokens whose activity they follow, replacing
+// polling of /latestblock and /pending-transactions.
+func StreamRoute(router *gin.Engine) {
+	hub := newStreamHub()
+
+	router.GET("/stream", func(c *gin.Context) {
+		client, err := newStreamClient(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := hub.subscribe(client); err != nil {
+			status := http.StatusServiceUnavailable
+			if err == errStreamClientCap {
+				status = http.StatusTooManyRequests
+			}
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		defer hub.unsubscribe(client)
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // Keep proxies such as nginx from buffering events
+		c.Status(http.StatusOK)
+		io.WriteString(c.Writer, "retry: 5000\n\n")
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case message := <-client.events:
+				c.SSEvent(message.event, message.data)
+				return true
+			case <-heartbeat.C:
+				io.WriteString(w, ": ping\n\n")
+				return true
+			case <-client.dropped:
+				c.SSEvent("dropped", "client fell behind, reconnect to continue")
+				return false
+			case <-c.Request.Context().Done():
+				return false
+			}
+		})
+	})
+}
+
+// streamMessage is an event encoded once for every client receiving it
+type streamMessage struct {
+	event string
+	data  string
+}
+
+// streamClient is an open stream and the events it follows
+type streamClient struct {
+	ip        string
+	topics    map[string]bool
+	addresses map[string]bool
+	tokens    map[string]bool
+	events    chan streamMessage
+	dropped   chan struct{} // Closed when the client is disconnected for falling behind
+}
+
+// newStreamClient reads the topics, addresses and tokens of a request
+func newStreamClient(c *gin.Context) (*streamClient, error) {
+	client := &streamClient{
+		// Forwarded IPs are only used behind TRUSTED_PROXIES, so clients
+		// cannot escape the per-IP cap by sending their own headers
+		ip:        c.ClientIP(),
+		topics:    make(map[string]bool),
+		addresses: make(map[string]bool),
+		tokens:    make(map[string]bool),
+		events:    make(chan streamMessage, streamBuffer),
+		dropped:   make(chan struct{}),
+	}
+
+	for _, name := range streamList(c.Query("topics")) {
+		topic, ok := streamTopics[name]
+		if !ok {
+			return nil, errors.New("unknown topic " + name + ", expected blocks, transactions, pending or tokenTransfers")
+		}
+		client.topics[topic] = true
+	}
+	for _, address := range streamList(c.Query("addresses")) {
+		normalized, ok := parseAddress(address)
+		if !ok {
+			return nil, errors.New("invalid address " + address)
+		}
+		client.addresses[normalized] = true
+	}
+	for _, token := range streamList(c.Query("tokens")) {
+		normalized, ok := parseAddress(token)
+		if !ok {
+			return nil, errors.New("invalid token address " + token)
+		}
+		client.tokens[normalized] = true
+	}
+
+	if len(client.topics) == 0 && len(client.addresses) == 0 && len(client.tokens) == 0 {
+		return nil, errors.New("specify topics, addresses or tokens to follow")
+	}
+	if len(client.addresses)+len(client.tokens) > streamMaxFilters {
+		return nil, errors.New("too many addresses and tokens, the limit is " + strconv.Itoa(streamMaxFilters))
+	}
+	return client, nil
+}
+
+// wants reports whether an event matches a topic, address or token the client follows
+func (c *streamClient) wants(event db.StreamEvent) bool {
+	if c.topics[event.Topic] {
+		return true
+	}
+	if event.Token != "" && c.tokens[event.Token] {
+		return true
+	}
+	for _, address := range event.Addresses {
+		if c.addresses[address] {
+			return true
+		}
+	}
+	return false
+}
+
+// streamHub fans stored events out to the open streams. It starts watching
+// the database when the first client connects.
+type streamHub struct {
+	start   sync.Once
+	mu      sync.Mutex
+	clients map[*streamClient]bool
+	perIP   map[string]int
+}
+
+func newStreamHub() *streamHub {
+	return &streamHub{
+		clients: make(map[*streamClient]bool),
+		perIP:   make(map[string]int),
+	}
+}
+
+// subscribe adds a client unless the server or its IP has too many streams open
+func (h *streamHub) subscribe(client *streamClient) error {
+	h.start.Do(func() {
+		db.WatchStream(context.Background(), h.publish)
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.clients) >= streamMaxClients {
+		return errStreamFull
+	}
+	if h.perIP[client.ip] >= streamMaxClientsPerIP {
+		return errStreamClientCap
+	}
+	h.clients[client] = true
+	h.perIP[client.ip]++
+	return nil
+}
+
+// unsubscribe removes a client that is still subscribed
+func (h *streamHub) unsubscribe(client *streamClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(client)
+}
+
+// remove drops a client; callers hold mu
+func (h *streamHub) remove(client *streamClient) bool {
+	if !h.clients[client] {
+		return false
+	}
+	delete(h.clients, client)
+	if h.perIP[client.ip]--; h.perIP[client.ip] <= 0 {
+		delete(h.perIP, client.ip)
+	}
+	return true
+}
+
+// publish queues an event for every client following it. Clients whose
+// queue is full are disconnected rather than holding back the others.
+func (h *streamHub) publish(event db.StreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var message *streamMessage
+	for client := range h.clients {
+		if !client.wants(event) {
+			continue
+		}
+		if message == nil {
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				log.Printf("Failed to encode %s event: %v", event.Topic, err)
+				return
+			}
+			message = &streamMessage{event: event.Topic, data: string(data)}
+		}
+
+		select {
+		case client.events <- *message:
+		default:
+			if h.remove(client) {
+				close(client.dropped)
+			}
+		}
+	}
+}
+
+// streamList splits a comma-separated query value
+func streamList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}